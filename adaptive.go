@@ -0,0 +1,94 @@
+package gt3
+
+import "time"
+
+// AdaptiveRender configures automatic render FPS adjustment. When the loop can't sustain the render FPS cap, the cap
+// is lowered in steps, and when there's headroom again it's raised back towards Target. This keeps the fixed sim rate
+// stable under load by trading away render rate first.
+type AdaptiveRender struct {
+	Target int // Highest render FPS
+	Min    int // Lowest render FPS
+	Step   int // FPS change per adjustment; defaults to 10% of Target
+
+	// Samples is the number of rendered loop iterations averaged before each adjustment. Defaults to 30.
+	Samples int
+	// Headroom is the fraction of the next higher rate's frame budget that must be left unused before raising the cap.
+	// Defaults to 0.25.
+	Headroom float64
+}
+
+type adaptiveRender struct {
+	AdaptiveRender
+	fps   int
+	total time.Duration
+	n     int
+	start time.Time // Start of the current loop iteration
+}
+
+// SetAdaptiveRender enables adaptive render FPS with the given configuration, starting at conf.Target. A Target <= 0
+// disables it, leaving the render FPS at its current value. SetAdaptiveRender must be called before Run.
+func (s *Sim) SetAdaptiveRender(conf AdaptiveRender) {
+	if conf.Target <= 0 {
+		s.adaptive = nil
+		return
+	}
+	if conf.Min <= 0 || conf.Min > conf.Target {
+		conf.Min = conf.Target
+	}
+	if conf.Step <= 0 {
+		if conf.Step = conf.Target / 10; conf.Step < 1 {
+			conf.Step = 1
+		}
+	}
+	if conf.Samples <= 0 {
+		conf.Samples = 30
+	}
+	if conf.Headroom <= 0 {
+		conf.Headroom = 0.25
+	}
+	s.adaptive = &adaptiveRender{AdaptiveRender: conf, fps: conf.Target}
+	s.SetRenderFPS(conf.Target)
+}
+
+// AdaptiveFPS returns the render FPS cap currently chosen by adaptive render FPS, or 0 if it's disabled.
+func (s *Sim) AdaptiveFPS() int {
+	if s.adaptive == nil {
+		return 0
+	}
+	return s.adaptive.fps
+}
+
+func budget(fps int) time.Duration {
+	return time.Second / time.Duration(fps)
+}
+
+// rendered records the cost of a loop iteration that rendered and adjusts the render FPS cap once enough iterations
+// have been sampled.
+func (a *adaptiveRender) rendered(s *Sim) {
+	a.total += time.Since(a.start)
+	if a.n++; a.n < a.Samples {
+		return
+	}
+	avg := a.total / time.Duration(a.n)
+	a.total, a.n = 0, 0
+
+	fps := a.fps
+	switch {
+	case avg > budget(fps) && fps > a.Min:
+		if fps -= a.Step; fps < a.Min {
+			fps = a.Min
+		}
+	case fps < a.Target:
+		up := fps + a.Step
+		if up > a.Target {
+			up = a.Target
+		}
+		if float64(avg) < float64(budget(up))*(1-a.Headroom) {
+			fps = up
+		}
+	}
+	if fps != a.fps {
+		a.fps = fps
+		s.SetRenderFPS(fps)
+	}
+}
@@ -0,0 +1,97 @@
+package gt3
+
+import (
+	"runtime/metrics"
+	"sync"
+)
+
+// AllocStats describes the heap allocations made during a single loop iteration.
+type AllocStats struct {
+	Tick  uint64 // Tick at the start of the iteration
+	Ticks uint64 // Number of sim frames run in the iteration
+	Bytes uint64 // Bytes allocated by ops during the iteration
+
+	// Phases holds the bytes allocated by ops in each phase. Phases without allocations are omitted.
+	Phases map[Phase]uint64
+}
+
+// PerTick returns the average bytes allocated by Frame ops per sim frame in the iteration.
+func (a AllocStats) PerTick() uint64 {
+	if a.Ticks == 0 {
+		return 0
+	}
+	return a.Phases[PhaseFrame] / a.Ticks
+}
+
+const allocsMetric = "/gc/heap/allocs:bytes"
+
+type allocTracker struct {
+	sample []metrics.Sample
+	cur    AllocStats
+
+	mu   sync.Mutex
+	last AllocStats
+}
+
+// TrackAllocs enables or disables per-iteration allocation tracking, reported by AllocStats. Allocations are measured
+// around every op using runtime/metrics, which, unlike runtime.ReadMemStats, doesn't stop the world, but tracking still
+// adds a small cost to every op. Allocations made by other goroutines while an op runs are included in its count.
+// TrackAllocs must be called before Run.
+func (s *Sim) TrackAllocs(enable bool) {
+	if !enable {
+		s.allocs = nil
+		return
+	}
+	s.allocs = &allocTracker{sample: []metrics.Sample{{Name: allocsMetric}}}
+}
+
+// AllocStats returns the allocation statistics of the last completed loop iteration. It returns the zero AllocStats
+// if allocation tracking is disabled. AllocStats may be called from any goroutine.
+func (s *Sim) AllocStats() AllocStats {
+	a := s.allocs
+	if a == nil {
+		return AllocStats{}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := a.last
+	stats.Phases = make(map[Phase]uint64, len(a.last.Phases))
+	for p, n := range a.last.Phases {
+		stats.Phases[p] = n
+	}
+	return stats
+}
+
+func (a *allocTracker) read() uint64 {
+	metrics.Read(a.sample)
+	if a.sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return a.sample[0].Value.Uint64()
+}
+
+func (a *allocTracker) begin(tick uint64) {
+	phases := a.cur.Phases
+	for p := range phases {
+		delete(phases, p)
+	}
+	a.cur = AllocStats{Tick: tick, Phases: phases}
+}
+
+func (a *allocTracker) op(phase Phase, n uint64) {
+	if n == 0 {
+		return
+	}
+	if a.cur.Phases == nil {
+		a.cur.Phases = make(map[Phase]uint64)
+	}
+	a.cur.Phases[phase] += n
+	a.cur.Bytes += n
+}
+
+func (a *allocTracker) end(tick uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cur.Ticks = tick - a.cur.Tick
+	a.last, a.cur.Phases = a.cur, a.last.Phases
+}
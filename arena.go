@@ -0,0 +1,93 @@
+package gt3
+
+import "reflect"
+
+// Arena is a frame-scoped allocator for transient slices, such as particle buffers, event batches, and render command
+// lists. Slices allocated from an Arena remain valid until the Arena is reset, after which their memory is reused. A
+// Sim resets its Arena at the start of every loop iteration, before PreFrame, and before every sim frame after the
+// first in an iteration. Slices allocated by PreFrame remain valid for the first frame, and slices allocated by the
+// last Frame op of an iteration remain valid for rendering, but slices must never be kept past the next tick.
+//
+// The zero value is an empty Arena ready for use. An Arena must only be used from one goroutine; a Sim's Arena must
+// only be used from the main goroutine.
+type Arena struct {
+	pools map[reflect.Type]arenaPool
+}
+
+type arenaPool interface {
+	reset()
+}
+
+type typedPool[T any] struct {
+	chunk []T
+	used  int
+	spill int // Elements allocated outside of chunk since the last reset
+}
+
+func (p *typedPool[T]) reset() {
+	// Grow the chunk to fit everything allocated since the last reset, so that steady-state ticks don't spill.
+	if p.spill > 0 {
+		p.chunk = make([]T, 2*(p.used+p.spill))
+	}
+	p.used, p.spill = 0, 0
+}
+
+// arenaMinChunk is the minimum number of elements in a pool's first chunk.
+const arenaMinChunk = 64
+
+// MakeSlice returns a zeroed slice of T with length and capacity n, allocated from a. If a is nil, the slice is
+// allocated normally.
+func MakeSlice[T any](a *Arena, n int) []T {
+	if a == nil || n <= 0 {
+		return make([]T, n)
+	}
+
+	typ := reflect.TypeOf((*T)(nil))
+	p, _ := a.pools[typ].(*typedPool[T])
+	if p == nil {
+		if a.pools == nil {
+			a.pools = make(map[reflect.Type]arenaPool)
+		}
+		p = &typedPool[T]{}
+		a.pools[typ] = p
+	}
+
+	if p.chunk == nil {
+		size := arenaMinChunk
+		for size < n {
+			size *= 2
+		}
+		p.chunk = make([]T, size)
+	}
+
+	if p.used+n > len(p.chunk) {
+		p.spill += n
+		return make([]T, n)
+	}
+
+	s := p.chunk[p.used : p.used+n : p.used+n]
+	p.used += n
+	var zero T
+	for i := range s {
+		s[i] = zero
+	}
+	return s
+}
+
+// Bytes returns a zeroed byte slice of length n allocated from a.
+func (a *Arena) Bytes(n int) []byte {
+	return MakeSlice[byte](a, n)
+}
+
+// Reset releases all slices allocated from a for reuse.
+func (a *Arena) Reset() {
+	for _, p := range a.pools {
+		p.reset()
+	}
+}
+
+// Arena returns the Sim's frame arena. It is also passed to ops as
+// OpContext.Arena.
+func (s *Sim) Arena() *Arena {
+	return &s.arena
+}
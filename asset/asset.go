@@ -0,0 +1,519 @@
+// Package asset loads assets such as textures and files in the background. Decoding and parsing run on worker
+// goroutines, and anything that needs the GL context, such as texture uploads, is scheduled to run on a Sim's main
+// goroutine with Sched. Assets are shared by key and reference counted, so scenes loading the same texture share one
+// copy, which is freed once every scene has released it.
+package asset
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register JPEG decoding for Texture
+	_ "image/png"  // Register PNG decoding for Texture
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/gfx"
+)
+
+// State is the load state of an asset.
+type State int32
+
+// Load states.
+const (
+	Loading  State = iota // Loading or waiting to be finalized
+	Ready                 // Loaded successfully
+	Failed                // Loading or finalizing failed
+	Released              // Every reference was released
+)
+
+func (s State) String() string {
+	switch s {
+	case Loading:
+		return "loading"
+	case Ready:
+		return "ready"
+	case Failed:
+		return "failed"
+	case Released:
+		return "released"
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+// Manager loads and tracks assets for a Sim.
+type Manager struct {
+	sim *gt3.Sim
+	sem chan struct{} // Limits concurrent loads
+
+	mu       sync.Mutex
+	assets   map[string]reloader // *Handle[T] by key
+	files    map[string][]string // Keys by the cleaned, absolute paths of the files they're loaded from
+	total    int                 // Loads started
+	finished int                 // Loads finished, successfully or not
+	progress func(finished, total int)
+	onTrack  func(path string)
+	events   gt3.EventHandler
+}
+
+// ReloadedEvent is posted to a Manager's event handler on the main goroutine when an asset has been reloaded by
+// Reload. If reloading failed, Err is set and the asset keeps its previous value.
+type ReloadedEvent struct {
+	gt3.CustomEvent
+	Key string
+	Err error
+}
+
+type reloader interface {
+	reload()
+}
+
+// NewManager returns a Manager that finalizes assets on s's main goroutine and runs up to workers loads at once. If
+// workers is <= 0, runtime.GOMAXPROCS(0) is used.
+func NewManager(s *gt3.Sim, workers int) *Manager {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Manager{
+		sim:    s,
+		sem:    make(chan struct{}, workers),
+		assets: make(map[string]reloader),
+		files:  make(map[string][]string),
+	}
+}
+
+// SetProgressFunc sets a function called on the main goroutine each time a load finishes, with the number of loads
+// finished and started so far, such as to drive a loading screen. Loads of shared assets are counted once.
+func (m *Manager) SetProgressFunc(fn func(finished, total int)) {
+	m.mu.Lock()
+	m.progress = fn
+	m.mu.Unlock()
+}
+
+// SetEventHandler sets the handler ReloadedEvents are posted to.
+func (m *Manager) SetEventHandler(h gt3.EventHandler) {
+	m.mu.Lock()
+	m.events = h
+	m.mu.Unlock()
+}
+
+// SetTrackFunc sets a function called with the path of each file newly tracked by Track, such as to watch it for
+// changes. It may be called from any goroutine.
+func (m *Manager) SetTrackFunc(fn func(path string)) {
+	m.mu.Lock()
+	m.onTrack = fn
+	m.mu.Unlock()
+}
+
+// Track records that the asset loaded with key is loaded from files, so that Reload reloads it when one of them
+// changes. Texture, File, and Program track their files themselves.
+func (m *Manager) Track(key string, files ...string) {
+	var added []string
+	m.mu.Lock()
+	for _, f := range files {
+		f = cleanPath(f)
+		keys := m.files[f]
+		if len(keys) == 0 {
+			added = append(added, f)
+		}
+		if !containsString(keys, key) {
+			m.files[f] = append(keys, key)
+		}
+	}
+	fn := m.onTrack
+	m.mu.Unlock()
+	if fn != nil {
+		for _, f := range added {
+			fn(f)
+		}
+	}
+}
+
+// Files returns the cleaned, absolute paths of all tracked files.
+func (m *Manager) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	files := make([]string, 0, len(m.files))
+	for f := range m.files {
+		files = append(files, f)
+	}
+	return files
+}
+
+// Reload reloads every loaded asset tracked as loaded from the file at path, returning the number of assets reloaded.
+// Loading runs on a worker goroutine as for the first load and, once finalized on the main goroutine, the new value
+// replaces the old one, which is freed. Texture and Program handles are updated in place, so pointers to them stay
+// valid. Other handles' values should be read with Get each time they're used. Reload may be called from any
+// goroutine.
+func (m *Manager) Reload(path string) int {
+	path = cleanPath(path)
+	var assets []reloader
+	m.mu.Lock()
+	for _, key := range m.files[path] {
+		if a, ok := m.assets[key]; ok {
+			assets = append(assets, a)
+		}
+	}
+	m.mu.Unlock()
+	for _, a := range assets {
+		a.reload()
+	}
+	return len(assets)
+}
+
+func cleanPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Progress returns the number of loads finished and started so far.
+func (m *Manager) Progress() (finished, total int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.finished, m.total
+}
+
+// Handle is a reference-counted reference to an asset of type T.
+type Handle[T any] struct {
+	m     *Manager
+	key   string
+	free  func(T)
+	start func(done func(T, error)) // Starts a load, calling done with its result on the main goroutine
+	// replace, if set, replaces old with a reloaded value, returning the value to keep. Otherwise, old is freed.
+	replace func(old, new T) T
+	refs    int  // Guarded by m.mu
+	loaded  bool // Guarded by m.mu; set once finish has run
+	state   int32
+	prog    uint64 // Float64 bits of the load's progress, from 0 to 1
+	done    chan struct{}
+
+	// Set on the main goroutine before done is closed.
+	value  T
+	err    error
+	onDone []func(T, error)
+}
+
+// Load loads the asset identified by key, or returns a new reference to it if it's already loaded or loading. If key
+// was loaded with a different type T, Load panics.
+//
+// For a new asset, load runs on a worker goroutine and may report its progress from 0 to 1 through progress. If it
+// succeeds, finalize is passed its result on the main goroutine, such as to upload it to the GPU, and returns the
+// asset's value. Once the last reference is released, free, if not nil, is called with the value on the main
+// goroutine.
+func Load[T, D any](
+	m *Manager,
+	key string,
+	load func(progress func(float64)) (D, error),
+	finalize func(D) (T, error),
+	free func(T),
+) *Handle[T] {
+	return loadAsset(m, key, load, finalize, free, nil)
+}
+
+// loadAsset is Load with a replace function for reloads, set on new handles only, before any reload can read it.
+func loadAsset[T, D any](
+	m *Manager,
+	key string,
+	load func(progress func(float64)) (D, error),
+	finalize func(D) (T, error),
+	free func(T),
+	replace func(old, new T) T,
+) *Handle[T] {
+	m.mu.Lock()
+	if a, ok := m.assets[key]; ok {
+		h, ok := a.(*Handle[T])
+		if !ok {
+			m.mu.Unlock()
+			panic(fmt.Sprintf("asset: %q already loaded as %T", key, a))
+		}
+		h.refs++
+		m.mu.Unlock()
+		return h
+	}
+	h := &Handle[T]{m: m, key: key, free: free, replace: replace, refs: 1, done: make(chan struct{})}
+	h.start = func(done func(T, error)) {
+		go func() {
+			m.sem <- struct{}{}
+			data, err := load(h.setProgress)
+			<-m.sem
+			m.sim.Sched(gt3.OpFn(func(float64, float64, time.Time) {
+				var v T
+				if err == nil {
+					v, err = finalize(data)
+				}
+				done(v, err)
+			}))
+		}()
+	}
+	m.assets[key] = h
+	m.total++
+	m.mu.Unlock()
+
+	h.start(h.finish)
+	return h
+}
+
+// Texture loads a PNG or JPEG image from path and uploads it as a texture with gfx.LoadTexture. Textures are keyed by
+// path, so options only apply to the first load of a path.
+func (m *Manager) Texture(path string, opts ...gfx.TextureOption) *Handle[*gfx.Texture] {
+	h := loadAsset(m, path,
+		func(progress func(float64)) (image.Image, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			img, _, err := image.Decode(f)
+			return img, err
+		},
+		func(img image.Image) (*gfx.Texture, error) { return gfx.LoadTexture(img, opts...) },
+		(*gfx.Texture).Delete,
+		replaceInPlace((*gfx.Texture).Delete),
+	)
+	m.Track(path, path)
+	return h
+}
+
+// Program compiles and links a shader program from the vertex and fragment shader files at the given paths, keyed by
+// both paths. If compiling fails, the error is a *gfx.ShaderError with its File set.
+func (m *Manager) Program(vertexPath, fragmentPath string) *Handle[*gfx.Program] {
+	key := vertexPath + "\x00" + fragmentPath
+	h := loadAsset(m, key,
+		func(progress func(float64)) ([2]string, error) {
+			vs, err := os.ReadFile(vertexPath)
+			if err != nil {
+				return [2]string{}, err
+			}
+			fs, err := os.ReadFile(fragmentPath)
+			return [2]string{string(vs), string(fs)}, err
+		},
+		func(src [2]string) (*gfx.Program, error) {
+			p, err := gfx.LoadProgram(src[0], src[1])
+			if se, ok := err.(*gfx.ShaderError); ok {
+				switch se.Stage {
+				case "vertex":
+					se.File = vertexPath
+				case "fragment":
+					se.File = fragmentPath
+				}
+			}
+			return p, err
+		},
+		(*gfx.Program).Delete,
+		replaceInPlace((*gfx.Program).Delete),
+	)
+	m.Track(key, vertexPath, fragmentPath)
+	return h
+}
+
+// replaceInPlace returns a replace function that frees old with del and copies new into it, so that pointers to old
+// see the reloaded value.
+func replaceInPlace[T any](del func(*T)) func(old, new *T) *T {
+	return func(old, new *T) *T {
+		del(old)
+		*old = *new
+		return old
+	}
+}
+
+// File reads the file at path.
+func (m *Manager) File(path string) *Handle[[]byte] {
+	h := Load(m, path,
+		func(progress func(float64)) ([]byte, error) { return os.ReadFile(path) },
+		func(b []byte) ([]byte, error) { return b, nil },
+		nil,
+	)
+	m.Track(path, path)
+	return h
+}
+
+func (h *Handle[T]) setProgress(p float64) {
+	atomic.StoreUint64(&h.prog, math.Float64bits(math.Max(0, math.Min(1, p))))
+}
+
+// finish records the result of a load on the main goroutine.
+func (h *Handle[T]) finish(v T, err error) {
+	h.setProgress(1)
+	h.value, h.err = v, err
+	state := Ready
+	if err != nil {
+		state = Failed
+	}
+
+	m := h.m
+	m.mu.Lock()
+	h.loaded = true
+	released := h.refs == 0
+	if released {
+		state = Released
+	}
+	atomic.StoreInt32(&h.state, int32(state))
+	m.finished++
+	finished, total, progress := m.finished, m.total, m.progress
+	m.mu.Unlock()
+
+	close(h.done)
+	if released {
+		h.freeValue()
+	} else {
+		for _, fn := range h.onDone {
+			fn(v, err)
+		}
+	}
+	h.onDone = nil
+	if progress != nil {
+		progress(finished, total)
+	}
+}
+
+func (h *Handle[T]) reload() {
+	h.m.mu.Lock()
+	loaded := h.loaded
+	h.m.mu.Unlock()
+	if loaded {
+		h.start(h.reloaded)
+	}
+}
+
+// reloaded replaces the asset's value with the result of a reload on the main goroutine.
+func (h *Handle[T]) reloaded(v T, err error) {
+	switch {
+	case h.State() == Released:
+		if err == nil && h.free != nil {
+			h.free(v)
+		}
+		return
+	case err != nil:
+	case h.State() == Failed:
+		h.value, h.err = v, nil
+		atomic.StoreInt32(&h.state, int32(Ready))
+	case h.replace != nil:
+		h.value = h.replace(h.value, v)
+	default:
+		h.freeValue()
+		h.value = v
+	}
+
+	h.m.mu.Lock()
+	events := h.m.events
+	h.m.mu.Unlock()
+	if events != nil {
+		events.Event(ReloadedEvent{Key: h.key, Err: err}, time.Now())
+	}
+}
+
+func (h *Handle[T]) freeValue() {
+	if h.free != nil && h.err == nil {
+		h.free(h.value)
+	}
+	var zero T
+	h.value = zero
+}
+
+// Key returns the key the asset was loaded with.
+func (h *Handle[T]) Key() string {
+	return h.key
+}
+
+// State returns the asset's load state.
+func (h *Handle[T]) State() State {
+	return State(atomic.LoadInt32(&h.state))
+}
+
+// Progress returns the load's progress from 0 to 1, as reported by its load function. It is 1 once the load finishes.
+func (h *Handle[T]) Progress() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&h.prog))
+}
+
+// Done returns a channel closed once the asset is ready or has failed.
+func (h *Handle[T]) Done() <-chan struct{} {
+	return h.done
+}
+
+// Get returns the asset's value and whether it's ready.
+func (h *Handle[T]) Get() (v T, ok bool) {
+	if h.State() != Ready {
+		return v, false
+	}
+	return h.value, true
+}
+
+// Err returns the error the load failed with, if it has failed.
+func (h *Handle[T]) Err() error {
+	if h.State() != Failed {
+		return nil
+	}
+	return h.err
+}
+
+// Wait waits for the asset to finish loading and returns its value or error. Since assets are finalized on the main
+// goroutine, Wait must not be called from it.
+func (h *Handle[T]) Wait() (T, error) {
+	<-h.done
+	return h.value, h.err
+}
+
+// OnDone calls fn with the asset's value or error once it finishes loading, or immediately if it already has. It isn't
+// called if every reference is released first. OnDone must be called from the main goroutine, and fn is called on it.
+func (h *Handle[T]) OnDone(fn func(v T, err error)) {
+	select {
+	case <-h.done:
+		if h.State() != Released {
+			fn(h.value, h.err)
+		}
+	default:
+		h.onDone = append(h.onDone, fn)
+	}
+}
+
+// Retain adds a reference to the asset and returns h.
+func (h *Handle[T]) Retain() *Handle[T] {
+	h.m.mu.Lock()
+	h.refs++
+	h.m.mu.Unlock()
+	return h
+}
+
+// Release drops a reference to the asset. Once the last reference is released, the asset is removed from its Manager
+// and freed on the main goroutine, after it finishes loading if it hasn't yet. Loading the same key again afterwards
+// starts a new load.
+func (h *Handle[T]) Release() {
+	m := h.m
+	m.mu.Lock()
+	if h.refs == 0 {
+		m.mu.Unlock()
+		return
+	}
+	h.refs--
+	last := h.refs == 0
+	if last {
+		delete(m.assets, h.key)
+	}
+	loaded := h.loaded
+	m.mu.Unlock()
+	if !last || !loaded {
+		return // If still loading, finish frees it
+	}
+
+	m.sim.Sched(gt3.OpFn(func(float64, float64, time.Time) {
+		if State(atomic.SwapInt32(&h.state, int32(Released))) != Released {
+			h.freeValue()
+		}
+	}))
+}
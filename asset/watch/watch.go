@@ -0,0 +1,130 @@
+// Package watch reloads an asset.Manager's assets when the files they're loaded from change on disk, such as when
+// editing shaders and textures while a program runs. Reloaded assets are finalized on the main goroutine and announced
+// with asset.ReloadedEvents.
+package watch
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.spiff.io/gt3/asset"
+)
+
+// DefaultDelay is how long a Watcher waits after a file stops changing before reloading it.
+const DefaultDelay = 100 * time.Millisecond
+
+// Watcher watches the files tracked by an asset.Manager.
+type Watcher struct {
+	m  *asset.Manager
+	fw *fsnotify.Watcher
+
+	// Delay is how long to wait after a file stops changing before reloading it, since editors often save files in
+	// several writes or by replacing them. It must be set before files change.
+	Delay time.Duration
+
+	mu      sync.Mutex
+	dirs    map[string]bool
+	pending map[string]*time.Timer
+	err     error
+	done    chan struct{}
+}
+
+// New returns a Watcher reloading m's assets when their files change. It watches the files m already tracks and any it
+// tracks afterwards, replacing any function set with m.SetTrackFunc. Files are watched through their directories, so
+// that files replaced by editors are still seen.
+func New(m *asset.Manager) (*Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		m:       m,
+		fw:      fw,
+		Delay:   DefaultDelay,
+		dirs:    make(map[string]bool),
+		pending: make(map[string]*time.Timer),
+		done:    make(chan struct{}),
+	}
+	m.SetTrackFunc(w.add)
+	for _, f := range m.Files() {
+		w.add(f)
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *Watcher) add(path string) {
+	dir := filepath.Dir(path)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.dirs[dir] {
+		return
+	}
+	if err := w.fw.Add(dir); err != nil {
+		w.err = err
+		return
+	}
+	w.dirs[dir] = true
+}
+
+func (w *Watcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fw.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				w.changed(ev.Name)
+			}
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return
+			}
+			w.mu.Lock()
+			w.err = err
+			w.mu.Unlock()
+		}
+	}
+}
+
+// changed reloads path once it stops changing for Delay.
+func (w *Watcher) changed(path string) {
+	path = filepath.Clean(path)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.pending[path]; ok {
+		t.Reset(w.Delay)
+		return
+	}
+	w.pending[path] = time.AfterFunc(w.Delay, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		w.mu.Unlock()
+		w.m.Reload(path)
+	})
+}
+
+// Err returns the last error encountered watching files, if any.
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Close stops watching files.
+func (w *Watcher) Close() error {
+	w.m.SetTrackFunc(nil)
+	close(w.done)
+	w.mu.Lock()
+	for path, t := range w.pending {
+		t.Stop()
+		delete(w.pending, path)
+	}
+	w.mu.Unlock()
+	return w.fw.Close()
+}
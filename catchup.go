@@ -0,0 +1,42 @@
+package gt3
+
+import (
+	"math"
+	"time"
+)
+
+// LagEvent is posted when a Sim falls far enough behind real time that it drops sim ticks rather than catching up on
+// them, such as after a long stall in a debugger or while a window is dragged.
+type LagEvent struct {
+	Tick    uint64        // The tick at which ticks were dropped
+	Dropped int           // Number of ticks dropped
+	Skipped time.Duration // Simulated time skipped
+}
+
+func (LagEvent) isEvent() {}
+
+// SetMaxCatchUp limits the number of sim ticks run in a single loop iteration to steps. When the Sim is still behind
+// real time after running steps ticks, the remaining time is skipped rather than simulated, and, if handler is not
+// nil, a LagEvent is posted to it on the main goroutine. A steps value <= 0 removes the limit. SetMaxCatchUp must be
+// called before Run.
+func (s *Sim) SetMaxCatchUp(steps int, handler EventHandler) {
+	s.maxCatchUp, s.lagHandler = steps, handler
+}
+
+// dropLag skips the timer ahead from now to sim, dropping the ticks in between.
+func (s *Sim) dropLag(hz, sim, now float64) {
+	skip := now - sim
+	s.baseTime += skip
+	s.renderTime -= skip
+	s.nextDrift -= skip
+
+	dropped := int(math.Ceil(skip / hz))
+	s.stats.skip(dropped)
+	if s.lagHandler != nil {
+		s.lagHandler.Event(LagEvent{
+			Tick:    s.Tick(),
+			Dropped: dropped,
+			Skipped: time.Duration(skip * float64(time.Second)),
+		}, time.Now())
+	}
+}
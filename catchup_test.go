@@ -0,0 +1,39 @@
+package gt3
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxCatchUp(t *testing.T) {
+	const fps = 64
+
+	s, step := newTestSim(t, fps)
+	var lags []LagEvent
+	s.SetMaxCatchUp(4, EventHandlerFn(func(e Event, _ time.Time) { lags = append(lags, e.(LagEvent)) }))
+	s.Start()
+
+	tests := []struct {
+		advance float64 // Seconds to advance the clock by before stepping
+		ticks   uint64
+		lag     *LagEvent
+	}{
+		{10.0 / fps, 4, &LagEvent{Tick: 4, Dropped: 6, Skipped: 93750 * time.Microsecond}},
+		{1.0 / fps, 5, nil}, // Dropped time isn't caught up on later
+		{4.0 / fps, 9, nil}, // Exactly the limit
+		{4.5 / fps, 13, &LagEvent{Tick: 13, Dropped: 1, Skipped: 7812500 * time.Nanosecond}},
+	}
+	for i, tt := range tests {
+		lags = nil
+		step(tt.advance)
+		if got := s.Tick(); got != tt.ticks {
+			t.Errorf("step %d: Tick() = %d; want %d", i, got, tt.ticks)
+		}
+		switch {
+		case tt.lag == nil && len(lags) > 0:
+			t.Errorf("step %d: got %+v; want no LagEvent", i, lags)
+		case tt.lag != nil && (len(lags) != 1 || lags[0] != *tt.lag):
+			t.Errorf("step %d: got %+v; want %+v", i, lags, *tt.lag)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gt3
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the time source driving a Sim. Sims use GLFW's timer, or a monotonic clock on Android and iOS, unless given
+// another Clock with SetClock.
+type Clock interface {
+	// Now returns the clock's time in seconds from an arbitrary origin. It must not decrease.
+	Now() float64
+	// Wall returns the wall clock time, used to map the clock to wall time.
+	Wall() time.Time
+}
+
+// SetClock sets the Sim's time source. If c is nil, the default clock is used. SetClock must be called before Run.
+func (s *Sim) SetClock(c Clock) {
+	if c == nil {
+		c = defaultClock()
+	}
+	s.clock = c
+}
+
+// ManualClock is a Clock that only advances when told to, for running a Sim deterministically and without GLFW, such
+// as in tests. A Sim using a ManualClock runs sim ticks only as the clock is advanced, so a test typically advances it
+// by one step from a PreFrame op and stops the Sim once enough ticks have run:
+//
+//	clock := gt3.NewManualClock(time.Unix(0, 0))
+//	sim.SetClock(clock)
+//	sim.AddPreFrameOp(0, gt3.OpFn(func(step, _ float64, _ time.Time) { clock.AdvanceSeconds(step) }))
+//	sim.AddFrameOp(0, gt3.OpFn(func(float64, float64, time.Time) {
+//		if sim.Tick() == 99 {
+//			sim.Stop()
+//		}
+//	}))
+//	err := sim.Run()
+//
+// A ManualClock may be used from any goroutine.
+type ManualClock struct {
+	mu    sync.Mutex
+	now   float64
+	epoch time.Time
+}
+
+// NewManualClock returns a ManualClock at zero whose wall time is epoch plus its time.
+func NewManualClock(epoch time.Time) *ManualClock {
+	return &ManualClock{epoch: epoch}
+}
+
+// Now returns the clock's time in seconds.
+func (c *ManualClock) Now() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Wall returns the clock's epoch plus its time.
+func (c *ManualClock) Wall() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epoch.Add(time.Duration(c.now * float64(time.Second)))
+}
+
+// Advance moves the clock forward by d. Negative durations are ignored.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.AdvanceSeconds(d.Seconds())
+}
+
+// AdvanceSeconds moves the clock forward by secs seconds. Negative values are ignored.
+func (c *ManualClock) AdvanceSeconds(secs float64) {
+	if secs <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.now += secs
+	c.mu.Unlock()
+}
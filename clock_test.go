@@ -0,0 +1,108 @@
+package gt3
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	epoch := time.Unix(1000, 0)
+	c := NewManualClock(epoch)
+	c.Advance(1500 * time.Millisecond)
+	c.AdvanceSeconds(-1)
+	if got, want := c.Now(), 1.5; got != want {
+		t.Errorf("Now() = %v; want %v", got, want)
+	}
+	if got, want := c.Wall(), epoch.Add(1500*time.Millisecond); !got.Equal(want) {
+		t.Errorf("Wall() = %v; want %v", got, want)
+	}
+}
+
+func TestSimManualClock(t *testing.T) {
+	const (
+		fps   = 64 // A power of two, so that steps add up exactly
+		ticks = 100
+		start = 5.0 // Seconds the clock has run before Run
+	)
+
+	epoch := time.Unix(1000, 0)
+	clock := NewManualClock(epoch)
+	clock.AdvanceSeconds(start)
+
+	s := NewSim(fps, 0, nil)
+	s.SetClock(clock)
+	s.AddPreFrameOp(0, OpFn(func(step, _ float64, _ time.Time) { clock.AdvanceSeconds(step) }))
+	frames := 0
+	s.Frame = OpFn(func(float64, float64, time.Time) {
+		frames++
+		if s.Tick() == ticks-1 {
+			s.Stop()
+		}
+	})
+
+	if err := s.Run(); !errors.Is(err, ErrStopped) {
+		t.Fatalf("Run() = %v; want %v", err, ErrStopped)
+	}
+	if got := s.Tick(); got != ticks {
+		t.Errorf("Tick() = %d; want %d", got, ticks)
+	}
+	if frames != ticks {
+		t.Errorf("Frame ran %d times; want %d", frames, ticks)
+	}
+	secs := float64(ticks) / fps
+	if got := s.Seconds(); got != secs {
+		t.Errorf("Seconds() = %v; want %v", got, secs)
+	}
+	// The Sim started when the clock read start, so sim time 0 maps to the clock's wall time then.
+	want := epoch.Add(time.Duration((start + secs) * float64(time.Second)))
+	if got := s.Time(); got.Sub(want).Abs() > time.Microsecond {
+		t.Errorf("Time() = %v; want %v", got, want)
+	}
+}
+
+func TestSimStep(t *testing.T) {
+	const fps = 64
+
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewSim(fps, 0, nil)
+	s.SetClock(clock)
+	renders, stops := 0, 0
+	s.Render = OpFn(func(float64, float64, time.Time) { renders++ })
+	s.OnStop(OpFn(func(float64, float64, time.Time) { stops++ }))
+
+	s.Start()
+	tests := []struct {
+		advance float64 // Seconds to advance the clock by before stepping
+		ticks   uint64  // Total ticks after stepping; sim time runs ahead of the clock by up to a step
+	}{
+		{0, 0},
+		{1.0 / fps, 1},
+		{0.5 / fps, 2},
+		{0.5 / fps, 2},
+		{3.0 / fps, 5},
+	}
+	for i, tt := range tests {
+		clock.AdvanceSeconds(tt.advance)
+		if err := s.Step(); err != nil {
+			t.Fatalf("step %d: Step() = %v", i, err)
+		}
+		if got := s.Tick(); got != tt.ticks {
+			t.Errorf("step %d: Tick() = %d; want %d", i, got, tt.ticks)
+		}
+		if renders != i+1 {
+			t.Errorf("step %d: rendered %d times; want %d", i, renders, i+1)
+		}
+	}
+	if stops != 0 {
+		t.Fatalf("OnStop ran %d times before stopping", stops)
+	}
+
+	s.Stop()
+	if err := s.Step(); !errors.Is(err, ErrStopped) {
+		t.Fatalf("Step() after Stop = %v; want %v", err, ErrStopped)
+	}
+	if stops != 1 {
+		t.Errorf("OnStop ran %d times; want 1", stops)
+	}
+}
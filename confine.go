@@ -0,0 +1,172 @@
+//go:build !android && !ios
+
+package gt3
+
+import (
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// CursorConfineEvent is posted by a CursorConfiner when confinement is broken, such as when the window loses focus or
+// the cursor escapes it, and again when confinement resumes.
+type CursorConfineEvent struct {
+	Window   *Window
+	Confined bool
+}
+
+func (CursorConfineEvent) isEvent() {}
+
+// ConfineMode is the method a CursorConfiner uses to keep the cursor within its region.
+type ConfineMode int
+
+// Confinement modes.
+const (
+	// ConfineWarp warps the visible system cursor back into the region whenever it leaves it. The cursor may briefly
+	// appear outside the region, and may escape entirely if moved fast enough to leave the window between events.
+	ConfineWarp ConfineMode = iota
+	// ConfineVirtual disables the system cursor and tracks a virtual cursor, moved by cursor deltas and clamped to the
+	// region, which is reported in CursorPosEvents. The cursor can't escape, but the application must draw it.
+	ConfineVirtual
+)
+
+// CursorConfiner confines a window's cursor to a rectangle within it, such as for edge scrolling. It's an EventHandler
+// that should receive the window's events, and it passes CursorPosEvents to Next with positions clamped to the region.
+// A CursorConfiner must only be used from the main goroutine.
+type CursorConfiner struct {
+	Next EventHandler
+
+	win        *Window
+	mode       ConfineMode
+	x0, y0     float64
+	x1, y1     float64
+	enabled    bool // Requested by Enable and Disable
+	unfocused  bool
+	active     bool // Enabled and focused
+	confined   bool
+	vx, vy     float64 // Virtual cursor position
+	lastX      float64 // Last system cursor position in ConfineVirtual mode
+	lastY      float64
+	havePrevXY bool
+}
+
+// ConfineCursor starts confining w's cursor to the rectangle from (x, y) to (x+width, y+height) in screen coordinates
+// relative to the window. w must be a GLFW window.
+func ConfineCursor(w *Window, mode ConfineMode, x, y, width, height float64, next EventHandler) *CursorConfiner {
+	c := &CursorConfiner{Next: next, win: w, mode: mode}
+	c.SetRegion(x, y, width, height)
+	c.Enable()
+	return c
+}
+
+// SetRegion changes the confinement rectangle.
+func (c *CursorConfiner) SetRegion(x, y, width, height float64) {
+	c.x0, c.y0, c.x1, c.y1 = x, y, x+width, y+height
+	c.vx, c.vy = c.clamp(c.vx, c.vy)
+}
+
+func (c *CursorConfiner) clamp(x, y float64) (float64, float64) {
+	switch {
+	case x < c.x0:
+		x = c.x0
+	case x > c.x1:
+		x = c.x1
+	}
+	switch {
+	case y < c.y0:
+		y = c.y0
+	case y > c.y1:
+		y = c.y1
+	}
+	return x, y
+}
+
+// Enable resumes confinement.
+func (c *CursorConfiner) Enable() {
+	c.enabled = true
+	c.update()
+}
+
+// Disable stops confinement and restores the system cursor.
+func (c *CursorConfiner) Disable() {
+	c.enabled = false
+	c.update()
+}
+
+// update applies confinement if it's enabled and the window is focused, and releases it otherwise.
+func (c *CursorConfiner) update() {
+	gw := c.win.GLFW()
+	active := c.enabled && !c.unfocused
+	if gw == nil || active == c.active {
+		return
+	}
+	c.active = active
+
+	if active {
+		x, y := gw.GetCursorPos()
+		c.vx, c.vy = c.clamp(x, y)
+		c.havePrevXY = false
+		if c.mode == ConfineVirtual {
+			gw.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+		}
+	} else if c.mode == ConfineVirtual {
+		gw.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+		gw.SetCursorPos(c.vx, c.vy)
+	}
+	c.setConfined(active, time.Now())
+}
+
+// Position returns the confined cursor position.
+func (c *CursorConfiner) Position() (x, y float64) {
+	return c.vx, c.vy
+}
+
+func (c *CursorConfiner) setConfined(confined bool, when time.Time) {
+	if c.confined == confined {
+		return
+	}
+	c.confined = confined
+	if c.Next != nil {
+		c.Next.Event(CursorConfineEvent{c.win, confined}, when)
+	}
+}
+
+func (c *CursorConfiner) Event(e Event, when time.Time) {
+	switch ev := e.(type) {
+	case CursorPosEvent:
+		if ev.Window == c.win && c.active {
+			e = c.move(ev, when)
+		}
+	case FocusEvent:
+		if ev.Window == c.win {
+			c.unfocused = !ev.Focused
+			c.update()
+		}
+	case CursorEnterEvent:
+		if ev.Window == c.win && c.active && c.mode == ConfineWarp {
+			c.setConfined(ev.Entered, when)
+		}
+	}
+	if c.Next != nil {
+		c.Next.Event(e, when)
+	}
+}
+
+func (c *CursorConfiner) move(ev CursorPosEvent, when time.Time) CursorPosEvent {
+	switch c.mode {
+	case ConfineVirtual:
+		if c.havePrevXY {
+			c.vx, c.vy = c.clamp(c.vx+ev.X-c.lastX, c.vy+ev.Y-c.lastY)
+		}
+		c.lastX, c.lastY, c.havePrevXY = ev.X, ev.Y, true
+	default:
+		x, y := c.clamp(ev.X, ev.Y)
+		if x != ev.X || y != ev.Y {
+			c.win.GLFW().SetCursorPos(x, y)
+		}
+		c.vx, c.vy = x, y
+		c.setConfined(true, when)
+	}
+	ev.X, ev.Y = c.vx, c.vy
+	return ev
+}
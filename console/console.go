@@ -0,0 +1,229 @@
+package console
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/debugui"
+)
+
+// Console is a drop-down command console. It must only be used from the main goroutine.
+type Console struct {
+	Registry
+
+	// ToggleKey opens and closes the console. Defaults to gt3.KeyGraveAccent.
+	ToggleKey gt3.Key
+	// MaxLines is the number of output lines kept. Defaults to 200.
+	MaxLines int
+
+	sim  *gt3.Sim
+	open bool
+	edit LineEditor
+
+	history []string
+	histPos int
+
+	output    []string
+	swallowCh bool // Drop the CharEvent produced by the toggle key
+}
+
+// New returns a closed Console whose commands run via sim.Sched. It registers a "help" command listing all commands.
+func New(sim *gt3.Sim) *Console {
+	c := &Console{
+		ToggleKey: gt3.KeyGraveAccent,
+		MaxLines:  200,
+		sim:       sim,
+	}
+	c.Register(Command{Name: "help", Help: "list commands", Run: func(c *Console, args []string) error {
+		for _, name := range c.Complete("") {
+			cmd, _ := c.Lookup(name)
+			c.Printf("%s - %s", cmd.Name, cmd.Help)
+		}
+		return nil
+	}})
+	return c
+}
+
+// Open reports whether the console is open.
+func (c *Console) Open() bool { return c.open }
+
+// SetOpen opens or closes the console.
+func (c *Console) SetOpen(open bool) { c.open = open }
+
+// Printf appends a line to the console's output.
+func (c *Console) Printf(format string, args ...interface{}) {
+	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
+		c.output = append(c.output, line)
+	}
+	if over := len(c.output) - c.MaxLines; c.MaxLines > 0 && over > 0 {
+		c.output = append(c.output[:0], c.output[over:]...)
+	}
+}
+
+// Exec parses line and schedules the named command to run via the Sim's Sched. Errors are printed to the console.
+func (c *Console) Exec(line string) {
+	c.Printf("> %s", line)
+	args, err := Split(line)
+	if err != nil {
+		c.Printf("error: %v", err)
+		return
+	}
+	if len(args) == 0 {
+		return
+	}
+
+	cmd, ok := c.Lookup(args[0])
+	if !ok {
+		c.Printf("unknown command: %s", args[0])
+		return
+	}
+	c.sim.Sched(gt3.OpFn(func(float64, float64, time.Time) {
+		if err := cmd.Run(c, args[1:]); err != nil {
+			c.Printf("%s: %v", cmd.Name, err)
+		}
+	}))
+}
+
+// Events returns an EventHandler that feeds input to the console while it's open and passes events to next while it's
+// closed. The toggle key is always handled by the console.
+func (c *Console) Events(next gt3.EventHandler) gt3.EventHandler {
+	return gt3.EventHandlerFn(func(e gt3.Event, when time.Time) {
+		if !c.handle(e) && next != nil {
+			next.Event(e, when)
+		}
+	})
+}
+
+// handle processes an event and reports whether the console consumed it.
+func (c *Console) handle(e gt3.Event) bool {
+	switch ev := e.(type) {
+	case gt3.KeyEvent:
+		if ev.Key == c.ToggleKey && ev.Action == gt3.Press {
+			c.open = !c.open
+			c.swallowCh = true
+			return true
+		}
+		if !c.open {
+			return false
+		}
+		if ev.Action != gt3.Release {
+			c.key(ev.Key)
+		}
+		return true
+	case gt3.CharEvent:
+		if c.swallowCh {
+			c.swallowCh = false
+			return true
+		}
+		if c.open {
+			c.edit.Insert(ev.Char)
+		}
+		return c.open
+	case gt3.CharModsEvent:
+		return c.open
+	}
+	return false
+}
+
+func (c *Console) key(k gt3.Key) {
+	c.swallowCh = false
+	switch k {
+	case gt3.KeyEnter, gt3.KeyKPEnter:
+		line := c.edit.String()
+		c.edit.Set("")
+		if strings.TrimSpace(line) != "" {
+			c.history = append(c.history, line)
+		}
+		c.histPos = len(c.history)
+		c.Exec(line)
+	case gt3.KeyBackspace:
+		c.edit.Backspace()
+	case gt3.KeyDelete:
+		c.edit.Delete()
+	case gt3.KeyLeft:
+		c.edit.Move(-1)
+	case gt3.KeyRight:
+		c.edit.Move(1)
+	case gt3.KeyHome:
+		c.edit.Home()
+	case gt3.KeyEnd:
+		c.edit.End()
+	case gt3.KeyUp:
+		if c.histPos > 0 {
+			c.histPos--
+			c.edit.Set(c.history[c.histPos])
+		}
+	case gt3.KeyDown:
+		if c.histPos < len(c.history)-1 {
+			c.histPos++
+			c.edit.Set(c.history[c.histPos])
+		} else {
+			c.histPos = len(c.history)
+			c.edit.Set("")
+		}
+	case gt3.KeyTab:
+		c.complete()
+	}
+}
+
+// complete completes the command name being typed. With a single match, the name is filled in; with several, the
+// longest common prefix is filled in and the matches are printed.
+func (c *Console) complete() {
+	text := c.edit.String()
+	if strings.ContainsAny(text, " \t") {
+		return
+	}
+	matches := c.Complete(text)
+	switch len(matches) {
+	case 0:
+		return
+	case 1:
+		c.edit.Set(matches[0] + " ")
+		return
+	}
+
+	prefix := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	c.edit.Set(prefix)
+	c.Printf("%s", strings.Join(matches, "  "))
+}
+
+// Colors used when drawing the console.
+var (
+	ColorBackground = color.RGBA{0x10, 0x10, 0x10, 0xe0}
+	ColorText       = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	ColorInput      = color.RGBA{0xff, 0xff, 0x80, 0xff}
+)
+
+// Draw draws the console, if open, across the top of the screen with the given width, showing up to lines lines of
+// output above the input line.
+func (c *Console) Draw(p debugui.Painter, width float64, lines int) {
+	if !c.open {
+		return
+	}
+
+	_, lh := p.TextSize("M")
+	p.FillRect(0, 0, width, lh*float64(lines+1)+4, ColorBackground)
+
+	out := c.output
+	if len(out) > lines {
+		out = out[len(out)-lines:]
+	}
+	y := 2 + lh*float64(lines-len(out))
+	for _, line := range out {
+		p.Text(2, y, line, ColorText)
+		y += lh
+	}
+
+	input := c.edit.String()
+	p.Text(2, y, "] "+input, ColorInput)
+	cw, _ := p.TextSize("] " + string([]rune(input)[:c.edit.Cursor()]))
+	p.FillRect(2+cw, y, 1, lh, ColorInput)
+}
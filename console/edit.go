@@ -0,0 +1,59 @@
+package console
+
+// LineEditor is a single-line text buffer with a cursor.
+type LineEditor struct {
+	text   []rune
+	cursor int
+}
+
+// String returns the editor's text.
+func (e *LineEditor) String() string { return string(e.text) }
+
+// Cursor returns the cursor's position, in runes from the start of the text.
+func (e *LineEditor) Cursor() int { return e.cursor }
+
+// Set replaces the text and moves the cursor to its end.
+func (e *LineEditor) Set(s string) {
+	e.text = []rune(s)
+	e.cursor = len(e.text)
+}
+
+// Insert inserts r at the cursor and advances the cursor.
+func (e *LineEditor) Insert(r rune) {
+	e.text = append(e.text, 0)
+	copy(e.text[e.cursor+1:], e.text[e.cursor:])
+	e.text[e.cursor] = r
+	e.cursor++
+}
+
+// Backspace deletes the rune before the cursor.
+func (e *LineEditor) Backspace() {
+	if e.cursor == 0 {
+		return
+	}
+	e.text = append(e.text[:e.cursor-1], e.text[e.cursor:]...)
+	e.cursor--
+}
+
+// Delete deletes the rune at the cursor.
+func (e *LineEditor) Delete() {
+	if e.cursor < len(e.text) {
+		e.text = append(e.text[:e.cursor], e.text[e.cursor+1:]...)
+	}
+}
+
+// Move moves the cursor by n runes, clamped to the text.
+func (e *LineEditor) Move(n int) {
+	e.cursor += n
+	if e.cursor < 0 {
+		e.cursor = 0
+	} else if e.cursor > len(e.text) {
+		e.cursor = len(e.text)
+	}
+}
+
+// Home moves the cursor to the start of the text.
+func (e *LineEditor) Home() { e.cursor = 0 }
+
+// End moves the cursor to the end of the text.
+func (e *LineEditor) End() { e.cursor = len(e.text) }
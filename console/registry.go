@@ -0,0 +1,113 @@
+// Package console implements a drop-down developer console: a registry of commands, a line editor with history and
+// tab completion, and rendering through a debugui.Painter. Commands run on the main goroutine via Sim.Sched.
+package console
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Handler runs a console command. args holds the command's arguments, not including its name.
+type Handler func(c *Console, args []string) error
+
+// Command is a named console command.
+type Command struct {
+	Name string
+	Help string
+	Run  Handler
+}
+
+// ErrDuplicate is returned when registering a command whose name is already registered.
+var ErrDuplicate = errors.New("console: command already registered")
+
+// Registry holds console commands by name. The zero value is an empty Registry ready for use. A Registry may be used
+// from any goroutine.
+type Registry struct {
+	mu   sync.RWMutex
+	cmds map[string]Command
+}
+
+// Register adds cmd to the registry.
+func (r *Registry) Register(cmd Command) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cmds == nil {
+		r.cmds = make(map[string]Command)
+	}
+	if _, dup := r.cmds[cmd.Name]; dup {
+		return ErrDuplicate
+	}
+	r.cmds[cmd.Name] = cmd
+	return nil
+}
+
+// Lookup returns the command with the given name.
+func (r *Registry) Lookup(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmd, ok := r.cmds[name]
+	return cmd, ok
+}
+
+// Complete returns the sorted names of commands beginning with prefix.
+func (r *Registry) Complete(prefix string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var names []string
+	for name := range r.cmds {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrUnterminated is returned by Split for a line with an unterminated quote.
+var ErrUnterminated = errors.New("console: unterminated quote")
+
+// Split splits a command line into words. Words are separated by whitespace, may be quoted with single or double
+// quotes to include whitespace, and may escape any character with a backslash outside of single quotes.
+func Split(line string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, ErrUnterminated
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
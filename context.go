@@ -0,0 +1,39 @@
+package gt3
+
+import "context"
+
+// NewSimContext returns a new Sim that stops when ctx is done. Once ctx is done, Run returns, and Sync returns without
+// waiting, a *StopError wrapping ctx.Err(), which also matches ErrStopped with errors.Is.
+func NewSimContext(ctx context.Context, fps, renderfps int) *Sim {
+	s := NewSim(fps, renderfps, ctx.Done())
+	s.ctx = ctx
+	return s
+}
+
+// StopError is returned by a Sim created with NewSimContext when its context is done. It matches ErrStopped and the
+// context's error with errors.Is.
+type StopError struct {
+	Err error // The context's error
+}
+
+func (e *StopError) Error() string {
+	return ErrStopped.Error() + ": " + e.Err.Error()
+}
+
+func (e *StopError) Is(target error) bool {
+	return target == ErrStopped
+}
+
+func (e *StopError) Unwrap() error {
+	return e.Err
+}
+
+// stopErr returns the error Run and Sync return once the Sim is stopped.
+func (s *Sim) stopErr() error {
+	if s.ctx != nil {
+		if err := s.ctx.Err(); err != nil {
+			return &StopError{err}
+		}
+	}
+	return ErrStopped
+}
@@ -0,0 +1,7 @@
+//go:build gt3debug
+
+package gt3
+
+// debugChecks enables extra runtime checks, such as main thread assertions before GL and GLFW calls. It is set by the
+// gt3debug build tag.
+const debugChecks = true
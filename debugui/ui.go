@@ -0,0 +1,228 @@
+// Package debugui is a minimal immediate-mode UI for tuning values at runtime. Widgets are laid out top to bottom in a
+// single panel, driven by gt3 cursor and mouse events, and drawn through a Painter such as gfx.DebugDraw.
+//
+// A typical render op calls Begin, then a widget function for each control, then End, and finally flushes the
+// Painter:
+//
+//	ui.Begin(10, 10)
+//	if ui.Button("Reset") {
+//		reset()
+//	}
+//	ui.Slider("Speed", &speed, 0, 10)
+//	ui.Checkbox("Wireframe", &wireframe)
+//	ui.Value("FPS", fps)
+//	ui.End()
+package debugui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// Painter draws UI primitives. Coordinates are in screen units with the origin at the top-left, matching cursor
+// events. *gfx.DebugDraw implements Painter.
+type Painter interface {
+	FillRect(x, y, w, h float64, c color.RGBA)
+	Text(x, y float64, s string, c color.RGBA)
+	TextSize(s string) (w, h float64)
+}
+
+// Colors used by the UI.
+var (
+	ColorWidget = color.RGBA{0x40, 0x40, 0x40, 0xff}
+	ColorHot    = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	ColorActive = color.RGBA{0x30, 0x70, 0xc0, 0xff}
+	ColorText   = color.RGBA{0xf0, 0xf0, 0xf0, 0xff}
+)
+
+const (
+	padding = 4
+	// WidgetWidth is the width of sliders and buttons in screen units.
+	WidgetWidth = 160
+)
+
+// UI holds the state of an immediate-mode UI. It must only be used from the main goroutine.
+type UI struct {
+	Painter Painter
+
+	// Input state
+	mx, my            float64
+	down              bool
+	pressed, released bool
+	hot, active       int
+
+	// Layout
+	x, y float64
+	id   int
+}
+
+// New returns a UI drawing with p.
+func New(p Painter) *UI {
+	return &UI{Painter: p}
+}
+
+// Event updates the UI's cursor and mouse button state. UI implements gt3.EventHandler so it can be subscribed to a
+// window's events.
+func (u *UI) Event(e gt3.Event, _ time.Time) {
+	switch ev := e.(type) {
+	case gt3.CursorPosEvent:
+		u.mx, u.my = ev.X, ev.Y
+	case gt3.MouseEvent:
+		if ev.Button != gt3.MouseButtonLeft {
+			return
+		}
+		switch ev.Action {
+		case gt3.Press:
+			u.down, u.pressed = true, true
+		case gt3.Release:
+			u.down, u.released = false, true
+		}
+	}
+}
+
+// Capturing reports whether the cursor is over the UI or a widget is being dragged, in which case the application
+// should ignore mouse input.
+func (u *UI) Capturing() bool {
+	return u.active != 0 || u.hot != 0
+}
+
+// Begin starts laying out widgets from top to bottom, starting with the top-left corner of the first at x, y.
+func (u *UI) Begin(x, y float64) {
+	u.x, u.y = x, y
+	u.id = 0
+	u.hot = 0
+}
+
+// End finishes the panel and resets per-frame input state.
+func (u *UI) End() {
+	if !u.down {
+		u.active = 0
+	}
+	u.pressed, u.released = false, false
+}
+
+func (u *UI) nextID() int {
+	u.id++
+	return u.id
+}
+
+func (u *UI) row(h float64) (x, y float64) {
+	x, y = u.x, u.y
+	u.y += h + padding
+	return x, y
+}
+
+// interact updates hot/active state for a widget occupying the given rectangle and reports whether it is hot and
+// whether it is active.
+func (u *UI) interact(id int, x, y, w, h float64) (hot, active bool) {
+	if u.mx >= x && u.mx < x+w && u.my >= y && u.my < y+h {
+		u.hot = id
+		if u.pressed && u.active == 0 {
+			u.active = id
+		}
+	}
+	return u.hot == id, u.active == id
+}
+
+func (u *UI) widgetColor(hot, active bool) color.RGBA {
+	switch {
+	case active:
+		return ColorActive
+	case hot:
+		return ColorHot
+	}
+	return ColorWidget
+}
+
+func (u *UI) lineHeight() float64 {
+	_, h := u.Painter.TextSize("M")
+	return h + 2*padding
+}
+
+// Button draws a button and reports whether it was clicked this frame.
+func (u *UI) Button(label string) bool {
+	id, h := u.nextID(), u.lineHeight()
+	x, y := u.row(h)
+	hot, active := u.interact(id, x, y, WidgetWidth, h)
+
+	u.Painter.FillRect(x, y, WidgetWidth, h, u.widgetColor(hot, active))
+	u.Painter.Text(x+padding, y+padding, label, ColorText)
+	return hot && active && u.released
+}
+
+// Checkbox draws a labeled checkbox toggling *v and reports whether it changed this frame.
+func (u *UI) Checkbox(label string, v *bool) bool {
+	id, h := u.nextID(), u.lineHeight()
+	x, y := u.row(h)
+	hot, active := u.interact(id, x, y, WidgetWidth, h)
+
+	u.Painter.FillRect(x, y, h, h, u.widgetColor(hot, active))
+	if *v {
+		u.Painter.FillRect(x+padding, y+padding, h-2*padding, h-2*padding, ColorText)
+	}
+	u.Painter.Text(x+h+padding, y+padding, label, ColorText)
+
+	if hot && active && u.released {
+		*v = !*v
+		return true
+	}
+	return false
+}
+
+// Slider draws a labeled slider for *v in [min, max] and reports whether *v changed this frame.
+func (u *UI) Slider(label string, v *float64, min, max float64) bool {
+	id, h := u.nextID(), u.lineHeight()
+	x, y := u.row(h)
+	_, active := u.interact(id, x, y, WidgetWidth, h)
+
+	changed := false
+	if active && u.down && max > min {
+		t := (u.mx - x) / WidgetWidth
+		if t < 0 {
+			t = 0
+		} else if t > 1 {
+			t = 1
+		}
+		if nv := min + t*(max-min); nv != *v {
+			*v, changed = nv, true
+		}
+	}
+
+	u.Painter.FillRect(x, y, WidgetWidth, h, ColorWidget)
+	if max > min {
+		t := (*v - min) / (max - min)
+		u.Painter.FillRect(x, y, WidgetWidth*t, h, ColorActive)
+	}
+	u.Painter.Text(x+padding, y+padding, fmt.Sprintf("%s: %.3g", label, *v), ColorText)
+	return changed
+}
+
+// Value draws a read-only labeled value.
+func (u *UI) Value(label string, v interface{}) {
+	text := fmt.Sprintf("%s: %v", label, v)
+	_, h := u.Painter.TextSize(text)
+	x, y := u.row(h)
+	u.Painter.Text(x, y, text, ColorText)
+}
+
+// Allocs displays a Sim's allocation statistics: bytes allocated in the last loop iteration, per tick, and per phase.
+func (u *UI) Allocs(stats gt3.AllocStats) {
+	u.Value("Alloc/iter", stats.Bytes)
+	u.Value("Alloc/tick", stats.PerTick())
+	for p := gt3.PhasePreFrame; p <= gt3.PhaseStop; p++ {
+		if n, ok := stats.Phases[p]; ok {
+			u.Value("  "+p.String(), n)
+		}
+	}
+}
+
+// Stats displays a Sim's frame statistics.
+func (u *UI) Stats(stats gt3.FrameStats) {
+	u.Value("FPS", fmt.Sprintf("%.1f", stats.RenderFPS))
+	u.Value("TPS", fmt.Sprintf("%.1f", stats.TicksPerSecond))
+	u.Value("Frame", fmt.Sprintf("avg %v min %v max %v p99 %v", stats.Avg, stats.Min, stats.Max, stats.P99))
+	u.Value("Dropped", stats.Dropped)
+}
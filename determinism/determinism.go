@@ -0,0 +1,265 @@
+// Package determinism validates that a simulation is deterministic. Systems register functions that write their state
+// to a hash, and a Checker hashes every system once per tick. Records can be compared between two Sims stepped in
+// lockstep with identical inputs, or against a baseline recorded from an earlier run, pinpointing the first tick and
+// system whose state diverges.
+package determinism
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+
+	"go.spiff.io/gt3"
+)
+
+// StateFunc writes a system's state to w. It must write the same bytes for the same state, so map iteration and
+// pointers must be avoided.
+type StateFunc func(w io.Writer)
+
+// Checker hashes registered systems. A Checker must only be used from the goroutine running its Sim.
+type Checker struct {
+	names []string
+	fns   []StateFunc
+}
+
+// Register adds a system to hash. Systems are hashed in the order they're registered, and records are only comparable
+// between Checkers with the same systems registered in the same order.
+func (c *Checker) Register(name string, fn StateFunc) {
+	c.names = append(c.names, name)
+	c.fns = append(c.fns, fn)
+}
+
+// Systems returns the names of registered systems.
+func (c *Checker) Systems() []string {
+	return append([]string(nil), c.names...)
+}
+
+// Record holds the state hashes of each system at a tick.
+type Record struct {
+	Tick   uint64
+	Hashes []uint64
+}
+
+// Hash hashes every system's current state.
+func (c *Checker) Hash(tick uint64) Record {
+	rec := Record{Tick: tick, Hashes: make([]uint64, len(c.fns))}
+	h := fnv.New64a()
+	for i, fn := range c.fns {
+		h.Reset()
+		fn(h)
+		rec.Hashes[i] = h.Sum64()
+	}
+	return rec
+}
+
+// Op returns an op that hashes every system and passes the record to check. It should run at the end of the Frame
+// op, after all systems have been updated for the tick.
+func (c *Checker) Op(check func(Record)) gt3.Op {
+	return gt3.ContextOpFn(func(ctx gt3.OpContext) {
+		check(c.Hash(ctx.Frame.Tick))
+	})
+}
+
+// Mismatch describes the first system found to differ between two records.
+type Mismatch struct {
+	Tick      uint64
+	System    string
+	Want, Got uint64
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("determinism: %s diverged at tick %d: want hash %016x, got %016x", m.System, m.Tick, m.Want, m.Got)
+}
+
+// Compare returns the first system whose hash differs between want and got, or nil if they match. If the records are
+// for different ticks, the mismatch's System is "tick" and its hashes are the ticks.
+func (c *Checker) Compare(want, got Record) *Mismatch {
+	if want.Tick != got.Tick {
+		return &Mismatch{Tick: got.Tick, System: "tick", Want: want.Tick, Got: got.Tick}
+	}
+	for i, name := range c.names {
+		if i >= len(want.Hashes) || i >= len(got.Hashes) || want.Hashes[i] != got.Hashes[i] {
+			var w, g uint64
+			if i < len(want.Hashes) {
+				w = want.Hashes[i]
+			}
+			if i < len(got.Hashes) {
+				g = got.Hashes[i]
+			}
+			return &Mismatch{Tick: got.Tick, System: name, Want: w, Got: g}
+		}
+	}
+	return nil
+}
+
+// Lockstep compares records from two Sims stepped with identical inputs. Records may be submitted from different
+// goroutines and in any order; each tick is compared once both sides have submitted it.
+type Lockstep struct {
+	checker *Checker
+	fn      func(*Mismatch)
+
+	mu      sync.Mutex
+	pending [2]map[uint64]Record
+	failed  bool
+}
+
+// NewLockstep returns a Lockstep comparing records with c's systems and calling fn with the first mismatch found.
+// Later mismatches are not reported.
+func NewLockstep(c *Checker, fn func(*Mismatch)) *Lockstep {
+	return &Lockstep{
+		checker: c,
+		fn:      fn,
+		pending: [2]map[uint64]Record{{}, {}},
+	}
+}
+
+// A returns a check function, for use with Checker.Op, submitting records for the first Sim.
+func (l *Lockstep) A() func(Record) { return func(r Record) { l.submit(0, r) } }
+
+// B returns a check function, for use with Checker.Op, submitting records for the second Sim.
+func (l *Lockstep) B() func(Record) { return func(r Record) { l.submit(1, r) } }
+
+func (l *Lockstep) submit(side int, rec Record) {
+	l.mu.Lock()
+	if l.failed {
+		l.mu.Unlock()
+		return
+	}
+	other, ok := l.pending[1-side][rec.Tick]
+	if !ok {
+		l.pending[side][rec.Tick] = rec
+		l.mu.Unlock()
+		return
+	}
+	delete(l.pending[1-side], rec.Tick)
+
+	want, got := other, rec
+	if side == 0 {
+		want, got = rec, other
+	}
+	m := l.checker.Compare(want, got)
+	l.failed = m != nil
+	l.mu.Unlock()
+
+	if m != nil {
+		l.fn(m)
+	}
+}
+
+// ErrSystems is returned when reading a baseline recorded with different systems than the Checker reading it.
+var ErrSystems = errors.New("determinism: baseline systems do not match checker")
+
+// Recorder writes records to a baseline.
+type Recorder struct {
+	w *bufio.Writer
+	n int
+}
+
+// NewRecorder writes a baseline header for c's systems to w and returns a Recorder for it.
+func (c *Checker) NewRecorder(w io.Writer) (*Recorder, error) {
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.LittleEndian, uint32(len(c.names))); err != nil {
+		return nil, err
+	}
+	for _, name := range c.names {
+		if err := binary.Write(bw, binary.LittleEndian, uint32(len(name))); err != nil {
+			return nil, err
+		}
+		if _, err := bw.WriteString(name); err != nil {
+			return nil, err
+		}
+	}
+	return &Recorder{w: bw, n: len(c.names)}, nil
+}
+
+// Record appends rec to the baseline.
+func (r *Recorder) Record(rec Record) error {
+	if len(rec.Hashes) != r.n {
+		return ErrSystems
+	}
+	if err := binary.Write(r.w, binary.LittleEndian, rec.Tick); err != nil {
+		return err
+	}
+	return binary.Write(r.w, binary.LittleEndian, rec.Hashes)
+}
+
+// Flush writes any buffered records.
+func (r *Recorder) Flush() error {
+	return r.w.Flush()
+}
+
+// Baseline reads records written by a Recorder.
+type Baseline struct {
+	r *bufio.Reader
+	n int
+}
+
+// NewBaseline reads a baseline header from r and returns a Baseline for it. It returns ErrSystems if the baseline
+// wasn't recorded with c's systems.
+func (c *Checker) NewBaseline(r io.Reader) (*Baseline, error) {
+	br := bufio.NewReader(r)
+	var n uint32
+	if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if int(n) != len(c.names) {
+		return nil, ErrSystems
+	}
+	for _, name := range c.names {
+		var size uint32
+		if err := binary.Read(br, binary.LittleEndian, &size); err != nil {
+			return nil, err
+		}
+		if int(size) != len(name) {
+			return nil, ErrSystems
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, err
+		}
+		if string(buf) != name {
+			return nil, ErrSystems
+		}
+	}
+	return &Baseline{r: br, n: int(n)}, nil
+}
+
+// Next returns the next record in the baseline. It returns io.EOF at the end of the baseline.
+func (b *Baseline) Next() (Record, error) {
+	rec := Record{Hashes: make([]uint64, b.n)}
+	if err := binary.Read(b.r, binary.LittleEndian, &rec.Tick); err != nil {
+		return Record{}, err
+	}
+	if err := binary.Read(b.r, binary.LittleEndian, rec.Hashes); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+// Validate returns a check function, for use with Checker.Op, comparing each record against the next record in b and
+// calling fn with the first mismatch found or error reading b. Later mismatches are not reported.
+func (c *Checker) Validate(b *Baseline, fn func(error)) func(Record) {
+	failed := false
+	return func(got Record) {
+		if failed {
+			return
+		}
+		want, err := b.Next()
+		if err != nil {
+			failed = true
+			fn(err)
+			return
+		}
+		if m := c.Compare(want, got); m != nil {
+			failed = true
+			fn(m)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package determinism
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// newChecker returns a Checker hashing two systems whose states are the returned ints.
+func newChecker() (c *Checker, a, b *int64) {
+	a, b = new(int64), new(int64)
+	c = new(Checker)
+	c.Register("a", func(w io.Writer) { binary.Write(w, binary.LittleEndian, *a) })
+	c.Register("b", func(w io.Writer) { binary.Write(w, binary.LittleEndian, *b) })
+	return c, a, b
+}
+
+func TestLockstep(t *testing.T) {
+	c, a, b := newChecker()
+	var mismatches []*Mismatch
+	l := NewLockstep(c, func(m *Mismatch) { mismatches = append(mismatches, m) })
+	sideA, sideB := l.A(), l.B()
+
+	for tick := uint64(0); tick < 6; tick++ {
+		*a, *b = int64(tick), int64(tick)
+		recA := c.Hash(tick)
+		if tick >= 3 {
+			*b = -1
+		}
+		recB := c.Hash(tick)
+		// Submit in either order.
+		if tick%2 == 0 {
+			sideA(recA)
+			sideB(recB)
+		} else {
+			sideB(recB)
+			sideA(recA)
+		}
+	}
+
+	if len(mismatches) != 1 {
+		t.Fatalf("reported %d mismatches; want 1", len(mismatches))
+	}
+	if m := mismatches[0]; m.Tick != 3 || m.System != "b" || m.Want == m.Got {
+		t.Errorf("mismatch = %+v; want system b at tick 3", m)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	c, a, b := newChecker()
+	var buf bytes.Buffer
+	rec, err := c.NewRecorder(&buf)
+	if err != nil {
+		t.Fatalf("NewRecorder() = %v", err)
+	}
+	for tick := uint64(0); tick < 3; tick++ {
+		*a, *b = int64(tick), int64(tick)
+		if err := rec.Record(c.Hash(tick)); err != nil {
+			t.Fatalf("Record(%d) = %v", tick, err)
+		}
+	}
+	if err := rec.Flush(); err != nil {
+		t.Fatalf("Flush() = %v", err)
+	}
+	data := buf.Bytes()
+
+	other := new(Checker)
+	other.Register("a", func(io.Writer) {})
+	if _, err := other.NewBaseline(bytes.NewReader(data)); !errors.Is(err, ErrSystems) {
+		t.Errorf("NewBaseline() with other systems = %v; want %v", err, ErrSystems)
+	}
+
+	tests := []struct {
+		name    string
+		diverge uint64 // Tick at which system a diverges
+		ticks   uint64 // Ticks to validate
+		want    func(error) bool
+	}{
+		{"Match", 3, 3, func(err error) bool { return err == nil }},
+		{"Diverge", 1, 3, func(err error) bool {
+			var m *Mismatch
+			return errors.As(err, &m) && m.Tick == 1 && m.System == "a"
+		}},
+		{"End", 4, 4, func(err error) bool { return errors.Is(err, io.EOF) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := c.NewBaseline(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("NewBaseline() = %v", err)
+			}
+			var errs []error
+			check := c.Validate(base, func(err error) { errs = append(errs, err) })
+			for tick := uint64(0); tick < tt.ticks; tick++ {
+				*a, *b = int64(tick), int64(tick)
+				if tick >= tt.diverge {
+					*a = -1
+				}
+				check(c.Hash(tick))
+			}
+			if len(errs) > 1 {
+				t.Fatalf("reported %d errors; want at most 1: %v", len(errs), errs)
+			}
+			var got error
+			if len(errs) == 1 {
+				got = errs[0]
+			}
+			if !tt.want(got) {
+				t.Errorf("reported %v", got)
+			}
+		})
+	}
+}
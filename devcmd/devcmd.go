@@ -0,0 +1,152 @@
+// Package devcmd binds key chords to developer commands, such as toggling wireframe rendering or reloading shaders.
+// Its bindings are kept apart from a game's own input bindings so that development tools can be dropped from shipping
+// builds without touching player-facing controls.
+package devcmd
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/debugui"
+)
+
+// Chord is a key pressed with an exact set of modifiers.
+type Chord struct {
+	Key  gt3.Key
+	Mods gt3.ModifierKey
+}
+
+func (c Chord) String() string {
+	var parts []string
+	for _, m := range [...]struct {
+		mod  gt3.ModifierKey
+		name string
+	}{
+		{gt3.ModControl, "Ctrl"},
+		{gt3.ModAlt, "Alt"},
+		{gt3.ModShift, "Shift"},
+		{gt3.ModSuper, "Super"},
+	} {
+		if c.Mods&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, keyName(c.Key)), "+")
+}
+
+func keyName(k gt3.Key) string {
+	switch {
+	case k > gt3.KeySpace && k <= gt3.KeyGraveAccent:
+		return string(rune(k))
+	case k >= gt3.KeyF1 && k <= gt3.KeyF25:
+		return fmt.Sprintf("F%d", k-gt3.KeyF1+1)
+	case k == gt3.KeySpace:
+		return "Space"
+	}
+	return fmt.Sprintf("Key(%d)", int(k))
+}
+
+// Command is a named developer command.
+type Command struct {
+	Name  string
+	Chord Chord
+	Run   func()
+}
+
+// Registry maps key chords to developer commands. The zero value is an empty Registry ready for use. A Registry may
+// be modified from any goroutine, but commands run on the goroutine handling events.
+type Registry struct {
+	mu    sync.RWMutex
+	cmds  map[Chord]Command
+	order []Chord
+}
+
+// Bind binds a command to a chord, replacing any command already bound to it.
+func (r *Registry) Bind(name string, chord Chord, run func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cmds == nil {
+		r.cmds = make(map[Chord]Command)
+	}
+	if _, ok := r.cmds[chord]; !ok {
+		r.order = append(r.order, chord)
+	}
+	r.cmds[chord] = Command{Name: name, Chord: chord, Run: run}
+}
+
+// Unbind removes the command bound to chord, if any.
+func (r *Registry) Unbind(chord Chord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.cmds[chord]; !ok {
+		return
+	}
+	delete(r.cmds, chord)
+	for i, c := range r.order {
+		if c == chord {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Commands returns the registered commands in the order they were bound.
+func (r *Registry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmds := make([]Command, len(r.order))
+	for i, c := range r.order {
+		cmds[i] = r.cmds[c]
+	}
+	return cmds
+}
+
+// Event runs the command bound to a pressed key's chord. Other events are ignored.
+func (r *Registry) Event(e gt3.Event, when time.Time) {
+	r.Handle(e)
+}
+
+// Handle runs the command bound to a pressed key's chord and reports whether there was one.
+func (r *Registry) Handle(e gt3.Event) bool {
+	ev, ok := e.(gt3.KeyEvent)
+	if !ok || ev.Action != gt3.Press {
+		return false
+	}
+
+	r.mu.RLock()
+	cmd, ok := r.cmds[Chord{ev.Key, ev.Mods}]
+	r.mu.RUnlock()
+	if ok && cmd.Run != nil {
+		cmd.Run()
+	}
+	return ok
+}
+
+// Events returns an EventHandler that runs bound commands and passes all other events to next.
+func (r *Registry) Events(next gt3.EventHandler) gt3.EventHandler {
+	return gt3.EventHandlerFn(func(e gt3.Event, when time.Time) {
+		if !r.Handle(e) && next != nil {
+			next.Event(e, when)
+		}
+	})
+}
+
+// ColorList is the color of the command list drawn by Draw.
+var ColorList = color.RGBA{0xc0, 0xff, 0xc0, 0xff}
+
+// Draw draws the list of bound commands at (x, y), one per line, sorted by name.
+func (r *Registry) Draw(p debugui.Painter, x, y float64) {
+	cmds := r.Commands()
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	for _, cmd := range cmds {
+		line := cmd.Chord.String() + "  " + cmd.Name
+		p.Text(x, y, line, ColorList)
+		_, h := p.TextSize(line)
+		y += h
+	}
+}
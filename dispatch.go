@@ -0,0 +1,467 @@
+package gt3
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Dispatcher is an EventHandler that delivers events to any number of subscribers. The zero value is an empty
+// Dispatcher ready for use. A Dispatcher may be subscribed to from any goroutine.
+type Dispatcher struct {
+	seq     uint64 // Sequence number of the last dispatched event
+	dropped uint64 // Events dropped by channel subscriptions
+	tick    uint64 // Sim tick during which the last event was dispatched
+
+	sim *Sim // Optional; set by SetSim
+
+	mu     sync.Mutex
+	subs   []*subscriber // Copy-on-write
+	groups map[string]*HandlerGroup
+
+	postMu   sync.Mutex
+	posted   []postedEvent // Events posted with a Sim set, waiting to be dispatched in order
+	draining bool          // Whether an op to dispatch posted events is scheduled
+}
+
+type postedEvent struct {
+	e    Event
+	when time.Time
+}
+
+type subscriber struct {
+	group    *HandlerGroup // nil if not grouped
+	priority int
+	handler  EventHandler // Set by Subscribe
+	// deliver delivers an event to the subscriber and reports whether the event was consumed.
+	deliver func(seq, tick uint64, e Event, when time.Time) (consumed bool)
+}
+
+// SetSim sets the Sim whose tick is recorded for each dispatched event, as reported by Tick and Sequenced.Tick. Events
+// are normally dispatched while the Sim drains its event sources in PreFrame, so the recorded tick is the tick whose
+// Frame op will first see the event. SetSim must be called before events are dispatched.
+func (d *Dispatcher) SetSim(s *Sim) {
+	d.sim = s
+}
+
+// Tick returns the sim tick during which the most recently dispatched event was dispatched. It is zero if no Sim is
+// set.
+func (d *Dispatcher) Tick() uint64 {
+	return atomic.LoadUint64(&d.tick)
+}
+
+// Event assigns the event the next sequence number and delivers it to the Dispatcher's subscribers in order of
+// descending priority, stopping at the first handler that consumes it.
+func (d *Dispatcher) Event(e Event, when time.Time) {
+	seq := atomic.AddUint64(&d.seq, 1)
+	var tick uint64
+	if d.sim != nil {
+		tick = d.sim.Tick()
+		atomic.StoreUint64(&d.tick, tick)
+		if t := d.sim.tracer; t != nil {
+			defer t.StartSpan(SpanInfo{
+				Name:  SpanEvent,
+				Tick:  tick,
+				Event: fmt.Sprintf("%T", e),
+				Main:  d.sim.onMainGoroutine(),
+				When:  when,
+			})()
+		}
+	}
+
+	d.mu.Lock()
+	subs := d.subs
+	d.mu.Unlock()
+
+	for _, s := range subs {
+		if s.group != nil && !s.group.Enabled() {
+			continue
+		}
+		if s.deliver(seq, tick, e, when) {
+			return
+		}
+	}
+}
+
+// Post dispatches an application-defined event, such as a gameplay or network message, through d alongside window
+// events. Post may be called from any goroutine. The event is timestamped with the time of the call and, if a Sim is
+// set, queued and dispatched on the main goroutine by an op scheduled with Sched, before the Frame op of a later tick.
+// Posted events are dispatched in the order they were posted; events posted from the same goroutine are dispatched in
+// the order of the calls. Without a Sim, or once the Sim has stopped, it is dispatched immediately on the calling
+// goroutine.
+//
+// Event types defined outside gt3 embed CustomEvent.
+func (d *Dispatcher) Post(e Event) {
+	when := time.Now()
+	if d.sim == nil {
+		d.Event(e, when)
+		return
+	}
+	d.postMu.Lock()
+	d.posted = append(d.posted, postedEvent{e, when})
+	if d.sim.stopping() {
+		// Scheduled ops no longer run, so dispatch here, along with any events left by an op scheduled just before the
+		// Sim stopped.
+		d.postMu.Unlock()
+		d.drainPosted()
+		return
+	}
+	schedule := !d.draining
+	d.draining = true
+	d.postMu.Unlock()
+	if schedule {
+		d.sim.Sched(OpFn(func(float64, float64, time.Time) { d.drainPosted() }))
+	}
+}
+
+// drainPosted dispatches posted events in order. Events posted while draining are left for the next scheduled op.
+func (d *Dispatcher) drainPosted() {
+	d.postMu.Lock()
+	posted := d.posted
+	d.posted, d.draining = nil, false
+	d.postMu.Unlock()
+	for _, p := range posted {
+		d.Event(p.e, p.when)
+	}
+}
+
+// Seq returns the sequence number of the most recently dispatched event. Sequence numbers start at 1 and increase by
+// one for every event dispatched, so handlers called by the Dispatcher may use Seq to identify the event being
+// handled, provided events are only dispatched from one goroutine.
+func (d *Dispatcher) Seq() uint64 {
+	return atomic.LoadUint64(&d.seq)
+}
+
+// Dropped returns the number of events dropped or coalesced by channel subscriptions to d due to full buffers.
+func (d *Dispatcher) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+func (d *Dispatcher) subscribe(s *subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Insert after all subscribers of equal or higher priority so that equal priorities keep subscription order.
+	i := sort.Search(len(d.subs), func(i int) bool { return d.subs[i].priority < s.priority })
+	subs := make([]*subscriber, 0, len(d.subs)+1)
+	subs = append(subs, d.subs[:i]...)
+	subs = append(subs, s)
+	d.subs = append(subs, d.subs[i:]...)
+}
+
+// Handle registers fn as a handler for events of type T dispatched by d. Handlers and channel subscriptions receive
+// events in order of descending priority, and in the order they were registered for equal priorities. Channel
+// subscriptions have priority 0. If fn returns true, the event is consumed and is not delivered to any subscriber
+// after it, so a modal dialog's key handler can take priority over gameplay handlers. fn is called on the dispatching
+// goroutine. Calling cancel removes the handler.
+func Handle[T Event](d *Dispatcher, priority int, fn func(e T, when time.Time) (consumed bool)) (cancel func()) {
+	return handle(d, nil, priority, fn)
+}
+
+// HandleGroup is the same as Handle, except that the handler belongs to g and only receives events while g is enabled.
+func HandleGroup[T Event](g *HandlerGroup, priority int, fn func(e T, when time.Time) (consumed bool)) (cancel func()) {
+	return handle(g.d, g, priority, fn)
+}
+
+func handle[T Event](d *Dispatcher, g *HandlerGroup, priority int, fn func(T, time.Time) bool) func() {
+	s := &subscriber{group: g, priority: priority, deliver: func(_, _ uint64, e Event, when time.Time) bool {
+		ev, ok := e.(T)
+		return ok && fn(ev, when)
+	}}
+	d.subscribe(s)
+	return func() { d.unsubscribe(s) }
+}
+
+// Subscribe adds handler as a subscriber receiving events of the given types, or all events if no types are given,
+// such that a Dispatcher passed to SetEventCallbacks fans each of a window's events out to any number of handlers.
+// Handlers have priority 0 and receive events in the order they were subscribed, along with other subscriptions of
+// equal priority. Calling cancel removes the subscription. For a channel of events of one type, use SubscribeChan.
+func (d *Dispatcher) Subscribe(handler EventHandler, eventTypes ...Event) (cancel func()) {
+	var types map[reflect.Type]bool
+	if len(eventTypes) > 0 {
+		types = make(map[reflect.Type]bool, len(eventTypes))
+		for _, e := range eventTypes {
+			types[reflect.TypeOf(e)] = true
+		}
+	}
+
+	s := &subscriber{handler: handler, deliver: func(_, _ uint64, e Event, when time.Time) bool {
+		if types == nil || types[reflect.TypeOf(e)] {
+			handler.Event(e, when)
+		}
+		return false
+	}}
+	d.subscribe(s)
+	return func() { d.unsubscribe(s) }
+}
+
+// Unsubscribe removes all subscriptions of handler added by Subscribe. Handlers are compared with ==, so handlers of
+// uncomparable types, such as EventHandlerFn, can only be removed by calling the cancel function returned by
+// Subscribe.
+func (d *Dispatcher) Unsubscribe(handler EventHandler) {
+	if handler == nil || !reflect.TypeOf(handler).Comparable() {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	subs := make([]*subscriber, 0, len(d.subs))
+	for _, s := range d.subs {
+		if s.handler == nil || !reflect.TypeOf(s.handler).Comparable() || s.handler != handler {
+			subs = append(subs, s)
+		}
+	}
+	d.subs = subs
+}
+
+// HandlerGroup is a named set of handlers, such as "gameplay", "ui", or "debug", that can be enabled and disabled
+// together. Groups are enabled when created.
+type HandlerGroup struct {
+	disabled int32 // Accessed atomically
+
+	d    *Dispatcher
+	name string
+}
+
+// Group returns the Dispatcher's handler group with the given name, creating it if it doesn't exist.
+func (d *Dispatcher) Group(name string) *HandlerGroup {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if g, ok := d.groups[name]; ok {
+		return g
+	}
+	if d.groups == nil {
+		d.groups = make(map[string]*HandlerGroup)
+	}
+	g := &HandlerGroup{d: d, name: name}
+	d.groups[name] = g
+	return g
+}
+
+// SetGroups enables every named group in enable and disables every other group, so that a mode switch, such as
+// entering a cutscene, is a single call. Groups named in enable are created if they don't exist.
+func (d *Dispatcher) SetGroups(enable ...string) {
+	for _, name := range enable {
+		d.Group(name)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, g := range d.groups {
+		on := false
+		for _, e := range enable {
+			on = on || e == name
+		}
+		g.SetEnabled(on)
+	}
+}
+
+// Name returns the group's name.
+func (g *HandlerGroup) Name() string { return g.name }
+
+// Enabled reports whether the group's handlers receive events.
+func (g *HandlerGroup) Enabled() bool {
+	return atomic.LoadInt32(&g.disabled) == 0
+}
+
+// SetEnabled enables or disables all of the group's handlers. Changes take effect for the next dispatched event.
+func (g *HandlerGroup) SetEnabled(enabled bool) {
+	var v int32
+	if !enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&g.disabled, v)
+}
+
+func (d *Dispatcher) unsubscribe(s *subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, sub := range d.subs {
+		if sub != s {
+			continue
+		}
+		subs := make([]*subscriber, 0, len(d.subs)-1)
+		subs = append(subs, d.subs[:i]...)
+		d.subs = append(subs, d.subs[i+1:]...)
+		return
+	}
+}
+
+// SubscriptionBuffer is the default capacity of channels returned by SubscribeChan.
+const SubscriptionBuffer = 64
+
+// OverflowPolicy determines what a channel subscription does with an event when its channel's buffer is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// OverflowDropNewest drops the new event. This is the default.
+	OverflowDropNewest OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest buffered event to make room for the new event.
+	OverflowDropOldest
+	// OverflowBlock blocks the dispatching goroutine until there is room for the event or the subscription is
+	// cancelled. A blocked subscription blocks delivery to all later subscribers, so it should only be used by consumers
+	// that keep up with events, or for events that must not be lost, such as CloseEvent.
+	OverflowBlock
+	// OverflowCoalesce drops the most recently buffered event of the same type in favor of the new event, such that
+	// only the latest of a run of events like CursorPosEvent is kept. If no event of the same type is buffered, the
+	// oldest buffered event is dropped.
+	OverflowCoalesce
+)
+
+type subscribeConfig struct {
+	buffer   int
+	overflow OverflowPolicy
+}
+
+// SubscribeOption configures a channel subscription created by SubscribeChan or SubscribeSequenced.
+type SubscribeOption func(*subscribeConfig)
+
+// Overflow sets the subscription's overflow policy.
+func Overflow(p OverflowPolicy) SubscribeOption {
+	return func(c *subscribeConfig) { c.overflow = p }
+}
+
+// BufferSize sets the capacity of the subscription's channel. Sizes less than 1 are treated as 1.
+func BufferSize(n int) SubscribeOption {
+	return func(c *subscribeConfig) { c.buffer = n }
+}
+
+// SubscribeChan returns a channel receiving all events of type T dispatched by d. Unless the subscription uses
+// OverflowBlock, events are delivered without blocking the dispatching goroutine, and events are dropped or coalesced
+// according to the subscription's overflow policy if the channel's buffer is full. Calling cancel unsubscribes from d
+// and closes the channel.
+func SubscribeChan[T Event](d *Dispatcher, opts ...SubscribeOption) (events <-chan T, cancel func()) {
+	return subscribeChannel(d, opts, func(_, _ uint64, e Event, _ time.Time) (T, bool) {
+		ev, ok := e.(T)
+		return ev, ok
+	}, func(ev T) Event { return ev })
+}
+
+// Sequenced is an event paired with its Dispatcher sequence number, dispatch time, and, if the Dispatcher has a Sim,
+// the sim tick it was dispatched during.
+type Sequenced[T Event] struct {
+	Seq   uint64
+	Tick  uint64
+	Event T
+	When  time.Time
+}
+
+// SubscribeSequenced is the same as SubscribeChan, except that events are delivered with their sequence numbers and
+// dispatch times. Gaps between sequence numbers indicate either events of other types or dropped events.
+func SubscribeSequenced[T Event](d *Dispatcher, opts ...SubscribeOption) (events <-chan Sequenced[T], cancel func()) {
+	return subscribeChannel(d, opts, func(seq, tick uint64, e Event, when time.Time) (Sequenced[T], bool) {
+		ev, ok := e.(T)
+		return Sequenced[T]{seq, tick, ev, when}, ok
+	}, func(ev Sequenced[T]) Event { return ev.Event })
+}
+
+func subscribeChannel[T any](
+	d *Dispatcher,
+	opts []SubscribeOption,
+	filter func(uint64, uint64, Event, time.Time) (T, bool),
+	eventOf func(T) Event,
+) (<-chan T, func()) {
+	conf := subscribeConfig{buffer: SubscriptionBuffer}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if conf.buffer < 1 {
+		conf.buffer = 1
+	}
+
+	var (
+		ch     = make(chan T, conf.buffer)
+		done   = make(chan struct{})
+		mu     sync.Mutex
+		closed bool
+	)
+
+	s := &subscriber{deliver: func(seq, tick uint64, e Event, when time.Time) bool {
+		ev, ok := filter(seq, tick, e, when)
+		if !ok {
+			return false
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return false
+		}
+		select {
+		case ch <- ev:
+			return false
+		default:
+		}
+
+		switch conf.overflow {
+		case OverflowBlock:
+			select {
+			case ch <- ev:
+			case <-done:
+			}
+			return false
+		case OverflowDropOldest:
+			select {
+			case <-ch:
+			default:
+			}
+		case OverflowCoalesce:
+			coalesce(ch, ev, eventOf)
+		}
+
+		atomic.AddUint64(&d.dropped, 1)
+		select {
+		case ch <- ev:
+		default:
+		}
+		return false
+	}}
+	d.subscribe(s)
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { close(done) })
+		d.unsubscribe(s)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}
+}
+
+// coalesce drains ch and refills it without the most recently buffered event of the same type as ev, or without the
+// oldest buffered event if there is none. Since the consumer may receive concurrently, the refilled buffer never
+// exceeds the channel's capacity.
+func coalesce[T any](ch chan T, ev T, eventOf func(T) Event) {
+	var pending []T
+	for {
+		select {
+		case p := <-ch:
+			pending = append(pending, p)
+			continue
+		default:
+		}
+		break
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	drop, typ := 0, reflect.TypeOf(eventOf(ev))
+	for i := len(pending) - 1; i >= 0; i-- {
+		if reflect.TypeOf(eventOf(pending[i])) == typ {
+			drop = i
+			break
+		}
+	}
+	pending = append(pending[:drop], pending[drop+1:]...)
+	for _, p := range pending {
+		ch <- p
+	}
+}
@@ -0,0 +1,166 @@
+package gt3
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOverflow(t *testing.T) {
+	key := func(n int) Event { return KeyEvent{Code: n} }
+	pos := func(n int) Event { return CursorPosEvent{X: float64(n)} }
+
+	tests := []struct {
+		name    string
+		policy  OverflowPolicy
+		events  []Event
+		want    []Event
+		dropped uint64
+	}{
+		{"DropNewest", OverflowDropNewest, []Event{key(1), key(2), key(3)}, []Event{key(1), key(2)}, 1},
+		{"DropOldest", OverflowDropOldest, []Event{key(1), key(2), key(3)}, []Event{key(2), key(3)}, 1},
+		{"DropOldestTwice", OverflowDropOldest, []Event{key(1), key(2), key(3), key(4)}, []Event{key(3), key(4)}, 2},
+		{"Coalesce", OverflowCoalesce, []Event{pos(1), key(1), pos(2)}, []Event{key(1), pos(2)}, 1},
+		{"CoalesceLatest", OverflowCoalesce, []Event{pos(1), pos(2), pos(3)}, []Event{pos(1), pos(3)}, 1},
+		{"CoalesceOldest", OverflowCoalesce, []Event{key(1), key(2), pos(1)}, []Event{key(2), pos(1)}, 1},
+		{"Fits", OverflowDropNewest, []Event{key(1), pos(1)}, []Event{key(1), pos(1)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Dispatcher
+			ch, cancel := SubscribeChan[Event](&d, BufferSize(2), Overflow(tt.policy))
+			for _, e := range tt.events {
+				d.Event(e, time.Time{})
+			}
+			cancel()
+
+			var got []Event
+			for e := range ch {
+				got = append(got, e)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("received %v; want %v", got, tt.want)
+			}
+			if d.Dropped() != tt.dropped {
+				t.Errorf("Dropped() = %d; want %d", d.Dropped(), tt.dropped)
+			}
+		})
+	}
+}
+
+func TestOverflowBlock(t *testing.T) {
+	var d Dispatcher
+	ch, cancel := SubscribeChan[KeyEvent](&d, BufferSize(1), Overflow(OverflowBlock))
+
+	dispatched := make(chan int)
+	go func() {
+		for i := 1; i <= 3; i++ {
+			d.Event(KeyEvent{Code: i}, time.Time{})
+			dispatched <- i
+		}
+	}()
+
+	// The first event is buffered, and the second blocks until the first is received.
+	<-dispatched
+	select {
+	case <-dispatched:
+		t.Fatal("second event dispatched before the buffer had room")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if e := <-ch; e.Code != 1 {
+		t.Fatalf("received %v; want Code 1", e)
+	}
+	<-dispatched
+
+	// Cancelling unblocks the third event, which is dropped.
+	go cancel()
+	<-dispatched
+	for e := range ch {
+		if e.Code != 2 {
+			t.Errorf("received %v after cancel; want only Code 2", e)
+		}
+	}
+	if d.Dropped() != 0 {
+		t.Errorf("Dropped() = %d; want 0", d.Dropped())
+	}
+}
+
+type postedTestEvent struct {
+	CustomEvent
+	producer, n int
+}
+
+func TestPost(t *testing.T) {
+	const (
+		producers = 4
+		posts     = 500
+	)
+
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewSim(64, 0, nil)
+	s.SetClock(clock)
+	var d Dispatcher
+	d.SetSim(s)
+
+	var got []postedTestEvent
+	d.Subscribe(EventHandlerFn(func(e Event, _ time.Time) {
+		if !s.IsMainThread() {
+			t.Error("posted event dispatched off the main goroutine")
+		}
+		got = append(got, e.(postedTestEvent))
+	}))
+	s.Start()
+
+	done := make(chan struct{})
+	for p := 0; p < producers; p++ {
+		go func(p int) {
+			for n := 0; n < posts; n++ {
+				d.Post(postedTestEvent{producer: p, n: n})
+			}
+			done <- struct{}{}
+		}(p)
+	}
+	for p := 0; p < producers; p++ {
+		<-done
+	}
+	if len(got) != 0 {
+		t.Fatalf("%d events dispatched before the Sim ran", len(got))
+	}
+	clock.AdvanceSeconds(1.0 / 64)
+	if err := s.Step(); err != nil {
+		t.Fatalf("Step() = %v; want nil", err)
+	}
+
+	if len(got) != producers*posts {
+		t.Fatalf("dispatched %d events; want %d", len(got), producers*posts)
+	}
+	next := make([]int, producers)
+	for _, e := range got {
+		if e.n != next[e.producer] {
+			t.Fatalf("producer %d: dispatched event %d; want %d", e.producer, e.n, next[e.producer])
+		}
+		next[e.producer]++
+	}
+}
+
+func TestPostStopped(t *testing.T) {
+	s := NewSim(64, 0, nil)
+	s.SetClock(NewManualClock(time.Unix(0, 0)))
+	var d Dispatcher
+	d.SetSim(s)
+
+	var got []int
+	d.Subscribe(EventHandlerFn(func(e Event, _ time.Time) {
+		got = append(got, e.(postedTestEvent).n)
+	}))
+
+	// The first event's drain op is scheduled but never runs, since the Sim stops first. Posting after the Sim stops
+	// dispatches it immediately, along with the first.
+	d.Post(postedTestEvent{n: 1})
+	s.Stop()
+	d.Post(postedTestEvent{n: 2})
+	d.Post(postedTestEvent{n: 3})
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dispatched %v; want %v", got, want)
+	}
+}
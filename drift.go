@@ -0,0 +1,44 @@
+package gt3
+
+import (
+	"time"
+)
+
+const (
+	// driftInterval is how often, in timer seconds, a running Sim measures drift between its timer and the wall clock.
+	driftInterval = 5.0
+	// driftSlew is the largest correction, in seconds, applied to the wall clock mapping per measurement, so that Time
+	// and RealTime converge on the wall clock gradually instead of jumping.
+	driftSlew = 0.002
+	// driftStep is the drift, in seconds, beyond which the wall clock mapping is corrected immediately rather than
+	// slewed, such as when the system clock is set.
+	driftStep = 1.0
+)
+
+// Drift returns the difference between the wall clock and the Sim's RealTime as of the last drift measurement. A
+// positive drift means the Sim's clock is behind the wall clock.
+func (s *Sim) Drift() time.Duration {
+	return time.Duration(s.drift * float64(time.Second))
+}
+
+// checkDrift periodically compares the Sim's mapping of its timer to wall time against its Clock's wall time and
+// nudges the mapping towards it. Only the wall time reported by Time and RealTime is affected; simulation time is not.
+func (s *Sim) checkDrift() {
+	now := s.Now()
+	if now < s.nextDrift {
+		return
+	}
+	s.nextDrift = now + driftInterval
+
+	drift := s.clock.Wall().Sub(s.realtime(now)).Seconds()
+	s.drift = drift
+
+	switch {
+	case drift > driftStep || drift < -driftStep:
+	case drift > driftSlew:
+		drift = driftSlew
+	case drift < -driftSlew:
+		drift = -driftSlew
+	}
+	s.wallOffset += drift
+}
@@ -0,0 +1,209 @@
+// Package drop classifies files dropped onto a window and delivers them as typed events. Images and text are decoded
+// off the main goroutine, and the resulting events are posted through Sim.Sched.
+package drop
+
+import (
+	"image"
+	"image/draw"
+	_ "image/gif"  // Register GIF decoding
+	_ "image/jpeg" // Register JPEG decoding
+	_ "image/png"  // Register PNG decoding
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.spiff.io/gt3"
+)
+
+// Kind is the classification of a dropped path.
+type Kind int
+
+// Kinds.
+const (
+	KindFile Kind = iota // Unrecognized file
+	KindDirectory
+	KindImage
+	KindAudio
+	KindText
+)
+
+// Extensions used to classify files, by lowercase extension including the dot. They may be modified before a Handler
+// is used.
+var (
+	ImageExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+	AudioExts = map[string]bool{".wav": true, ".ogg": true, ".mp3": true, ".flac": true, ".opus": true}
+	TextExts  = map[string]bool{".txt": true, ".md": true, ".json": true, ".toml": true, ".yaml": true, ".yml": true,
+		".csv": true, ".xml": true, ".ini": true, ".cfg": true, ".log": true}
+)
+
+// Classify returns the kind of the file at path. Directories are detected with os.Stat; files are classified by
+// extension.
+func Classify(path string) Kind {
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		return KindDirectory
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ImageExts[ext]:
+		return KindImage
+	case AudioExts[ext]:
+		return KindAudio
+	case TextExts[ext]:
+		return KindText
+	}
+	return KindFile
+}
+
+// Follow-up events posted for each dropped path.
+type (
+	ImageDroppedEvent struct {
+		gt3.CustomEvent
+		Window *gt3.Window
+		Path   string
+		Image  *image.RGBA
+	}
+
+	TextDroppedEvent struct {
+		gt3.CustomEvent
+		Window *gt3.Window
+		Path   string
+		Text   string
+	}
+
+	// AudioDroppedEvent is posted for audio files. Audio is not decoded.
+	AudioDroppedEvent struct {
+		gt3.CustomEvent
+		Window *gt3.Window
+		Path   string
+	}
+
+	DirectoryDroppedEvent struct {
+		gt3.CustomEvent
+		Window *gt3.Window
+		Path   string
+	}
+
+	// FileDroppedEvent is posted for files of unrecognized kinds.
+	FileDroppedEvent struct {
+		gt3.CustomEvent
+		Window *gt3.Window
+		Path   string
+	}
+
+	// DropErrorEvent is posted when a dropped image or text file can't be read or decoded.
+	DropErrorEvent struct {
+		gt3.CustomEvent
+		Window *gt3.Window
+		Path   string
+		Kind   Kind
+		Err    error
+	}
+)
+
+// DefaultMaxTextSize is the default limit on the size of dropped text files.
+const DefaultMaxTextSize = 1 << 20
+
+// Handler is an EventHandler that converts DropEvents into typed follow-up events, posted to Next via the Sim's Sched.
+// The DropEvent itself is passed to Next immediately. All other events are passed through.
+type Handler struct {
+	Next gt3.EventHandler
+	// MaxTextSize is the largest text file read. Larger files, and files that aren't valid UTF-8, are posted as
+	// FileDroppedEvents. Defaults to DefaultMaxTextSize.
+	MaxTextSize int64
+
+	sim *gt3.Sim
+}
+
+// NewHandler returns a Handler posting follow-up events to next through s.
+func NewHandler(s *gt3.Sim, next gt3.EventHandler) *Handler {
+	return &Handler{Next: next, MaxTextSize: DefaultMaxTextSize, sim: s}
+}
+
+func (h *Handler) Event(e gt3.Event, when time.Time) {
+	h.Next.Event(e, when)
+	if ev, ok := e.(gt3.DropEvent); ok {
+		names := append([]string(nil), ev.Names...)
+		go h.convert(ev.Window, names)
+	}
+}
+
+func (h *Handler) post(e gt3.Event) {
+	h.sim.Sched(gt3.OpFn(func(_, _ float64, when time.Time) {
+		h.Next.Event(e, when)
+	}))
+}
+
+// convert classifies and decodes paths. It runs on its own goroutine.
+func (h *Handler) convert(w *gt3.Window, paths []string) {
+	for _, path := range paths {
+		switch kind := Classify(path); kind {
+		case KindDirectory:
+			h.post(DirectoryDroppedEvent{Window: w, Path: path})
+		case KindAudio:
+			h.post(AudioDroppedEvent{Window: w, Path: path})
+		case KindImage:
+			img, err := decodeImage(path)
+			if err != nil {
+				h.post(DropErrorEvent{Window: w, Path: path, Kind: kind, Err: err})
+				continue
+			}
+			h.post(ImageDroppedEvent{Window: w, Path: path, Image: img})
+		case KindText:
+			text, ok, err := h.readText(path)
+			switch {
+			case err != nil:
+				h.post(DropErrorEvent{Window: w, Path: path, Kind: kind, Err: err})
+			case ok:
+				h.post(TextDroppedEvent{Window: w, Path: path, Text: text})
+			default:
+				h.post(FileDroppedEvent{Window: w, Path: path})
+			}
+		default:
+			h.post(FileDroppedEvent{Window: w, Path: path})
+		}
+	}
+}
+
+func decodeImage(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	if rgba, ok := src.(*image.RGBA); ok {
+		return rgba, nil
+	}
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	return rgba, nil
+}
+
+func (h *Handler) readText(path string) (text string, ok bool, err error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", false, err
+	}
+	max := h.MaxTextSize
+	if max <= 0 {
+		max = DefaultMaxTextSize
+	}
+	if fi.Size() > max {
+		return "", false, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	if !utf8.Valid(b) {
+		return "", false, nil
+	}
+	return string(b), true, nil
+}
@@ -0,0 +1,73 @@
+// Package ease provides smoothing functions that behave the same regardless of step size. Each takes the elapsed time
+// dt, normally the sim step passed to ops, so that camera and UI smoothing don't change character when a Sim's FPS
+// changes.
+package ease
+
+import "math"
+
+// Lerp linearly interpolates from a to b by t.
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// Factor converts a per-step interpolation factor into one for dt. If f is the fraction of the remaining distance
+// covered in refStep seconds, Factor returns the fraction covered in dt seconds. For example, a lerp tuned with
+// factor 0.1 at 60 FPS behaves identically at any rate with Lerp(a, b, Factor(0.1, 1.0/60, dt)).
+func Factor(f, refStep, dt float64) float64 {
+	if refStep <= 0 {
+		return f
+	}
+	return 1 - math.Pow(1-f, dt/refStep)
+}
+
+// Damp exponentially moves current towards target, where lambda is the decay rate per second. Larger values of lambda
+// converge faster.
+func Damp(current, target, lambda, dt float64) float64 {
+	return Lerp(current, target, 1-math.Exp(-lambda*dt))
+}
+
+// HalfLife exponentially moves current towards target such that half of the remaining distance is covered every
+// halfLife seconds.
+func HalfLife(current, target, halfLife, dt float64) float64 {
+	if halfLife <= 0 {
+		return target
+	}
+	return Lerp(current, target, 1-math.Exp2(-dt/halfLife))
+}
+
+// Spring is a critically damped spring, which approaches its target as quickly as possible without overshooting. The
+// zero value is a spring at rest at zero.
+type Spring struct {
+	Value    float64
+	Velocity float64
+}
+
+// Update advances the spring towards target by dt seconds. smoothTime is roughly the time the spring takes to reach
+// its target.
+func (s *Spring) Update(target, smoothTime, dt float64) float64 {
+	if smoothTime <= 0 {
+		s.Value, s.Velocity = target, 0
+		return target
+	}
+
+	// Critically damped spring approximation from Game Programming Gems 4, "Critically Damped Ease-In/Ease-Out
+	// Smoothing".
+	omega := 2 / smoothTime
+	x := omega * dt
+	decay := 1 / (1 + x + 0.48*x*x + 0.235*x*x*x)
+	change := s.Value - target
+	temp := (s.Velocity + omega*change) * dt
+	s.Velocity = (s.Velocity - omega*temp) * decay
+	s.Value = target + (change+temp)*decay
+	return s.Value
+}
+
+// Spring2 is a Spring for 2D values.
+type Spring2 struct {
+	X, Y Spring
+}
+
+// Update advances the spring towards (tx, ty) by dt seconds.
+func (s *Spring2) Update(tx, ty, smoothTime, dt float64) (x, y float64) {
+	return s.X.Update(tx, smoothTime, dt), s.Y.Update(ty, smoothTime, dt)
+}
@@ -0,0 +1,166 @@
+package ease
+
+import (
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestLerp(t *testing.T) {
+	tests := []struct {
+		a, b, t, want float64
+	}{
+		{0, 10, 0, 0},
+		{0, 10, 1, 10},
+		{0, 10, 0.25, 2.5},
+		{-4, 4, 0.5, 0},
+		{10, 0, 0.1, 9},
+		{0, 10, 2, 20},
+	}
+	for _, tt := range tests {
+		if got := Lerp(tt.a, tt.b, tt.t); !near(got, tt.want) {
+			t.Errorf("Lerp(%v, %v, %v) = %v; want %v", tt.a, tt.b, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		name           string
+		f, refStep, dt float64
+		want           float64
+	}{
+		{"RefStep", 0.1, 1.0 / 60, 1.0 / 60, 0.1},
+		{"DoubleStep", 0.1, 1.0 / 60, 2.0 / 60, 1 - 0.9*0.9},
+		{"ZeroDt", 0.5, 1, 0, 0},
+		{"One", 1, 1, 0.5, 1},
+		{"ZeroRefStep", 0.3, 0, 1, 0.3},
+	}
+	for _, tt := range tests {
+		if got := Factor(tt.f, tt.refStep, tt.dt); !near(got, tt.want) {
+			t.Errorf("%s: Factor(%v, %v, %v) = %v; want %v", tt.name, tt.f, tt.refStep, tt.dt, got, tt.want)
+		}
+	}
+}
+
+// TestStepIndependence checks that smoothing over one step gives the same result as smoothing over several smaller
+// steps covering the same time.
+func TestStepIndependence(t *testing.T) {
+	const dt = 0.1
+	tests := []struct {
+		name string
+		fn   func(current, dt float64) float64
+	}{
+		{"Factor", func(c, dt float64) float64 { return Lerp(c, 100, Factor(0.2, 1.0/60, dt)) }},
+		{"Damp", func(c, dt float64) float64 { return Damp(c, 100, 5, dt) }},
+		{"HalfLife", func(c, dt float64) float64 { return HalfLife(c, 100, 0.25, dt) }},
+	}
+	for _, tt := range tests {
+		for _, n := range []int{2, 3, 10} {
+			want := tt.fn(0, dt)
+			got := 0.0
+			for i := 0; i < n; i++ {
+				got = tt.fn(got, dt/float64(n))
+			}
+			if !near(got, want) {
+				t.Errorf("%s: %d steps of %v = %v; want %v", tt.name, n, dt/float64(n), got, want)
+			}
+		}
+	}
+}
+
+func TestDamp(t *testing.T) {
+	tests := []struct {
+		current, target, lambda, dt, want float64
+	}{
+		{0, 10, 1, 0, 0},
+		{0, 10, math.Ln2, 1, 5},
+		{10, 0, math.Ln2, 2, 2.5},
+		{3, 3, 4, 1, 3},
+	}
+	for _, tt := range tests {
+		if got := Damp(tt.current, tt.target, tt.lambda, tt.dt); !near(got, tt.want) {
+			t.Errorf("Damp(%v, %v, %v, %v) = %v; want %v", tt.current, tt.target, tt.lambda, tt.dt, got, tt.want)
+		}
+	}
+}
+
+func TestHalfLife(t *testing.T) {
+	tests := []struct {
+		current, target, halfLife, dt, want float64
+	}{
+		{0, 10, 1, 1, 5},
+		{0, 10, 0.5, 1, 7.5},
+		{-8, 0, 2, 1, -8 / math.Sqrt2},
+		{0, 10, 1, 0, 0},
+		{0, 10, 0, 1, 10},
+		{0, 10, -1, 1, 10},
+	}
+	for _, tt := range tests {
+		if got := HalfLife(tt.current, tt.target, tt.halfLife, tt.dt); !near(got, tt.want) {
+			t.Errorf("HalfLife(%v, %v, %v, %v) = %v; want %v", tt.current, tt.target, tt.halfLife, tt.dt, got, tt.want)
+		}
+	}
+}
+
+func TestSpring(t *testing.T) {
+	tests := []struct {
+		name               string
+		start, target      float64
+		smoothTime, dt     float64
+		steps              int
+		wantSettledWithin  float64
+		wantNoOvershoot    bool
+		wantSnapsOnFirstDt bool
+	}{
+		{name: "Forward", start: 0, target: 10, smoothTime: 0.3, dt: 1.0 / 60, steps: 240,
+			wantSettledWithin: 1e-3, wantNoOvershoot: true},
+		{name: "Backward", start: 5, target: -5, smoothTime: 0.5, dt: 1.0 / 30, steps: 120,
+			wantSettledWithin: 1e-3, wantNoOvershoot: true},
+		{name: "LargeStep", start: 0, target: 1, smoothTime: 0.1, dt: 0.5, steps: 10,
+			wantSettledWithin: 1e-3, wantNoOvershoot: true},
+		{name: "ZeroSmoothTime", start: 0, target: 7, smoothTime: 0, dt: 1.0 / 60, steps: 1,
+			wantSnapsOnFirstDt: true},
+		{name: "NegativeSmoothTime", start: 3, target: 7, smoothTime: -1, dt: 1.0 / 60, steps: 1,
+			wantSnapsOnFirstDt: true},
+	}
+	for _, tt := range tests {
+		s := Spring{Value: tt.start}
+		dir := math.Copysign(1, tt.target-tt.start)
+		for i := 0; i < tt.steps; i++ {
+			v := s.Update(tt.target, tt.smoothTime, tt.dt)
+			if v != s.Value {
+				t.Fatalf("%s: Update returned %v; Value is %v", tt.name, v, s.Value)
+			}
+			if tt.wantNoOvershoot && (v-tt.target)*dir > tolerance {
+				t.Fatalf("%s: step %d overshot %v with %v", tt.name, i, tt.target, v)
+			}
+		}
+		if tt.wantSnapsOnFirstDt && (s.Value != tt.target || s.Velocity != 0) {
+			t.Errorf("%s: spring = %+v; want at rest at %v", tt.name, s, tt.target)
+		}
+		if tt.wantSettledWithin > 0 && math.Abs(s.Value-tt.target) > tt.wantSettledWithin {
+			t.Errorf("%s: spring = %+v after %d steps; want within %v of %v",
+				tt.name, s, tt.steps, tt.wantSettledWithin, tt.target)
+		}
+	}
+}
+
+func TestSpring2(t *testing.T) {
+	var s Spring2
+	var x, y float64
+	for i := 0; i < 240; i++ {
+		x, y = s.Update(4, -2, 0.25, 1.0/60)
+	}
+	if math.Abs(x-4) > 1e-3 || math.Abs(y+2) > 1e-3 {
+		t.Errorf("Spring2 settled at (%v, %v); want (4, -2)", x, y)
+	}
+	if x != s.X.Value || y != s.Y.Value {
+		t.Errorf("Update returned (%v, %v); springs hold (%v, %v)", x, y, s.X.Value, s.Y.Value)
+	}
+}
@@ -2,8 +2,6 @@ package gt3
 
 import (
 	"time"
-
-	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
 // Event handling
@@ -16,62 +14,6 @@ type EventHandlerFn func(Event, time.Time)
 
 func (fn EventHandlerFn) Event(e Event, t time.Time) { fn(e, t) }
 
-func SetEventCallbacks(w *glfw.Window, handler EventHandler, eventTypes ...Event) {
-	s := &eventProvider{handler}
-	for _, e := range eventTypes {
-		switch e.(type) {
-		case RefreshEvent:
-			w.SetRefreshCallback(s.postRefreshEvent)
-		case CharModsEvent:
-			w.SetCharModsCallback(s.postCharModsEvent)
-		case CursorEnterEvent:
-			w.SetCursorEnterCallback(s.postCursorEnterEvent)
-		case CursorPosEvent:
-			w.SetCursorPosCallback(s.postCursorPosEvent)
-		case DropEvent:
-			w.SetDropCallback(s.postDropEvent)
-		case FramebufferSizeEvent:
-			w.SetFramebufferSizeCallback(s.postFramebufferSizeEvent)
-		case IconifyEvent:
-			w.SetIconifyCallback(s.postIconifyEvent)
-		case KeyEvent:
-			w.SetKeyCallback(s.postKeyEvent)
-		case MouseEvent:
-			w.SetMouseButtonCallback(s.postMouseEvent)
-		case CharEvent:
-			w.SetCharCallback(s.postCharEvent)
-		case CloseEvent:
-			w.SetCloseCallback(s.postCloseEvent)
-		case FocusEvent:
-			w.SetFocusCallback(s.postFocusEvent)
-		case PositionEvent:
-			w.SetPosCallback(s.postPositionEvent)
-		case ResizeEvent:
-			w.SetSizeCallback(s.postResizeEvent)
-		case ScrollEvent:
-			w.SetScrollCallback(s.postScrollEvent)
-		}
-	}
-}
-
-func ClearEventCallbacks(w *glfw.Window) {
-	w.SetRefreshCallback(nil)
-	w.SetCharModsCallback(nil)
-	w.SetCursorEnterCallback(nil)
-	w.SetCursorPosCallback(nil)
-	w.SetDropCallback(nil)
-	w.SetFramebufferSizeCallback(nil)
-	w.SetIconifyCallback(nil)
-	w.SetKeyCallback(nil)
-	w.SetMouseButtonCallback(nil)
-	w.SetCharCallback(nil)
-	w.SetCloseCallback(nil)
-	w.SetFocusCallback(nil)
-	w.SetPosCallback(nil)
-	w.SetSizeCallback(nil)
-	w.SetScrollCallback(nil)
-}
-
 // Event types
 type (
 	Event interface {
@@ -79,90 +21,139 @@ type (
 	}
 
 	RefreshEvent struct {
-		Window *glfw.Window
+		Window *Window
 	}
 
 	CharModsEvent struct {
-		Window *glfw.Window
+		Window *Window
 		Char   rune
-		Mods   glfw.ModifierKey
+		Mods   ModifierKey
 	}
 
 	CursorEnterEvent struct {
-		Window  *glfw.Window
+		Window  *Window
 		Entered bool
 	}
 
 	CursorPosEvent struct {
-		Window *glfw.Window
+		Window *Window
 		X      float64
 		Y      float64
 	}
 
 	DropEvent struct {
-		Window *glfw.Window
+		Window *Window
 		Names  []string
 	}
 
 	FramebufferSizeEvent struct {
-		Window *glfw.Window
+		Window *Window
 		Width  int
 		Height int
 	}
 
 	IconifyEvent struct {
-		Window    *glfw.Window
+		Window    *Window
 		Iconified bool
 	}
 
 	KeyEvent struct {
-		Window *glfw.Window
-		Key    glfw.Key
+		Window *Window
+		Key    Key
 		Code   int
-		Action glfw.Action
-		Mods   glfw.ModifierKey
+		Action Action
+		Mods   ModifierKey
 	}
 
 	MouseEvent struct {
-		Window *glfw.Window
-		Button glfw.MouseButton
-		Action glfw.Action
-		Mods   glfw.ModifierKey
+		Window *Window
+		Button MouseButton
+		Action Action
+		Mods   ModifierKey
 	}
 
 	CharEvent struct {
-		Window *glfw.Window
+		Window *Window
 		Char   rune
 	}
 
 	CloseEvent struct {
-		Window *glfw.Window
+		Window *Window
 	}
 
 	FocusEvent struct {
-		Window  *glfw.Window
+		Window  *Window
 		Focused bool
 	}
 
 	PositionEvent struct {
-		Window *glfw.Window
+		Window *Window
 		X      int
 		Y      int
 	}
 
 	ResizeEvent struct {
-		Window *glfw.Window
+		Window *Window
 		Width  int
 		Height int
 	}
 
 	ScrollEvent struct {
-		Window *glfw.Window
+		Window *Window
 		XOff   float64
 		YOff   float64
 	}
+
+	MaximizeEvent struct {
+		Window    *Window
+		Maximized bool
+	}
+
+	// ContentScaleEvent is posted when a window's content scale changes, such as when it moves to a monitor with a
+	// different DPI.
+	ContentScaleEvent struct {
+		Window *Window
+		X      float64
+		Y      float64
+	}
+
+	// PasteEvent carries clipboard text pasted into a window. It's posted by Paste and PasteShortcuts.
+	PasteEvent struct {
+		Window *Window
+		Text   string
+	}
+
+	// TouchEvent is posted by touch screen backends, such as go.spiff.io/gt3/mobile, as a touch begins, moves, and
+	// ends. ID identifies a touch from its TouchBegin to its TouchEnd. Positions are in framebuffer pixels.
+	TouchEvent struct {
+		Window *Window
+		ID     int64
+		Phase  TouchPhase
+		X      float64
+		Y      float64
+	}
+)
+
+// TouchPhase is the stage of a touch reported by a TouchEvent.
+type TouchPhase int
+
+// Touch phases.
+const (
+	TouchBegin TouchPhase = iota
+	TouchMove
+	TouchEnd
 )
 
+// CustomEvent may be embedded in a struct to make it an Event, allowing packages other than gt3 to define event types:
+//
+//	type ShortcutEvent struct {
+//		gt3.CustomEvent
+//		Name string
+//	}
+type CustomEvent struct{}
+
+func (CustomEvent) isEvent() {}
+
 func (RefreshEvent) isEvent()         {}
 func (CharModsEvent) isEvent()        {}
 func (CursorEnterEvent) isEvent()     {}
@@ -178,75 +169,7 @@ func (FocusEvent) isEvent()           {}
 func (PositionEvent) isEvent()        {}
 func (ResizeEvent) isEvent()          {}
 func (ScrollEvent) isEvent()          {}
-
-// Event provider (hook)
-
-type eventProvider struct {
-	events EventHandler
-}
-
-func (p *eventProvider) event(e Event) {
-	if e != nil {
-		p.events.Event(e, time.Now())
-	}
-}
-
-func (p *eventProvider) postRefreshEvent(Window *glfw.Window) {
-	p.event(RefreshEvent{Window})
-}
-
-func (p *eventProvider) postCharModsEvent(Window *glfw.Window, Char rune, Mods glfw.ModifierKey) {
-	p.event(CharModsEvent{Window, Char, Mods})
-}
-
-func (p *eventProvider) postCursorEnterEvent(Window *glfw.Window, Entered bool) {
-	p.event(CursorEnterEvent{Window, Entered})
-}
-
-func (p *eventProvider) postCursorPosEvent(Window *glfw.Window, X float64, Y float64) {
-	p.event(CursorPosEvent{Window, X, Y})
-}
-
-func (p *eventProvider) postDropEvent(Window *glfw.Window, Names []string) {
-	p.event(DropEvent{Window, Names})
-}
-
-func (p *eventProvider) postFramebufferSizeEvent(Window *glfw.Window, Width int, Height int) {
-	p.event(FramebufferSizeEvent{Window, Width, Height})
-}
-
-func (p *eventProvider) postIconifyEvent(Window *glfw.Window, Iconified bool) {
-	p.event(IconifyEvent{Window, Iconified})
-}
-
-func (p *eventProvider) postKeyEvent(Window *glfw.Window, Key glfw.Key, Code int, Action glfw.Action, Mods glfw.ModifierKey) {
-	p.event(KeyEvent{Window, Key, Code, Action, Mods})
-}
-
-func (p *eventProvider) postMouseEvent(Window *glfw.Window, Button glfw.MouseButton, Action glfw.Action, Mods glfw.ModifierKey) {
-	p.event(MouseEvent{Window, Button, Action, Mods})
-}
-
-func (p *eventProvider) postCharEvent(Window *glfw.Window, Char rune) {
-	p.event(CharEvent{Window, Char})
-}
-
-func (p *eventProvider) postCloseEvent(Window *glfw.Window) {
-	p.event(CloseEvent{Window})
-}
-
-func (p *eventProvider) postFocusEvent(Window *glfw.Window, Focused bool) {
-	p.event(FocusEvent{Window, Focused})
-}
-
-func (p *eventProvider) postPositionEvent(Window *glfw.Window, X int, Y int) {
-	p.event(PositionEvent{Window, X, Y})
-}
-
-func (p *eventProvider) postResizeEvent(Window *glfw.Window, Width int, Height int) {
-	p.event(ResizeEvent{Window, Width, Height})
-}
-
-func (p *eventProvider) postScrollEvent(Window *glfw.Window, XOff float64, YOff float64) {
-	p.event(ScrollEvent{Window, XOff, YOff})
-}
+func (MaximizeEvent) isEvent()        {}
+func (ContentScaleEvent) isEvent()    {}
+func (PasteEvent) isEvent()           {}
+func (TouchEvent) isEvent()           {}
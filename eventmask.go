@@ -0,0 +1,78 @@
+package gt3
+
+// EventMask is a set of window event types, for selecting the events SetEventMask posts.
+type EventMask uint32
+
+// Event masks. Each selects the event type of the same name.
+const (
+	EventRefresh EventMask = 1 << iota
+	EventCharMods
+	EventCursorEnter
+	EventCursorPos
+	EventRawMotion
+	EventDrop
+	EventFramebufferSize
+	EventIconify
+	EventKey
+	EventMouse
+	EventChar
+	EventClose
+	EventFocus
+	EventPosition
+	EventResize
+	EventScroll
+	EventMaximize
+	EventContentScale
+
+	// AllEvents selects every window event type except RawMotionEvent, which also changes the cursor's input mode.
+	AllEvents = EventRefresh | EventCharMods | EventCursorEnter | EventCursorPos | EventDrop | EventFramebufferSize |
+		EventIconify | EventKey | EventMouse | EventChar | EventClose | EventFocus | EventPosition | EventResize |
+		EventScroll | EventMaximize | EventContentScale
+)
+
+// EventMaskOf returns the mask selecting the types of the given events. Events that aren't window events, such as
+// PasteEvent, are ignored.
+func EventMaskOf(eventTypes ...Event) EventMask {
+	var m EventMask
+	for _, e := range eventTypes {
+		switch e.(type) {
+		case RefreshEvent:
+			m |= EventRefresh
+		case CharModsEvent:
+			m |= EventCharMods
+		case CursorEnterEvent:
+			m |= EventCursorEnter
+		case CursorPosEvent:
+			m |= EventCursorPos
+		case RawMotionEvent:
+			m |= EventRawMotion
+		case DropEvent:
+			m |= EventDrop
+		case FramebufferSizeEvent:
+			m |= EventFramebufferSize
+		case IconifyEvent:
+			m |= EventIconify
+		case KeyEvent:
+			m |= EventKey
+		case MouseEvent:
+			m |= EventMouse
+		case CharEvent:
+			m |= EventChar
+		case CloseEvent:
+			m |= EventClose
+		case FocusEvent:
+			m |= EventFocus
+		case PositionEvent:
+			m |= EventPosition
+		case ResizeEvent:
+			m |= EventResize
+		case ScrollEvent:
+			m |= EventScroll
+		case MaximizeEvent:
+			m |= EventMaximize
+		case ContentScaleEvent:
+			m |= EventContentScale
+		}
+	}
+	return m
+}
@@ -10,13 +10,9 @@ import (
 	"go.spiff.io/gt3"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
-func init() {
-	runtime.LockOSThread()
-}
-
 func logstack(msg ...interface{}) {
 	var buf [8192]byte
 	n := runtime.Stack(buf[:], false)
@@ -45,16 +41,18 @@ func (q *eventQueue) play(h gt3.EventHandler) {
 }
 
 func main() {
-	if err := glfw.Init(); err != nil {
-		panic(err)
+	if err := gt3.Main(setup); err != nil {
+		log.Fatal(err)
 	}
+}
 
+func setup(sim *gt3.Sim) error {
 	if err := gl.Init(); err != nil {
-		panic(err)
+		return err
 	}
 
-	down := make(chan struct{})
-	sim := gt3.NewSim(2, 30, down)
+	sim.SetFPS(2)
+	sim.SetRenderFPS(30)
 
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
@@ -62,7 +60,7 @@ func main() {
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, 1)
 	wnd, err := glfw.CreateWindow(800, 600, "Test", nil, nil)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	focused := true
@@ -77,15 +75,11 @@ func main() {
 			}
 			sim.SetRenderFPS(fps)
 		case gt3.CloseEvent:
-			if down != nil {
-				close(down)
-				down = nil
-				log.Println("Window closed")
-			}
+			sim.Stop()
+			log.Println("Window closed")
 		case gt3.KeyEvent:
-			if ev.Key == glfw.KeyEscape && ev.Action == glfw.Release && down != nil {
-				close(down)
-				down = nil
+			if ev.Key == gt3.KeyEscape && ev.Action == gt3.Release {
+				sim.Stop()
 				log.Println("Escape pressed")
 			}
 		default:
@@ -112,16 +106,13 @@ func main() {
 		cur := sim.Seconds() - step
 		log.Print("Render | ft=", ft, " st=", cur, "->", cur+step, " rt=", rt)
 
-		wnd.MakeContextCurrent()
-
 		// Just make it clear the context is working.
 		r := float32(math.Abs(math.Sin(sim.Now() * 0.2)))
 		gl.ClearColor(r, 0.3, 0.3, 1.0)
 		gl.Clear(gl.COLOR_BUFFER_BIT)
 		// No pun intended.
-
-		wnd.SwapBuffers()
 	})
+	sim.SetRenderWindows(gt3.GLFWWindow(wnd))
 
-	sim.Run()
+	return nil
 }
@@ -0,0 +1,73 @@
+package gt3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FramebufferConfig describes a window's default framebuffer.
+type FramebufferConfig struct {
+	Samples      int // MSAA samples per pixel, or 0 to disable multisampling
+	DepthBits    int
+	StencilBits  int
+	DoubleBuffer bool
+	SRGB         bool
+}
+
+// Framebuffer presets.
+var (
+	// FramebufferDefault is GLFW's default framebuffer configuration.
+	FramebufferDefault = FramebufferConfig{DepthBits: 24, StencilBits: 8, DoubleBuffer: true}
+	// Framebuffer2D has no depth or stencil buffer.
+	Framebuffer2D = FramebufferConfig{DoubleBuffer: true}
+	// Framebuffer3D has a 24-bit depth buffer and 8-bit stencil buffer.
+	Framebuffer3D = FramebufferConfig{DepthBits: 24, StencilBits: 8, DoubleBuffer: true}
+	// Framebuffer3DMSAA is Framebuffer3D with 4x multisampling.
+	Framebuffer3DMSAA = FramebufferConfig{Samples: 4, DepthBits: 24, StencilBits: 8, DoubleBuffer: true}
+)
+
+func (c FramebufferConfig) String() string {
+	return fmt.Sprintf("samples=%d depth=%d stencil=%d doublebuffer=%t srgb=%t",
+		c.Samples, c.DepthBits, c.StencilBits, c.DoubleBuffer, c.SRGB)
+}
+
+// FramebufferMismatchError is returned when the framebuffer granted by the driver doesn't satisfy the requested
+// configuration.
+type FramebufferMismatchError struct {
+	Requested FramebufferConfig
+	Effective FramebufferConfig
+}
+
+func (e *FramebufferMismatchError) Error() string {
+	var diffs []string
+	r, g := e.Requested, e.Effective
+	if g.Samples < r.Samples {
+		diffs = append(diffs, fmt.Sprintf("samples %d < %d", g.Samples, r.Samples))
+	}
+	if g.DepthBits < r.DepthBits {
+		diffs = append(diffs, fmt.Sprintf("depth bits %d < %d", g.DepthBits, r.DepthBits))
+	}
+	if g.StencilBits < r.StencilBits {
+		diffs = append(diffs, fmt.Sprintf("stencil bits %d < %d", g.StencilBits, r.StencilBits))
+	}
+	if g.DoubleBuffer != r.DoubleBuffer {
+		diffs = append(diffs, fmt.Sprintf("doublebuffer %t != %t", g.DoubleBuffer, r.DoubleBuffer))
+	}
+	if r.SRGB && !g.SRGB {
+		diffs = append(diffs, "srgb not granted")
+	}
+	return "gt3: framebuffer mismatch: " + strings.Join(diffs, ", ")
+}
+
+// Satisfies returns nil if c, the effective configuration, provides at least what was requested, or a
+// *FramebufferMismatchError otherwise.
+func (c FramebufferConfig) Satisfies(requested FramebufferConfig) error {
+	if c.Samples >= requested.Samples &&
+		c.DepthBits >= requested.DepthBits &&
+		c.StencilBits >= requested.StencilBits &&
+		c.DoubleBuffer == requested.DoubleBuffer &&
+		(c.SRGB || !requested.SRGB) {
+		return nil
+	}
+	return &FramebufferMismatchError{Requested: requested, Effective: c}
+}
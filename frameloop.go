@@ -1,12 +1,12 @@
 package gt3
 
 import (
+	"context"
 	"errors"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
 type Op interface {
@@ -18,9 +18,24 @@ type OpFn func(step, frameTime float64, when time.Time)
 func (fn OpFn) Do(step, frameTime float64, when time.Time) { fn(step, frameTime, when) }
 
 type Sim struct {
-	PreFrame Op
-	Frame    Op
-	Render   Op
+	// Counters, accessed atomically. Kept first for alignment.
+	ticks   uint64
+	renders uint64
+
+	// Main goroutine, accessed atomically. Set by Start, and zero until then.
+	goid     uint64
+	osThread bool // Whether the main goroutine started on the main OS thread
+
+	// Sched budget and fallback backlog, accessed atomically.
+	schedMaxOps  int64
+	schedMaxTime int64 // Nanoseconds
+	schedWaiting int64 // Ops waiting on the fallback channel
+
+	PreFrame   Op
+	Frame      Op
+	PreRender  Op // Runs before Render, e.g. to make a context current or upload interpolated state
+	Render     Op
+	PostRender Op // Runs after Render, e.g. to swap buffers or capture the frame
 
 	fps  int // Simulation limitation
 	hz   float64
@@ -31,21 +46,68 @@ type Sim struct {
 	fpsrw sync.RWMutex
 
 	// Timing
+	clock      Clock
 	runTime    int64
+	varStep    float64 // Step of the last frame, with a variable timestep
 	baseTime   float64
 	simTime    float64
 	renderTime float64
 
-	sched   chan Op
-	stopped <-chan struct{}
+	// Wall clock correction
+	wallOffset float64 // Seconds added to the timer when mapping it to wall time
+	drift      float64 // Last measured drift, in seconds
+	nextDrift  float64 // Timer value at which to next measure drift
+
+	pause    int     // Bitset of pause reasons
+	pausedAt float64 // Timer value at which the Sim was paused
+	inFrame  bool    // Set while a sim frame runs
+
+	maxCatchUp int // Maximum ticks per iteration, if > 0
+	lagHandler EventHandler
+
+	schedq    *opQueue
+	sched     chan Op // Fallback for ops scheduled while schedq is full
+	thisFrame []Op    // Ops scheduled by SchedThisFrame
+	stopped   <-chan struct{}
+	ctx       context.Context // Set by NewSimContext
+	quit      chan struct{}
+	quitter   sync.Once
+	onStop    []Op
+	runDone   chan struct{} // Closed when the loop exits, stopping the watchdog and spike logger
+
+	wd       *watchdog
+	spike    *spikeLogger
+	idleGC   *idleGC
+	allocs   *allocTracker
+	adaptive *adaptiveRender
+	tracer   Tracer
+
+	windows []*Window // Managed render windows
+	arena   Arena     // Reset at the start of every loop iteration and between sim frames
+	tickBus TickBus
+	pool    workerPool
+
+	phaseOps [PhaseStop + 1][]phaseOp // Copy-on-write
+	lastOpID OpID
+
+	stats frameStats
 }
 
+// DefaultFPS is the simulation rate of Sims created by Main.
+const DefaultFPS = 60
+
+// NewSim returns a Sim that simulates fps ticks per second and renders at most renderfps times per second, or as often
+// as it can if renderfps is 0. If fps is 0, the Sim uses a variable timestep: each loop iteration runs one sim frame
+// whose step is the time elapsed since the previous one, and then renders. The Sim stops when stop is closed.
 func NewSim(fps, renderfps int, stop <-chan struct{}) *Sim {
-	if fps <= 0 {
-		panic("gt3: simloop FPS must be > 0")
+	if fps < 0 {
+		panic("gt3: simloop FPS must be >= 0")
 	}
 
-	var rhz float64
+	var hz, rhz float64
+	if fps > 0 {
+		hz = 1.0 / float64(fps)
+	}
 	if renderfps > 0 {
 		rhz = 1.0 / float64(renderfps)
 	}
@@ -53,14 +115,18 @@ func NewSim(fps, renderfps int, stop <-chan struct{}) *Sim {
 	return &Sim{
 		fps:  fps,
 		rfps: renderfps,
-		hz:   1.0 / float64(fps),
+		hz:   hz,
 		rhz:  rhz,
 
+		schedq:  newOpQueue(),
+		sched:   make(chan Op),
 		stopped: stop,
+		quit:    make(chan struct{}),
+		clock:   defaultClock(),
 	}
 }
 
-var ErrBadFPS = errors.New("gt3: FPS must be > 0")
+var ErrBadFPS = errors.New("gt3: FPS must be >= 0")
 
 func (s *Sim) SetRenderFPS(fps int) (previous int) {
 	s.fpsrw.Lock()
@@ -74,6 +140,8 @@ func (s *Sim) SetRenderFPS(fps int) (previous int) {
 	return previous
 }
 
+// SetFPS sets the number of sim ticks per second, returning the previous rate. An fps of 0 selects a variable timestep,
+// as described by NewSim.
 func (s *Sim) SetFPS(fps int) (previous int, err error) {
 	if fps < 0 {
 		return 0, ErrBadFPS
@@ -85,13 +153,18 @@ func (s *Sim) SetFPS(fps int) (previous int, err error) {
 	previous = s.fps
 
 	s.fps = fps
-	s.hz = 1.0 / float64(fps)
+	s.hz = 0
+	if fps > 0 {
+		s.hz = 1.0 / float64(fps)
+	}
 
 	return previous, nil
 }
 
+// Now returns the Sim's timer in seconds. The timer keeps running while the Sim is paused, but skips the time spent
+// paused on Resume, so that it stays in step with Seconds.
 func (s *Sim) Now() float64 {
-	return glfw.GetTime() - s.baseTime
+	return s.clock.Now() - s.baseTime
 }
 
 func realtime(unixBase int64, base, after float64) time.Time {
@@ -105,55 +178,133 @@ func realtime(unixBase int64, base, after float64) time.Time {
 	return time.Unix(secs, nanos)
 }
 
+// Seconds returns the simulated time in seconds as of the end of the last sim tick. It doesn't advance while the Sim is
+// paused.
 func (s *Sim) Seconds() float64 {
 	return s.simTime
 }
 
+// Tick returns the number of simulation frames run so far. While a Frame op is running, Tick is the zero-based index of
+// the frame being simulated.
+func (s *Sim) Tick() uint64 {
+	return atomic.LoadUint64(&s.ticks)
+}
+
+// RenderCount returns the number of times the Render op has been run so far. While the Render op is running,
+// RenderCount is the zero-based index of the render.
+func (s *Sim) RenderCount() uint64 {
+	return atomic.LoadUint64(&s.renders)
+}
+
+// realtime maps a time in seconds since the Sim started to wall time.
+func (s *Sim) realtime(after float64) time.Time {
+	return realtime(s.runTime, s.baseTime+s.wallOffset, after)
+}
+
+// Time returns the wall clock time corresponding to Seconds. It doesn't advance while the Sim is paused, and jumps
+// forward by the length of the pause on Resume, since time spent paused isn't simulated.
 func (s *Sim) Time() time.Time {
-	return realtime(s.runTime, s.baseTime, s.simTime)
+	return s.realtime(s.simTime)
 }
 
+// RealTime returns the wall clock time corresponding to Now.
 func (s *Sim) RealTime() time.Time {
-	return realtime(s.runTime, s.baseTime, s.Now())
+	return s.realtime(s.Now())
 }
 
 func (s *Sim) pollSched(hz, ft float64, rt time.Time) {
-	for sched := s.sched; ; {
-		select {
-		case op := <-sched:
-			op.Do(hz, ft, rt)
-		default:
-			return
+	// Ops scheduled from the main goroutine run first, in order, including any they schedule in turn.
+	for len(s.thisFrame) > 0 {
+		ops := s.thisFrame
+		s.thisFrame = nil
+		for _, op := range ops {
+			s.runOp(op, s.opContext(PhaseSched, hz, ft, rt))
 		}
 	}
-}
 
-func runOp(op Op, hz, ft float64, rt time.Time) {
-	if op != nil {
-		op.Do(hz, ft, rt)
+	var (
+		maxOps  = atomic.LoadInt64(&s.schedMaxOps)
+		maxTime = time.Duration(atomic.LoadInt64(&s.schedMaxTime))
+		start   time.Time
+	)
+	if maxTime > 0 {
+		start = time.Now()
+	}
+
+	for n := int64(0); maxOps <= 0 || n < maxOps; n++ {
+		if maxTime > 0 && n > 0 && time.Since(start) >= maxTime {
+			return
+		}
+		op, ok := s.schedq.pop()
+		if !ok {
+			select {
+			case op = <-s.sched:
+			default:
+				return
+			}
+		}
+		s.runOp(op, s.opContext(PhaseSched, hz, ft, rt))
 	}
 }
 
 func (s *Sim) frame(hz, ft float64, rt time.Time) {
+	s.inFrame = true
+	defer func() { s.inFrame = false }()
+	if s.tracer != nil {
+		defer s.span(SpanFrame, hz, ft, rt)()
+	}
 	s.pollSched(hz, ft, rt)
-	runOp(s.Frame, hz, ft, rt)
+	s.tickBus.run(s, s.opContext(PhaseTick, hz, ft, rt))
+	s.runPhase(s.Frame, s.opContext(PhaseFrame, hz, ft, rt))
+}
+
+func (s *Sim) render(hz, now float64) {
+	rt := s.realtime(now)
+	if len(s.windows) == 0 {
+		s.renderWindow(nil, hz, now, rt)
+	}
+	for _, w := range s.windows {
+		w.MakeContextCurrent()
+		s.renderWindow(w, hz, now, rt)
+		w.SwapBuffers()
+	}
+	atomic.AddUint64(&s.renders, 1)
+	s.stats.render(time.Now(), s.Tick())
+}
+
+func (s *Sim) renderWindow(w *Window, hz, now float64, rt time.Time) {
+	s.debugAssertMainThread()
+	for _, p := range [...]struct {
+		phase Phase
+		op    Op
+	}{
+		{PhasePreRender, s.PreRender},
+		{PhaseRender, s.Render},
+		{PhasePostRender, s.PostRender},
+	} {
+		ctx := s.opContext(p.phase, hz, now, rt)
+		ctx.Window = w
+		ctx.Alpha = s.alpha(hz, now)
+		s.runPhase(p.op, ctx)
+	}
 }
 
 var ErrStopped = errors.New("gt3: stopped")
 
-func (s *Sim) runSim(ubase int64, stopped <-chan struct{}) error {
+func (s *Sim) runSim(stopped <-chan struct{}) error {
 	select {
 	case <-stopped:
+		return s.stopErr()
+	case <-s.quit:
 		return ErrStopped
 	default:
 	}
 
 	s.fpsrw.RLock()
 	var (
-		now  float64
-		hz   float64
-		sim  = s.simTime
-		base = s.baseTime
+		now float64
+		hz  float64
+		sim = s.simTime
 	)
 	s.fpsrw.RUnlock()
 
@@ -162,17 +313,56 @@ func (s *Sim) runSim(ubase int64, stopped <-chan struct{}) error {
 	hz = s.hz
 	s.fpsrw.RUnlock()
 
-	runOp(s.PreFrame, hz, sim, realtime(ubase, base, sim))
+	if s.adaptive != nil {
+		s.adaptive.start = time.Now()
+	}
 
-	for now = s.Now(); sim < now; now = s.Now() {
-		s.frame(hz, sim, realtime(ubase, base, sim))
+	if s.tracer != nil {
+		defer s.span(SpanIteration, hz, sim, s.realtime(sim))()
+	}
+
+	s.arena.Reset()
+	s.runPhase(s.PreFrame, s.opContext(PhasePreFrame, hz, sim, s.realtime(sim)))
+	sim = s.simTime // PreFrame may have prerolled
+
+	s.checkDrift()
+
+	variable := hz == 0
+	if variable {
+		// Variable timestep: one frame covering the time since the last, if any has passed.
+		if now = s.Now(); now > sim && s.pause == 0 {
+			s.varStep = now - sim
+			s.frame(s.varStep, sim, s.realtime(sim))
+			s.simTime = now
+			atomic.AddUint64(&s.ticks, 1)
+		}
+		hz = s.varStep
+	}
+
+	for frames := 0; !variable; frames++ {
+		if now = s.Now(); sim >= now || s.pause != 0 {
+			break
+		}
+		if s.maxCatchUp > 0 && frames >= s.maxCatchUp {
+			s.dropLag(hz, sim, now)
+			now = sim
+			break
+		}
+		if frames > 0 {
+			// PreFrame allocations are only kept for the iteration's first frame
+			s.arena.Reset()
+		}
+		s.frame(hz, sim, s.realtime(sim))
 		sim += hz
 		s.simTime = sim
+		atomic.AddUint64(&s.ticks, 1)
 
 		if sim < now {
-			// Refresh hz per-frame
+			// Refresh hz per-frame. A switch to a variable timestep takes effect next iteration.
 			s.fpsrw.RLock()
-			hz = s.hz
+			if s.hz > 0 {
+				hz = s.hz
+			}
 			s.fpsrw.RUnlock()
 		}
 	}
@@ -188,55 +378,255 @@ func (s *Sim) runSim(ubase int64, stopped <-chan struct{}) error {
 	if rlimit {
 		// Reacquire current time and see if we're OK to render since the last render time
 		if rt := s.renderTime; now >= rt {
-			runOp(s.Render, hz, now, realtime(ubase, base, now))
+			s.render(hz, now)
 			s.renderTime = now + rhz
+			if s.adaptive != nil {
+				s.adaptive.rendered(s)
+			}
 		}
 	} else {
-		runOp(s.Render, hz, now, realtime(ubase, base, now))
+		s.render(hz, now)
 		s.renderTime = now
 	}
 
 	return nil
 }
 
-func (s *Sim) Run() error {
-	stopped := s.stopped
+// SetRenderWindows enables managed rendering for the given windows. For each window, in order, the Sim makes the
+// window's context current, runs the PreRender, Render, and PostRender ops with OpContext.Window set to the window, and
+// then swaps the window's buffers. Calling SetRenderWindows with no windows disables managed rendering, in which case
+// render ops run once per render with no window and context handling is left to them. SetRenderWindows must be called
+// from the main goroutine.
+func (s *Sim) SetRenderWindows(windows ...*Window) {
+	s.windows = append(s.windows[:0:0], windows...)
+}
 
-	ubase := time.Now().Unix()
-	glfw.SetTime(0)
+// Stop stops the Sim. Run returns ErrStopped after the current loop iteration completes and any OnStop ops have run.
+// Stop may be called from any goroutine and more than once.
+func (s *Sim) Stop() {
+	s.quitter.Do(func() { close(s.quit) })
+}
 
-	s.sched = make(chan Op)
-	s.runTime = ubase
-	s.simTime, s.baseTime = 0, glfw.GetTime()
+// stopping reports whether the Sim has been stopped, after which its loop runs no more scheduled ops.
+func (s *Sim) stopping() bool {
+	select {
+	case <-s.stopped:
+		return true
+	case <-s.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// OnStop adds an op to run on the main goroutine after the Sim's loop exits but before Run returns, such as to save
+// state or free GL resources. OnStop ops run in the order they were added, in PhaseStop, with the final sim time as
+// their frame time. They run however the Sim stops, including when its stop channel or context is done or an op
+// panics. OnStop may be called from ops but must not otherwise be called concurrently with Run.
+func (s *Sim) OnStop(op Op) {
+	s.onStop = append(s.onStop, op)
+}
+
+func (s *Sim) runOnStop() {
+	s.fpsrw.RLock()
+	hz := s.hz
+	s.fpsrw.RUnlock()
+
+	ctx := s.opContext(PhaseStop, hz, s.simTime, s.realtime(s.simTime))
+	for _, op := range s.onStop {
+		s.runOp(op, ctx)
+	}
+}
+
+func (s *Sim) Run() error {
+	s.Start()
+	defer s.finish()
 	for {
-		if err := s.runSim(ubase, stopped); err != nil {
+		if err := s.step(); err != nil {
 			return err
 		}
 	}
 }
 
+// Start prepares the Sim to be driven one loop iteration at a time with Step instead of Run, for platforms whose draw
+// callbacks own the main loop, such as go.spiff.io/gt3/mobile. The goroutine calling Start becomes the Sim's main
+// goroutine, and Step must be called from it. A Sim driven with Step must not also be run with Run.
+func (s *Sim) Start() {
+	s.osThread = isMainOSThread()
+	atomic.StoreUint64(&s.goid, goroutineID())
+	s.debugAssertMainThread()
+
+	start := s.clock.Wall()
+	resetClock(s.clock)
+	// The clock may not start at zero, such as a ManualClock advanced before Run, so map wall time from the clock's
+	// origin rather than from now.
+	now := s.clock.Now()
+	start = start.Add(-time.Duration(now * float64(time.Second)))
+
+	// Sim time carries over from Preroll, so start the timer at the current sim time.
+	s.runTime = start.Unix()
+	s.baseTime = now - s.simTime
+	s.renderTime = s.simTime
+	s.wallOffset = float64(start.Nanosecond()) / float64(time.Second)
+	s.drift, s.nextDrift = 0, s.simTime+driftInterval
+
+	if s.wd == nil && s.spike == nil {
+		return
+	}
+	s.runDone = make(chan struct{})
+	if wd := s.wd; wd != nil {
+		wd.goid = goroutineID()
+		go wd.run(s.runDone)
+	}
+	if spike := s.spike; spike != nil {
+		spike.goid = goroutineID()
+		go spike.run(s.runDone)
+	}
+}
+
+// Step runs one iteration of the Sim's loop: the PreFrame op, any sim frames that are due, and a render. Once the Sim
+// is stopped, Step runs its OnStop ops and returns the error Run would, after which it must not be called again. If an
+// op panics, the OnStop ops run before the panic continues. Step must be called from the goroutine that called Start.
+func (s *Sim) Step() (err error) {
+	ok := false
+	defer func() {
+		if !ok {
+			s.finish()
+		}
+	}()
+	err = s.step()
+	ok = err == nil
+	return err
+}
+
+func (s *Sim) step() error {
+	wd, spike, allocs := s.wd, s.spike, s.allocs
+	if wd != nil {
+		wd.begin()
+	}
+	if spike != nil {
+		spike.begin(s.Tick())
+	}
+	if allocs != nil {
+		allocs.begin(s.Tick())
+	}
+	err := s.runSim(s.stopped)
+	if allocs != nil {
+		allocs.end(s.Tick())
+	}
+	if spike != nil {
+		spike.end()
+	}
+	if wd != nil {
+		wd.end()
+	}
+	if err != nil {
+		return err
+	}
+	if s.idleGC != nil {
+		s.collectIdle()
+	}
+	return nil
+}
+
+// finish runs the OnStop ops and stops the watchdog and spike logger once the loop exits.
+func (s *Sim) finish() {
+	defer func() {
+		if s.runDone != nil {
+			close(s.runDone)
+			s.runDone = nil
+		}
+	}()
+	s.runOnStop()
+}
+
 // Sched schedules an op to run on the main goroutine. Sched does not wait for the op to run.
+//
+// Ops scheduled with Sched run before the Frame op of some later sim frame, but since they're delivered
+// asynchronously, there is no guarantee of which one. To schedule an op from the main goroutine for the current
+// tick, use SchedThisFrame.
+//
+// Sched is lock-free and doesn't allocate unless a large number of ops are waiting to run, in which case it falls back
+// to handing the op to the main goroutine from a new goroutine.
 func (s *Sim) Sched(op Op) {
+	if s.schedq.push(op) {
+		return
+	}
+	atomic.AddInt64(&s.schedWaiting, 1)
 	go func() {
+		defer atomic.AddInt64(&s.schedWaiting, -1)
 		select {
 		case s.sched <- op:
 		case <-s.stopped:
+		case <-s.quit:
 		}
 	}()
 }
 
-// Sync schedules an Op to run on the main goroutine and waits for it to finish running. If scheduled on the main
-// goroutine, Sync will deadlock the process.
-func (s *Sim) Sync(op Op) {
+// SchedThisFrame schedules an op to run before the next Frame op. It must only be called from the main goroutine,
+// typically by the PreFrame op or an event handler it calls. Ops scheduled during PreFrame are guaranteed to run
+// before the Frame op of the first sim frame in the same loop iteration, or before the next sim frame if no frame is
+// due in this iteration. Ops run in the order they were scheduled and before any ops scheduled with Sched.
+func (s *Sim) SchedThisFrame(op Op) {
+	s.thisFrame = append(s.thisFrame, op)
+}
+
+// Errors returned by Sync and SyncTimeout.
+var (
+	ErrSyncOnMain  = errors.New("gt3: Sync called from the main goroutine")
+	ErrSyncTimeout = errors.New("gt3: Sync timed out")
+)
+
+// Sync schedules an Op to run on the main goroutine and waits for it to finish running. If the Sim is stopped first,
+// Sync returns without waiting with the error Run returns. Since waiting on the main goroutine would deadlock, Sync
+// returns ErrSyncOnMain without scheduling op if called from it.
+func (s *Sim) Sync(op Op) error {
+	return s.sync(op, nil)
+}
+
+// SyncTimeout is the same as Sync, except that it gives up waiting after d and returns ErrSyncTimeout. If it times
+// out, op is still scheduled and may run later.
+func (s *Sim) SyncTimeout(op Op, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	return s.sync(op, t.C)
+}
+
+func (s *Sim) sync(op Op, timeout <-chan time.Time) error {
+	if s.onMainGoroutine() {
+		return ErrSyncOnMain
+	}
+
 	done := make(chan struct{})
-	syncOp := OpFn(func(hz, ft float64, w time.Time) {
+	syncOp := ContextOpFn(func(ctx OpContext) {
 		defer close(done)
-		op.Do(hz, ft, w)
+		RunOp(op, ctx)
 	})
 
+	s.Sched(syncOp)
 	select {
-	case s.sched <- syncOp:
-		<-done
+	case <-done:
+		return nil
+	case <-timeout:
+		return ErrSyncTimeout
 	case <-s.stopped:
+		return s.stopErr()
+	case <-s.quit:
+		return ErrStopped
+	}
+}
+
+// alpha returns how far the timer value now is between the start and end of the last sim frame, given its step.
+func (s *Sim) alpha(hz, now float64) float64 {
+	if hz <= 0 {
+		return 0
+	}
+	a := (now - (s.simTime - hz)) / hz
+	switch {
+	case a < 0:
+		return 0
+	case a > 1:
+		return 1
 	}
+	return a
 }
@@ -0,0 +1,22 @@
+package gt3
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestSim returns a Sim driven by a manual clock, and a function advancing the clock by a number of seconds and
+// stepping the Sim once.
+func newTestSim(t *testing.T, fps int) (*Sim, func(seconds float64)) {
+	t.Helper()
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewSim(fps, 0, nil)
+	s.SetClock(clock)
+	return s, func(seconds float64) {
+		t.Helper()
+		clock.AdvanceSeconds(seconds)
+		if err := s.Step(); err != nil {
+			t.Fatalf("Step() = %v", err)
+		}
+	}
+}
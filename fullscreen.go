@@ -0,0 +1,165 @@
+//go:build !android && !ios
+
+package gt3
+
+import (
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// FullscreenMode is how a window covers its monitor.
+type FullscreenMode int
+
+// Fullscreen modes.
+const (
+	Windowed FullscreenMode = iota
+	// ExclusiveFullscreen makes the window the monitor's fullscreen window, using the monitor's current video mode.
+	ExclusiveFullscreen
+	// BorderlessFullscreen makes the window an undecorated window covering the monitor. Switching to and from it
+	// doesn't change video modes, so it's faster and friendlier to switching between applications.
+	BorderlessFullscreen
+)
+
+func (m FullscreenMode) String() string {
+	switch m {
+	case Windowed:
+		return "windowed"
+	case ExclusiveFullscreen:
+		return "exclusive"
+	case BorderlessFullscreen:
+		return "borderless"
+	}
+	return "FullscreenMode(?)"
+}
+
+// FullscreenEvent is posted to a window's Dispatcher, as returned by Window.Events, when SetFullscreen or
+// ToggleFullscreen changes its mode.
+type FullscreenEvent struct {
+	Window  *Window
+	Mode    FullscreenMode
+	Monitor string // Name of the monitor covered, or "" if Windowed
+}
+
+func (FullscreenEvent) isEvent() {}
+
+// windowedGeometry is a window's windowed position and size, saved while it's fullscreen.
+type windowedGeometry struct {
+	mode                FullscreenMode
+	x, y, width, height int
+	decorated           bool
+}
+
+var windowedKey = NewWindowKey[windowedGeometry]("gt3.windowed")
+
+// FullscreenMode returns w's current fullscreen mode. Windows created fullscreen by NewWindow report
+// ExclusiveFullscreen.
+func (w *Window) FullscreenMode() FullscreenMode {
+	if g, ok := WindowData(w, windowedKey); ok {
+		return g.mode
+	}
+	if gw := w.GLFW(); gw != nil && gw.GetMonitor() != nil {
+		return ExclusiveFullscreen
+	}
+	return Windowed
+}
+
+// ToggleFullscreen switches w between Windowed and mode on the monitor it's mostly on, as by SetFullscreen.
+func (w *Window) ToggleFullscreen(mode FullscreenMode) {
+	if w.FullscreenMode() != Windowed {
+		mode = Windowed
+	}
+	w.SetFullscreen(mode, nil)
+}
+
+// SetFullscreen changes w's fullscreen mode. When going fullscreen from Windowed, w's position, size, and decoration
+// are saved and restored when it returns to Windowed. If monitor is nil, the monitor w is fullscreen on or centered on
+// is used, or the primary monitor if there is none. SetFullscreen posts a FullscreenEvent if the mode changes. It does
+// nothing if w isn't a GLFW window, and must be called from the main goroutine.
+func (w *Window) SetFullscreen(mode FullscreenMode, monitor *glfw.Monitor) {
+	debugAssertMainThread()
+	gw := w.GLFW()
+	prev := w.FullscreenMode()
+	if gw == nil || mode == prev && (mode == Windowed || monitor == nil) {
+		return
+	}
+
+	g, saved := WindowData(w, windowedKey)
+	switch {
+	case saved:
+	case prev == Windowed:
+		g.x, g.y = gw.GetPos()
+		g.width, g.height = gw.GetSize()
+		g.decorated = gw.GetAttrib(glfw.Decorated) != 0
+	default:
+		// Created fullscreen, so there's no windowed geometry to restore.
+		g = defaultWindowed(gw)
+	}
+
+	var name string
+	switch mode {
+	case Windowed:
+		gw.SetMonitor(nil, g.x, g.y, g.width, g.height, 0)
+		gw.SetAttrib(glfw.Decorated, glfwBool(g.decorated))
+		DeleteWindowData(w, windowedKey)
+	default:
+		m := monitorFor(gw, monitor)
+		if m.GLFW() == nil {
+			return
+		}
+		name = m.Name
+		if mode == ExclusiveFullscreen {
+			gw.SetAttrib(glfw.Decorated, glfwBool(g.decorated))
+			gw.SetMonitor(m.GLFW(), 0, 0, m.Width, m.Height, m.RefreshRate)
+		} else {
+			if gw.GetMonitor() != nil {
+				gw.SetMonitor(nil, m.X, m.Y, m.Width, m.Height, 0)
+			}
+			gw.SetAttrib(glfw.Decorated, glfw.False)
+			gw.SetPos(m.X, m.Y)
+			gw.SetSize(m.Width, m.Height)
+		}
+		g.mode = mode
+		SetWindowData(w, windowedKey, g)
+	}
+
+	w.events.Event(FullscreenEvent{Window: w, Mode: mode, Monitor: name}, time.Now())
+}
+
+// monitorFor returns monitor, the monitor w is fullscreen on, the monitor containing w's center, or the primary
+// monitor, in that order of preference. The returned Monitor has a nil native monitor if none is connected.
+func monitorFor(w *glfw.Window, monitor *glfw.Monitor) Monitor {
+	if monitor == nil {
+		monitor = w.GetMonitor()
+	}
+	monitors := Monitors()
+	if monitor != nil {
+		for _, m := range monitors {
+			if m.GLFW() == monitor {
+				return m
+			}
+		}
+	}
+	x, y := w.GetPos()
+	width, height := w.GetSize()
+	cx, cy := x+width/2, y+height/2
+	for _, m := range monitors {
+		if cx >= m.X && cx < m.X+m.Width && cy >= m.Y && cy < m.Y+m.Height {
+			return m
+		}
+	}
+	if len(monitors) > 0 {
+		return monitors[0]
+	}
+	return Monitor{}
+}
+
+// defaultWindowed returns windowed geometry for a window created fullscreen: half the size of its monitor, centered.
+func defaultWindowed(w *glfw.Window) windowedGeometry {
+	g := windowedGeometry{width: 640, height: 480, decorated: true}
+	if m := monitorFor(w, nil); m.GLFW() != nil {
+		g.width, g.height = m.Width/2, m.Height/2
+		g.x, g.y = m.X+m.Width/4, m.Y+m.Height/4
+	}
+	return g
+}
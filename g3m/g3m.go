@@ -0,0 +1,64 @@
+// Package g3m is a small vector and matrix math package for graphics. Types are float32-based arrays laid out as GL
+// expects them, so that &m[0] may be passed directly to functions such as gl.UniformMatrix4fv. Matrices are
+// column-major and act on column vectors, and projections follow GL conventions: a right-handed view space looking
+// down -Z, mapped to normalized device coordinates from -1 to 1 on every axis.
+package g3m
+
+import "math"
+
+// Epsilon is the tolerance used by ApproxEqual and to detect degenerate inputs, such as zero-length vectors.
+const Epsilon = 1e-6
+
+// Lerp returns the value t of the way from a to b.
+func Lerp(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
+
+// Clamp returns x clamped to [lo, hi].
+func Clamp(x, lo, hi float32) float32 {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
+
+// Radians converts degrees to radians.
+func Radians(deg float32) float32 {
+	return deg * (math.Pi / 180)
+}
+
+// Degrees converts radians to degrees.
+func Degrees(rad float32) float32 {
+	return rad * (180 / math.Pi)
+}
+
+// ApproxEqual reports whether a and b are within Epsilon of each other, relative to their magnitude if it's above 1.
+func ApproxEqual(a, b float32) bool {
+	d := abs(a - b)
+	if d <= Epsilon {
+		return true
+	}
+	if m := abs(b); m > abs(a) {
+		return d <= Epsilon*m
+	}
+	return d <= Epsilon*abs(a)
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sqrt(x float32) float32 {
+	return float32(math.Sqrt(float64(x)))
+}
+
+func sincos(x float32) (sin, cos float32) {
+	s, c := math.Sincos(float64(x))
+	return float32(s), float32(c)
+}
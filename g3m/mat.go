@@ -0,0 +1,204 @@
+package g3m
+
+// Mat3 is a column-major 3x3 matrix, used for 3D rotations and scales, normal matrices, and 2D affine transforms.
+type Mat3 [9]float32
+
+// Mat4 is a column-major 4x4 matrix, used for 3D affine transforms and projections.
+type Mat4 [16]float32
+
+// Ident3 returns the 3x3 identity matrix.
+func Ident3() Mat3 { return Mat3{0: 1, 4: 1, 8: 1} }
+
+// Ident4 returns the 4x4 identity matrix.
+func Ident4() Mat4 { return Mat4{0: 1, 5: 1, 10: 1, 15: 1} }
+
+// At returns the element at row and col.
+func (m Mat3) At(row, col int) float32 { return m[col*3+row] }
+
+// Col returns column col.
+func (m Mat3) Col(col int) Vec3 { return Vec3{m[col*3], m[col*3+1], m[col*3+2]} }
+
+// Mul returns the product m*n, which applies n and then m.
+func (m Mat3) Mul(n Mat3) (r Mat3) {
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			r[col*3+row] = m[row]*n[col*3] + m[3+row]*n[col*3+1] + m[6+row]*n[col*3+2]
+		}
+	}
+	return r
+}
+
+// MulVec returns the product m*v.
+func (m Mat3) MulVec(v Vec3) Vec3 {
+	return Vec3{
+		m[0]*v[0] + m[3]*v[1] + m[6]*v[2],
+		m[1]*v[0] + m[4]*v[1] + m[7]*v[2],
+		m[2]*v[0] + m[5]*v[1] + m[8]*v[2],
+	}
+}
+
+// Transpose returns m's transpose.
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{m[0], m[3], m[6], m[1], m[4], m[7], m[2], m[5], m[8]}
+}
+
+// Det returns m's determinant.
+func (m Mat3) Det() float32 {
+	return m[0]*(m[4]*m[8]-m[7]*m[5]) - m[3]*(m[1]*m[8]-m[7]*m[2]) + m[6]*(m[1]*m[5]-m[4]*m[2])
+}
+
+// Inverse returns m's inverse and true, or the zero matrix and false if m is singular.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Det()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	inv := Mat3{
+		m[4]*m[8] - m[7]*m[5], m[7]*m[2] - m[1]*m[8], m[1]*m[5] - m[4]*m[2],
+		m[6]*m[5] - m[3]*m[8], m[0]*m[8] - m[6]*m[2], m[3]*m[2] - m[0]*m[5],
+		m[3]*m[7] - m[6]*m[4], m[6]*m[1] - m[0]*m[7], m[0]*m[4] - m[3]*m[1],
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv, true
+}
+
+// Mat4 returns m as the upper-left of a homogeneous 4x4 matrix.
+func (m Mat3) Mat4() Mat4 {
+	return Mat4{
+		m[0], m[1], m[2], 0,
+		m[3], m[4], m[5], 0,
+		m[6], m[7], m[8], 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Translate2D returns the 2D affine transform translating by (x, y).
+func Translate2D(x, y float32) Mat3 { return Mat3{0: 1, 4: 1, 6: x, 7: y, 8: 1} }
+
+// Scale2D returns the 2D affine transform scaling by (x, y).
+func Scale2D(x, y float32) Mat3 { return Mat3{0: x, 4: y, 8: 1} }
+
+// Rotate2D returns the 2D affine transform rotating by angle radians, counterclockwise in a Y-up space.
+func Rotate2D(angle float32) Mat3 {
+	s, c := sincos(angle)
+	return Mat3{c, s, 0, -s, c, 0, 0, 0, 1}
+}
+
+// Transform2D returns the 2D point p transformed by the affine transform m.
+func (m Mat3) Transform2D(p Vec2) Vec2 {
+	return Vec2{m[0]*p[0] + m[3]*p[1] + m[6], m[1]*p[0] + m[4]*p[1] + m[7]}
+}
+
+// At returns the element at row and col.
+func (m Mat4) At(row, col int) float32 { return m[col*4+row] }
+
+// Col returns column col.
+func (m Mat4) Col(col int) Vec4 { return Vec4{m[col*4], m[col*4+1], m[col*4+2], m[col*4+3]} }
+
+// Mul returns the product m*n, which applies n and then m.
+func (m Mat4) Mul(n Mat4) (r Mat4) {
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			r[col*4+row] = m[row]*n[col*4] + m[4+row]*n[col*4+1] + m[8+row]*n[col*4+2] + m[12+row]*n[col*4+3]
+		}
+	}
+	return r
+}
+
+// MulVec returns the product m*v.
+func (m Mat4) MulVec(v Vec4) Vec4 {
+	return Vec4{
+		m[0]*v[0] + m[4]*v[1] + m[8]*v[2] + m[12]*v[3],
+		m[1]*v[0] + m[5]*v[1] + m[9]*v[2] + m[13]*v[3],
+		m[2]*v[0] + m[6]*v[1] + m[10]*v[2] + m[14]*v[3],
+		m[3]*v[0] + m[7]*v[1] + m[11]*v[2] + m[15]*v[3],
+	}
+}
+
+// TransformPoint returns the point p transformed by m, divided by the resulting W for projections.
+func (m Mat4) TransformPoint(p Vec3) Vec3 {
+	return m.MulVec(p.Vec4(1)).Project()
+}
+
+// TransformDir returns the direction d transformed by m, ignoring translation.
+func (m Mat4) TransformDir(d Vec3) Vec3 {
+	return m.MulVec(d.Vec4(0)).Vec3()
+}
+
+// Transpose returns m's transpose.
+func (m Mat4) Transpose() Mat4 {
+	return Mat4{
+		m[0], m[4], m[8], m[12],
+		m[1], m[5], m[9], m[13],
+		m[2], m[6], m[10], m[14],
+		m[3], m[7], m[11], m[15],
+	}
+}
+
+// Mat3 returns the upper-left 3x3 of m, its rotation and scale.
+func (m Mat4) Mat3() Mat3 {
+	return Mat3{m[0], m[1], m[2], m[4], m[5], m[6], m[8], m[9], m[10]}
+}
+
+// adjugate returns m's adjugate, the transpose of its cofactor matrix.
+func (m Mat4) adjugate() (a Mat4) {
+	a[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	a[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	a[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	a[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	a[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	a[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	a[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	a[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	a[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	a[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	a[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	a[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	a[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	a[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	a[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	a[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+	return a
+}
+
+// Det returns m's determinant.
+func (m Mat4) Det() float32 {
+	a := m.adjugate()
+	return m[0]*a[0] + m[1]*a[4] + m[2]*a[8] + m[3]*a[12]
+}
+
+// Inverse returns m's inverse and true, or the zero matrix and false if m is singular.
+func (m Mat4) Inverse() (Mat4, bool) {
+	a := m.adjugate()
+	det := m[0]*a[0] + m[1]*a[4] + m[2]*a[8] + m[3]*a[12]
+	if det == 0 {
+		return Mat4{}, false
+	}
+	for i := range a {
+		a[i] /= det
+	}
+	return a, true
+}
+
+// Translate returns the transform translating by v.
+func Translate(v Vec3) Mat4 { return Mat4{0: 1, 5: 1, 10: 1, 12: v[0], 13: v[1], 14: v[2], 15: 1} }
+
+// Scale returns the transform scaling by v.
+func Scale(v Vec3) Mat4 { return Mat4{0: v[0], 5: v[1], 10: v[2], 15: 1} }
+
+// Rotate returns the transform rotating by angle radians about axis.
+func Rotate(axis Vec3, angle float32) Mat4 { return QuatAxisAngle(axis, angle).Mat4() }
+
+// Compose returns the transform scaling by s, rotating by r, and then translating by t, as for an object placed in a
+// scene.
+func Compose(t Vec3, r Quat, s Vec3) Mat4 {
+	m := r.Mat3()
+	return Mat4{
+		m[0] * s[0], m[1] * s[0], m[2] * s[0], 0,
+		m[3] * s[1], m[4] * s[1], m[5] * s[1], 0,
+		m[6] * s[2], m[7] * s[2], m[8] * s[2], 0,
+		t[0], t[1], t[2], 1,
+	}
+}
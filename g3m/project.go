@@ -0,0 +1,59 @@
+package g3m
+
+import "math"
+
+// Perspective returns a perspective projection with a vertical field of view of fovy radians, the given aspect ratio
+// of width to height, and near and far clipping planes.
+func Perspective(fovy, aspect, near, far float32) Mat4 {
+	t := float32(1 / math.Tan(float64(fovy)/2))
+	return Mat4{
+		0: t / aspect, 5: t,
+		10: (far + near) / (near - far), 11: -1,
+		14: 2 * far * near / (near - far),
+	}
+}
+
+// Frustum returns a perspective projection of the view frustum with the given bounds on the near plane.
+func Frustum(left, right, bottom, top, near, far float32) Mat4 {
+	return Mat4{
+		0: 2 * near / (right - left), 5: 2 * near / (top - bottom),
+		8: (right + left) / (right - left), 9: (top + bottom) / (top - bottom),
+		10: (far + near) / (near - far), 11: -1,
+		14: 2 * far * near / (near - far),
+	}
+}
+
+// Ortho returns an orthographic projection of the given box in view space.
+func Ortho(left, right, bottom, top, near, far float32) Mat4 {
+	return Mat4{
+		0: 2 / (right - left), 5: 2 / (top - bottom), 10: -2 / (far - near),
+		12: -(right + left) / (right - left), 13: -(top + bottom) / (top - bottom), 14: -(far + near) / (far - near),
+		15: 1,
+	}
+}
+
+// Ortho2D returns an orthographic projection of the given rectangle, with near and far planes at 1 and -1. Passing a
+// top of 0 and a bottom equal to the height gives screen coordinates with a top-left origin.
+func Ortho2D(left, right, bottom, top float32) Mat4 {
+	return Ortho(left, right, bottom, top, -1, 1)
+}
+
+// LookAt returns the view transform of a camera at eye looking towards center, with up giving the rough direction of
+// the top of the view.
+func LookAt(eye, center, up Vec3) Mat4 {
+	return LookDir(eye, center.Sub(eye), up)
+}
+
+// LookDir returns the view transform of a camera at eye looking along dir, with up giving the rough direction of the
+// top of the view.
+func LookDir(eye, dir, up Vec3) Mat4 {
+	f := dir.Normalize()
+	s := f.Cross(up).Normalize()
+	u := s.Cross(f)
+	return Mat4{
+		s[0], u[0], -f[0], 0,
+		s[1], u[1], -f[1], 0,
+		s[2], u[2], -f[2], 0,
+		-s.Dot(eye), -u.Dot(eye), f.Dot(eye), 1,
+	}
+}
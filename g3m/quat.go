@@ -0,0 +1,120 @@
+package g3m
+
+import "math"
+
+// Quat is a quaternion, stored as X, Y, Z, and W, as a GLSL vec4. Unit quaternions represent rotations.
+type Quat [4]float32
+
+// QuatIdent returns the identity rotation.
+func QuatIdent() Quat { return Quat{0, 0, 0, 1} }
+
+// QuatAxisAngle returns the rotation of angle radians about axis, counterclockwise when looking down the axis towards
+// the origin.
+func QuatAxisAngle(axis Vec3, angle float32) Quat {
+	s, c := sincos(angle / 2)
+	a := axis.Normalize().Mul(s)
+	return Quat{a[0], a[1], a[2], c}
+}
+
+// QuatEuler returns the rotation by yaw about Y, then pitch about X, then roll about Z, in radians, applied in the
+// camera's frame. This matches a camera that turns, then looks up or down, then tilts.
+func QuatEuler(yaw, pitch, roll float32) Quat {
+	y := QuatAxisAngle(Vec3{0, 1, 0}, yaw)
+	p := QuatAxisAngle(Vec3{1, 0, 0}, pitch)
+	r := QuatAxisAngle(Vec3{0, 0, 1}, roll)
+	return y.Mul(p).Mul(r)
+}
+
+// Vec3 returns q's vector part.
+func (q Quat) Vec3() Vec3 { return Vec3{q[0], q[1], q[2]} }
+
+// Mul returns the product q*r, which rotates by r and then by q.
+func (q Quat) Mul(r Quat) Quat {
+	return Quat{
+		q[3]*r[0] + q[0]*r[3] + q[1]*r[2] - q[2]*r[1],
+		q[3]*r[1] - q[0]*r[2] + q[1]*r[3] + q[2]*r[0],
+		q[3]*r[2] + q[0]*r[1] - q[1]*r[0] + q[2]*r[3],
+		q[3]*r[3] - q[0]*r[0] - q[1]*r[1] - q[2]*r[2],
+	}
+}
+
+// Scale returns q scaled by s.
+func (q Quat) Scale(s float32) Quat { return Quat{q[0] * s, q[1] * s, q[2] * s, q[3] * s} }
+
+// Add returns q+r.
+func (q Quat) Add(r Quat) Quat { return Quat{q[0] + r[0], q[1] + r[1], q[2] + r[2], q[3] + r[3]} }
+
+// Dot returns the dot product of q and r.
+func (q Quat) Dot(r Quat) float32 { return q[0]*r[0] + q[1]*r[1] + q[2]*r[2] + q[3]*r[3] }
+
+// Len returns the length of q.
+func (q Quat) Len() float32 { return sqrt(q.Dot(q)) }
+
+// Normalize returns q scaled to unit length. A zero quaternion normalizes to the identity.
+func (q Quat) Normalize() Quat {
+	l := q.Len()
+	if l < Epsilon {
+		return QuatIdent()
+	}
+	return q.Scale(1 / l)
+}
+
+// Conjugate returns q's conjugate, which is its inverse if q is a unit quaternion.
+func (q Quat) Conjugate() Quat { return Quat{-q[0], -q[1], -q[2], q[3]} }
+
+// Inverse returns q's inverse. A zero quaternion is returned unchanged.
+func (q Quat) Inverse() Quat {
+	d := q.Dot(q)
+	if d < Epsilon {
+		return q
+	}
+	return q.Conjugate().Scale(1 / d)
+}
+
+// Rotate returns v rotated by the unit quaternion q.
+func (q Quat) Rotate(v Vec3) Vec3 {
+	u := q.Vec3()
+	t := u.Cross(v).Mul(2)
+	return v.Add(t.Mul(q[3])).Add(u.Cross(t))
+}
+
+// Lerp returns the normalized linear interpolation t of the way from q to r, along the shorter arc. It's cheaper than
+// Slerp but doesn't rotate at a constant speed.
+func (q Quat) Lerp(r Quat, t float32) Quat {
+	if q.Dot(r) < 0 {
+		r = r.Scale(-1)
+	}
+	return q.Scale(1 - t).Add(r.Scale(t)).Normalize()
+}
+
+// Slerp returns the spherical interpolation t of the way from the unit quaternion q to r, along the shorter arc.
+func (q Quat) Slerp(r Quat, t float32) Quat {
+	d := q.Dot(r)
+	if d < 0 {
+		r, d = r.Scale(-1), -d
+	}
+	if d > 1-Epsilon {
+		// Nearly parallel, where sin(theta) approaches zero.
+		return q.Lerp(r, t)
+	}
+	theta := math.Acos(float64(d))
+	sin := math.Sin(theta)
+	a := float32(math.Sin((1-float64(t))*theta) / sin)
+	b := float32(math.Sin(float64(t)*theta) / sin)
+	return q.Scale(a).Add(r.Scale(b))
+}
+
+// Mat3 returns the rotation matrix of the unit quaternion q.
+func (q Quat) Mat3() Mat3 {
+	x, y, z, w := q[0], q[1], q[2], q[3]
+	return Mat3{
+		1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w),
+		2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w),
+		2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y),
+	}
+}
+
+// Mat4 returns the homogeneous rotation matrix of the unit quaternion q.
+func (q Quat) Mat4() Mat4 {
+	return q.Mat3().Mat4()
+}
@@ -0,0 +1,125 @@
+package g3m
+
+// Vec2 is a 2D vector.
+type Vec2 [2]float32
+
+// Vec3 is a 3D vector.
+type Vec3 [3]float32
+
+// Vec4 is a 4D vector, such as a homogeneous point or an RGBA color.
+type Vec4 [4]float32
+
+// Add returns v+u.
+func (v Vec2) Add(u Vec2) Vec2 { return Vec2{v[0] + u[0], v[1] + u[1]} }
+
+// Sub returns v-u.
+func (v Vec2) Sub(u Vec2) Vec2 { return Vec2{v[0] - u[0], v[1] - u[1]} }
+
+// Mul returns v scaled by s.
+func (v Vec2) Mul(s float32) Vec2 { return Vec2{v[0] * s, v[1] * s} }
+
+// MulVec returns the component-wise product of v and u.
+func (v Vec2) MulVec(u Vec2) Vec2 { return Vec2{v[0] * u[0], v[1] * u[1]} }
+
+// Dot returns the dot product of v and u.
+func (v Vec2) Dot(u Vec2) float32 { return v[0]*u[0] + v[1]*u[1] }
+
+// Len returns the length of v.
+func (v Vec2) Len() float32 { return sqrt(v.Dot(v)) }
+
+// Normalize returns v scaled to unit length. A zero vector is returned unchanged.
+func (v Vec2) Normalize() Vec2 {
+	l := v.Len()
+	if l < Epsilon {
+		return v
+	}
+	return v.Mul(1 / l)
+}
+
+// Lerp returns the vector t of the way from v to u.
+func (v Vec2) Lerp(u Vec2, t float32) Vec2 { return v.Add(u.Sub(v).Mul(t)) }
+
+// Vec3 returns v extended with z.
+func (v Vec2) Vec3(z float32) Vec3 { return Vec3{v[0], v[1], z} }
+
+// Add returns v+u.
+func (v Vec3) Add(u Vec3) Vec3 { return Vec3{v[0] + u[0], v[1] + u[1], v[2] + u[2]} }
+
+// Sub returns v-u.
+func (v Vec3) Sub(u Vec3) Vec3 { return Vec3{v[0] - u[0], v[1] - u[1], v[2] - u[2]} }
+
+// Mul returns v scaled by s.
+func (v Vec3) Mul(s float32) Vec3 { return Vec3{v[0] * s, v[1] * s, v[2] * s} }
+
+// MulVec returns the component-wise product of v and u.
+func (v Vec3) MulVec(u Vec3) Vec3 { return Vec3{v[0] * u[0], v[1] * u[1], v[2] * u[2]} }
+
+// Dot returns the dot product of v and u.
+func (v Vec3) Dot(u Vec3) float32 { return v[0]*u[0] + v[1]*u[1] + v[2]*u[2] }
+
+// Cross returns the cross product of v and u.
+func (v Vec3) Cross(u Vec3) Vec3 {
+	return Vec3{v[1]*u[2] - v[2]*u[1], v[2]*u[0] - v[0]*u[2], v[0]*u[1] - v[1]*u[0]}
+}
+
+// Len returns the length of v.
+func (v Vec3) Len() float32 { return sqrt(v.Dot(v)) }
+
+// Normalize returns v scaled to unit length. A zero vector is returned unchanged.
+func (v Vec3) Normalize() Vec3 {
+	l := v.Len()
+	if l < Epsilon {
+		return v
+	}
+	return v.Mul(1 / l)
+}
+
+// Lerp returns the vector t of the way from v to u.
+func (v Vec3) Lerp(u Vec3, t float32) Vec3 { return v.Add(u.Sub(v).Mul(t)) }
+
+// Vec2 returns v's X and Y components.
+func (v Vec3) Vec2() Vec2 { return Vec2{v[0], v[1]} }
+
+// Vec4 returns v extended with w. Use a w of 1 for points and 0 for directions.
+func (v Vec3) Vec4(w float32) Vec4 { return Vec4{v[0], v[1], v[2], w} }
+
+// Add returns v+u.
+func (v Vec4) Add(u Vec4) Vec4 { return Vec4{v[0] + u[0], v[1] + u[1], v[2] + u[2], v[3] + u[3]} }
+
+// Sub returns v-u.
+func (v Vec4) Sub(u Vec4) Vec4 { return Vec4{v[0] - u[0], v[1] - u[1], v[2] - u[2], v[3] - u[3]} }
+
+// Mul returns v scaled by s.
+func (v Vec4) Mul(s float32) Vec4 { return Vec4{v[0] * s, v[1] * s, v[2] * s, v[3] * s} }
+
+// MulVec returns the component-wise product of v and u.
+func (v Vec4) MulVec(u Vec4) Vec4 { return Vec4{v[0] * u[0], v[1] * u[1], v[2] * u[2], v[3] * u[3]} }
+
+// Dot returns the dot product of v and u.
+func (v Vec4) Dot(u Vec4) float32 { return v[0]*u[0] + v[1]*u[1] + v[2]*u[2] + v[3]*u[3] }
+
+// Len returns the length of v.
+func (v Vec4) Len() float32 { return sqrt(v.Dot(v)) }
+
+// Normalize returns v scaled to unit length. A zero vector is returned unchanged.
+func (v Vec4) Normalize() Vec4 {
+	l := v.Len()
+	if l < Epsilon {
+		return v
+	}
+	return v.Mul(1 / l)
+}
+
+// Lerp returns the vector t of the way from v to u.
+func (v Vec4) Lerp(u Vec4, t float32) Vec4 { return v.Add(u.Sub(v).Mul(t)) }
+
+// Vec3 returns v's X, Y, and Z components.
+func (v Vec4) Vec3() Vec3 { return Vec3{v[0], v[1], v[2]} }
+
+// Project returns v divided by its W component, as a homogeneous point. If W is zero, v's X, Y, and Z are returned.
+func (v Vec4) Project() Vec3 {
+	if v[3] == 0 {
+		return v.Vec3()
+	}
+	return v.Vec3().Mul(1 / v[3])
+}
@@ -0,0 +1,25 @@
+package gfx
+
+import "github.com/go-gl/gl/v4.1-core/gl"
+
+// blendState is the GL blend state saved and restored around a batch's draw calls.
+type blendState struct {
+	enabled                            bool
+	srcRGB, dstRGB, srcAlpha, dstAlpha int32
+}
+
+func saveBlend() (s blendState) {
+	s.enabled = gl.IsEnabled(gl.BLEND)
+	gl.GetIntegerv(gl.BLEND_SRC_RGB, &s.srcRGB)
+	gl.GetIntegerv(gl.BLEND_DST_RGB, &s.dstRGB)
+	gl.GetIntegerv(gl.BLEND_SRC_ALPHA, &s.srcAlpha)
+	gl.GetIntegerv(gl.BLEND_DST_ALPHA, &s.dstAlpha)
+	return s
+}
+
+func (s blendState) restore() {
+	gl.BlendFuncSeparate(uint32(s.srcRGB), uint32(s.dstRGB), uint32(s.srcAlpha), uint32(s.dstAlpha))
+	if !s.enabled {
+		gl.Disable(gl.BLEND)
+	}
+}
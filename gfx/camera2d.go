@@ -0,0 +1,142 @@
+package gfx
+
+import (
+	"math"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/g3m"
+)
+
+// Camera2D is a 2D camera viewing a world whose Y axis points down, as in screen space, so that sprites can be drawn
+// at world positions transformed by WorldToScreen. Camera2D implements Projector for use with Coords.
+type Camera2D struct {
+	// X and Y are the world point at the center of the viewport.
+	X, Y float64
+	// Zoom is the number of screen units per world unit. Zero is treated as 1.
+	Zoom float64
+	// Rotation is the camera's rotation in radians. Rotating the camera clockwise rotates the world counterclockwise
+	// on screen.
+	Rotation float64
+
+	// ViewportX, ViewportY, ViewportW, and ViewportH are the screen region the camera draws to, in screen units with
+	// the origin at the top-left.
+	ViewportX, ViewportY float64
+	ViewportW, ViewportH float64
+
+	// MinX, MinY, MaxX, and MaxY bound the world region Clamp keeps the view within. Clamping is disabled on an axis
+	// if its maximum is not greater than its minimum.
+	MinX, MinY float64
+	MaxX, MaxY float64
+}
+
+func (c *Camera2D) zoom() float64 {
+	if c.Zoom == 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// WorldToScreen converts a world point to screen space.
+func (c *Camera2D) WorldToScreen(wx, wy float64) (x, y float64) {
+	z := c.zoom()
+	sin, cos := math.Sincos(c.Rotation)
+	dx, dy := wx-c.X, wy-c.Y
+	x = c.ViewportX + c.ViewportW/2 + z*(cos*dx+sin*dy)
+	y = c.ViewportY + c.ViewportH/2 + z*(-sin*dx+cos*dy)
+	return x, y
+}
+
+// ScreenToWorld converts a screen point, such as the cursor position, to world space.
+func (c *Camera2D) ScreenToWorld(x, y float64) (wx, wy float64) {
+	z := c.zoom()
+	sin, cos := math.Sincos(c.Rotation)
+	dx := (x - c.ViewportX - c.ViewportW/2) / z
+	dy := (y - c.ViewportY - c.ViewportH/2) / z
+	return c.X + cos*dx - sin*dy, c.Y + sin*dx + cos*dy
+}
+
+// ViewMatrix returns the matrix transforming world space to the viewport's normalized device coordinates, for use as a
+// shader's view-projection matrix while the GL viewport covers the camera's viewport.
+func (c *Camera2D) ViewMatrix() g3m.Mat4 {
+	if c.ViewportW == 0 || c.ViewportH == 0 {
+		return g3m.Ident4()
+	}
+	z := c.zoom()
+	sin, cos := math.Sincos(c.Rotation)
+	a, b := 2*z*cos/c.ViewportW, 2*z*sin/c.ViewportW
+	cc, d := 2*z*sin/c.ViewportH, -2*z*cos/c.ViewportH
+	return g3m.Mat4{
+		0: float32(a), 1: float32(cc),
+		4: float32(b), 5: float32(d),
+		10: 1,
+		12: float32(-(a*c.X + b*c.Y)), 13: float32(-(cc*c.X + d*c.Y)),
+		15: 1,
+	}
+}
+
+// WorldToNDC converts a world point to the viewport's normalized device coordinates. z is passed through.
+func (c *Camera2D) WorldToNDC(x, y, z float64) (nx, ny, nz float64) {
+	if c.ViewportW == 0 || c.ViewportH == 0 {
+		return 0, 0, z
+	}
+	sx, sy := c.WorldToScreen(x, y)
+	nx = 2*(sx-c.ViewportX)/c.ViewportW - 1
+	ny = 1 - 2*(sy-c.ViewportY)/c.ViewportH
+	return nx, ny, z
+}
+
+// NDCToWorld converts the viewport's normalized device coordinates to a world point. nz is passed through.
+func (c *Camera2D) NDCToWorld(nx, ny, nz float64) (x, y, z float64) {
+	sx := c.ViewportX + (nx+1)/2*c.ViewportW
+	sy := c.ViewportY + (1-ny)/2*c.ViewportH
+	x, y = c.ScreenToWorld(sx, sy)
+	return x, y, nz
+}
+
+// Follow moves the camera towards a target point, covering the fraction 1-exp(-rate*step) of the distance, so that it
+// closes in on the target at the same speed regardless of the sim's rate. A rate <= 0 moves the camera to the target.
+func (c *Camera2D) Follow(tx, ty, rate, step float64) {
+	t := 1.0
+	if rate > 0 {
+		t = 1 - math.Exp(-rate*step)
+	}
+	c.X += (tx - c.X) * t
+	c.Y += (ty - c.Y) * t
+}
+
+// Clamp moves the camera so that its view stays within its bounds. On an axis where the view is larger than the
+// bounds, the view is centered on them.
+func (c *Camera2D) Clamp() {
+	z := c.zoom()
+	sin, cos := math.Sincos(c.Rotation)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	// Half extents of the view's axis-aligned bounding box in world space.
+	hw := (cos*c.ViewportW + sin*c.ViewportH) / (2 * z)
+	hh := (sin*c.ViewportW + cos*c.ViewportH) / (2 * z)
+	c.X = clampView(c.X, hw, c.MinX, c.MaxX)
+	c.Y = clampView(c.Y, hh, c.MinY, c.MaxY)
+}
+
+func clampView(center, half, lo, hi float64) float64 {
+	switch {
+	case hi <= lo:
+		return center
+	case hi-lo <= 2*half:
+		return (lo + hi) / 2
+	case center-half < lo:
+		return lo + half
+	case center+half > hi:
+		return hi - half
+	}
+	return center
+}
+
+// FollowOp returns an op that makes the camera follow the point returned by target at rate, as by Follow, and then
+// clamps it to its bounds. It is meant to run as or after the Frame op that moves the target.
+func (c *Camera2D) FollowOp(target func() (x, y float64), rate float64) gt3.Op {
+	return gt3.ContextOpFn(func(ctx gt3.OpContext) {
+		tx, ty := target()
+		c.Follow(tx, ty, rate, ctx.Step)
+		c.Clamp()
+	})
+}
@@ -0,0 +1,276 @@
+package gfx
+
+import (
+	"math"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/g3m"
+)
+
+// Camera3D is a perspective or orthographic camera in a right-handed world with Y up. With zero Yaw and Pitch, it
+// looks down the -Z axis. Camera3D implements Projector for use with Coords.
+type Camera3D struct {
+	Position g3m.Vec3
+	// Yaw is the camera's rotation about the Y axis and Pitch its rotation above the horizon, in radians. Positive yaws
+	// turn the camera to the right.
+	Yaw, Pitch float64
+
+	// FOV is the vertical field of view in radians, for perspective projections.
+	FOV float64
+	// Orthographic selects an orthographic projection OrthoHeight world units tall instead of a perspective one.
+	Orthographic bool
+	OrthoHeight  float64
+	// Aspect is the viewport's width divided by its height.
+	Aspect    float64
+	Near, Far float64
+}
+
+// NewCamera3D returns a perspective camera at the origin with a 60 degree field of view, the given aspect ratio, and
+// near and far planes at 0.1 and 1000.
+func NewCamera3D(aspect float64) *Camera3D {
+	return &Camera3D{FOV: math.Pi / 3, Aspect: aspect, Near: 0.1, Far: 1000, OrthoHeight: 10}
+}
+
+// Forward returns the unit vector the camera looks along.
+func (c *Camera3D) Forward() g3m.Vec3 {
+	sy, cy := math.Sincos(c.Yaw)
+	sp, cp := math.Sincos(c.Pitch)
+	return g3m.Vec3{float32(cp * sy), float32(sp), float32(-cp * cy)}
+}
+
+// Right returns the unit vector to the camera's right, parallel to the ground.
+func (c *Camera3D) Right() g3m.Vec3 {
+	sy, cy := math.Sincos(c.Yaw)
+	return g3m.Vec3{float32(cy), 0, float32(sy)}
+}
+
+// Up returns the camera's unit up vector.
+func (c *Camera3D) Up() g3m.Vec3 {
+	return c.Right().Cross(c.Forward())
+}
+
+// LookAt turns the camera to face target.
+func (c *Camera3D) LookAt(target g3m.Vec3) {
+	d := target.Sub(c.Position)
+	if d == (g3m.Vec3{}) {
+		return
+	}
+	x, y, z := float64(d[0]), float64(d[1]), float64(d[2])
+	c.Yaw = math.Atan2(x, -z)
+	c.Pitch = math.Atan2(y, math.Hypot(x, z))
+}
+
+// ViewMatrix returns the matrix transforming world space to view space.
+func (c *Camera3D) ViewMatrix() g3m.Mat4 {
+	return g3m.LookDir(c.Position, c.Forward(), g3m.Vec3{0, 1, 0})
+}
+
+// ProjectionMatrix returns the matrix transforming view space to clip space.
+func (c *Camera3D) ProjectionMatrix() g3m.Mat4 {
+	n, f, aspect := float32(c.Near), float32(c.Far), float32(c.Aspect)
+	if aspect == 0 {
+		aspect = 1
+	}
+	if c.Orthographic {
+		h := float32(c.OrthoHeight) / 2
+		w := h * aspect
+		return g3m.Ortho(-w, w, -h, h, n, f)
+	}
+	return g3m.Perspective(float32(c.FOV), aspect, n, f)
+}
+
+// ViewProjectionMatrix returns the product of the projection and view matrices.
+func (c *Camera3D) ViewProjectionMatrix() g3m.Mat4 {
+	return c.ProjectionMatrix().Mul(c.ViewMatrix())
+}
+
+// WorldToNDC converts a world point to normalized device coordinates.
+func (c *Camera3D) WorldToNDC(x, y, z float64) (nx, ny, nz float64) {
+	p := c.ViewProjectionMatrix().TransformPoint(g3m.Vec3{float32(x), float32(y), float32(z)})
+	return float64(p[0]), float64(p[1]), float64(p[2])
+}
+
+// NDCToWorld converts normalized device coordinates to a world point.
+func (c *Camera3D) NDCToWorld(nx, ny, nz float64) (x, y, z float64) {
+	inv, ok := c.ViewProjectionMatrix().Inverse()
+	if !ok {
+		return 0, 0, 0
+	}
+	p := inv.TransformPoint(g3m.Vec3{float32(nx), float32(ny), float32(nz)})
+	return float64(p[0]), float64(p[1]), float64(p[2])
+}
+
+// FPSController moves a Camera3D like a first-person shooter: W, A, S, and D move it, Space and Left Shift move it up
+// and down, and moving the cursor turns it. FPSController is an EventHandler and should be subscribed to a window's
+// KeyEvents and CursorPosEvents. Its Op applies movement and should run every sim frame.
+type FPSController struct {
+	Camera *Camera3D
+	// Speed is the movement speed in world units per second.
+	Speed float64
+	// Sensitivity is the rotation in radians per screen unit of cursor motion.
+	Sensitivity float64
+	// Look requires the right mouse button to be held to turn the camera if true. Otherwise, all cursor motion turns
+	// it, as suits a disabled cursor.
+	Look bool
+
+	forward, back, left, right, up, down bool
+	looking                              bool
+	cursorX, cursorY                     float64
+	cursorSeen                           bool
+}
+
+// NewFPSController returns an FPSController for c with a speed of 5 units per second and a sensitivity of 0.003.
+func NewFPSController(c *Camera3D) *FPSController {
+	return &FPSController{Camera: c, Speed: 5, Sensitivity: 0.003}
+}
+
+// Event updates the controller's key and cursor state.
+func (fc *FPSController) Event(e gt3.Event, _ time.Time) {
+	switch ev := e.(type) {
+	case gt3.KeyEvent:
+		if ev.Action == gt3.Repeat {
+			return
+		}
+		down := ev.Action == gt3.Press
+		switch ev.Key {
+		case gt3.KeyW:
+			fc.forward = down
+		case gt3.KeyS:
+			fc.back = down
+		case gt3.KeyA:
+			fc.left = down
+		case gt3.KeyD:
+			fc.right = down
+		case gt3.KeySpace:
+			fc.up = down
+		case gt3.KeyLeftShift:
+			fc.down = down
+		}
+	case gt3.MouseEvent:
+		if ev.Button == gt3.MouseButtonRight {
+			fc.looking = ev.Action == gt3.Press
+		}
+	case gt3.CursorPosEvent:
+		dx, dy := ev.X-fc.cursorX, ev.Y-fc.cursorY
+		fc.cursorX, fc.cursorY = ev.X, ev.Y
+		if !fc.cursorSeen {
+			fc.cursorSeen = true
+			return
+		}
+		if fc.Look && !fc.looking {
+			return
+		}
+		c := fc.Camera
+		c.Yaw += dx * fc.Sensitivity
+		c.Pitch = clampPitch(c.Pitch - dy*fc.Sensitivity)
+	}
+}
+
+// Update moves the camera for a sim frame of step seconds.
+func (fc *FPSController) Update(step float64) {
+	var move g3m.Vec3
+	f, r := fc.Camera.Forward(), fc.Camera.Right()
+	f = g3m.Vec3{f[0], 0, f[2]}.Normalize()
+	if fc.forward {
+		move = move.Add(f)
+	}
+	if fc.back {
+		move = move.Sub(f)
+	}
+	if fc.right {
+		move = move.Add(r)
+	}
+	if fc.left {
+		move = move.Sub(r)
+	}
+	if fc.up {
+		move[1]++
+	}
+	if fc.down {
+		move[1]--
+	}
+	move = move.Normalize().Mul(float32(fc.Speed * step))
+	fc.Camera.Position = fc.Camera.Position.Add(move)
+}
+
+// Op returns an op calling Update with the sim's step.
+func (fc *FPSController) Op() gt3.Op {
+	return gt3.ContextOpFn(func(ctx gt3.OpContext) { fc.Update(ctx.Step) })
+}
+
+// OrbitController orbits a Camera3D around a target point: dragging with the left mouse button turns it around the
+// target and scrolling moves it closer or further away. OrbitController is an EventHandler and should be subscribed to
+// a window's MouseEvents, CursorPosEvents, and ScrollEvents. It updates the camera as events arrive.
+type OrbitController struct {
+	Camera *Camera3D
+	Target g3m.Vec3
+	// Distance is the camera's distance from the target, kept between MinDistance and MaxDistance if MaxDistance > 0.
+	Distance                 float64
+	MinDistance, MaxDistance float64
+	// Yaw and Pitch are the camera's angles around the target.
+	Yaw, Pitch float64
+	// Sensitivity is the rotation in radians per screen unit dragged.
+	Sensitivity float64
+	// ZoomFactor is the factor the distance is scaled by per unit scrolled.
+	ZoomFactor float64
+
+	dragging         bool
+	cursorX, cursorY float64
+}
+
+// NewOrbitController returns an OrbitController for c orbiting target at distance, with a sensitivity of 0.01 and a
+// zoom factor of 0.9, and places the camera.
+func NewOrbitController(c *Camera3D, target g3m.Vec3, distance float64) *OrbitController {
+	oc := &OrbitController{
+		Camera:      c,
+		Target:      target,
+		Distance:    distance,
+		MinDistance: 0.1,
+		Sensitivity: 0.01,
+		ZoomFactor:  0.9,
+	}
+	oc.Apply()
+	return oc
+}
+
+// Event updates the orbit from mouse input.
+func (oc *OrbitController) Event(e gt3.Event, _ time.Time) {
+	switch ev := e.(type) {
+	case gt3.MouseEvent:
+		if ev.Button == gt3.MouseButtonLeft {
+			oc.dragging = ev.Action == gt3.Press
+		}
+	case gt3.CursorPosEvent:
+		dx, dy := ev.X-oc.cursorX, ev.Y-oc.cursorY
+		oc.cursorX, oc.cursorY = ev.X, ev.Y
+		if !oc.dragging {
+			return
+		}
+		oc.Yaw += dx * oc.Sensitivity
+		oc.Pitch = clampPitch(oc.Pitch + dy*oc.Sensitivity)
+		oc.Apply()
+	case gt3.ScrollEvent:
+		oc.Distance *= math.Pow(oc.ZoomFactor, ev.YOff)
+		oc.Apply()
+	}
+}
+
+// Apply places the camera according to the orbit, clamping its distance.
+func (oc *OrbitController) Apply() {
+	if oc.Distance < oc.MinDistance {
+		oc.Distance = oc.MinDistance
+	}
+	if oc.MaxDistance > 0 && oc.Distance > oc.MaxDistance {
+		oc.Distance = oc.MaxDistance
+	}
+	c := oc.Camera
+	c.Yaw, c.Pitch = oc.Yaw, oc.Pitch
+	c.Position = oc.Target.Sub(c.Forward().Mul(float32(oc.Distance)))
+}
+
+// clampPitch keeps pitch just short of straight up or down, where yaw becomes ambiguous.
+func clampPitch(pitch float64) float64 {
+	const limit = math.Pi/2 - 0.001
+	return math.Max(-limit, math.Min(limit, pitch))
+}
@@ -0,0 +1,117 @@
+package gfx
+
+import (
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// Projector maps between world space and normalized device coordinates. Cameras registered with Coords implement
+// Projector.
+type Projector interface {
+	WorldToNDC(x, y, z float64) (nx, ny, nz float64)
+	NDCToWorld(nx, ny, nz float64) (x, y, z float64)
+}
+
+// Coords converts points between the coordinate spaces of a window:
+//
+//   - Screen: window coordinates as reported by cursor events, origin at the top-left.
+//   - Framebuffer: pixels, origin at the bottom-left as in OpenGL.
+//   - NDC: normalized device coordinates of the viewport, from -1 to 1 with Y up.
+//   - World: through the registered Camera.
+//
+// Coords is an EventHandler and keeps its window and framebuffer sizes current from ResizeEvents and
+// FramebufferSizeEvents, so it should be subscribed to the window's events.
+type Coords struct {
+	WindowW, WindowH           int
+	FramebufferW, FramebufferH int
+
+	// Viewport is the framebuffer region NDC is relative to. If empty, the whole framebuffer is used.
+	Viewport Rect
+	// Camera is used to convert between NDC and world space. It may be nil if world conversions aren't used.
+	Camera Projector
+}
+
+// Event updates the window or framebuffer size from ResizeEvents and FramebufferSizeEvents.
+func (c *Coords) Event(e gt3.Event, _ time.Time) {
+	switch ev := e.(type) {
+	case gt3.ResizeEvent:
+		c.WindowW, c.WindowH = ev.Width, ev.Height
+	case gt3.FramebufferSizeEvent:
+		c.FramebufferW, c.FramebufferH = ev.Width, ev.Height
+	}
+}
+
+// Scale returns the ratio of framebuffer pixels to screen coordinates on each axis. This is greater than 1 on high-DPI
+// displays.
+func (c *Coords) Scale() (sx, sy float64) {
+	if c.WindowW <= 0 || c.WindowH <= 0 {
+		return 1, 1
+	}
+	return float64(c.FramebufferW) / float64(c.WindowW), float64(c.FramebufferH) / float64(c.WindowH)
+}
+
+func (c *Coords) viewport() Rect {
+	if c.Viewport.W > 0 && c.Viewport.H > 0 {
+		return c.Viewport
+	}
+	return Rect{0, 0, c.FramebufferW, c.FramebufferH}
+}
+
+// ScreenToFramebuffer converts a screen point to framebuffer pixels.
+func (c *Coords) ScreenToFramebuffer(x, y float64) (px, py float64) {
+	sx, sy := c.Scale()
+	return x * sx, float64(c.FramebufferH) - y*sy
+}
+
+// FramebufferToScreen converts framebuffer pixels to a screen point.
+func (c *Coords) FramebufferToScreen(px, py float64) (x, y float64) {
+	sx, sy := c.Scale()
+	return px / sx, (float64(c.FramebufferH) - py) / sy
+}
+
+// FramebufferToNDC converts framebuffer pixels to normalized device coordinates of the viewport.
+func (c *Coords) FramebufferToNDC(px, py float64) (nx, ny float64) {
+	vp := c.viewport()
+	if vp.W <= 0 || vp.H <= 0 {
+		return 0, 0
+	}
+	return 2*(px-float64(vp.X))/float64(vp.W) - 1, 2*(py-float64(vp.Y))/float64(vp.H) - 1
+}
+
+// NDCToFramebuffer converts normalized device coordinates of the viewport to framebuffer pixels.
+func (c *Coords) NDCToFramebuffer(nx, ny float64) (px, py float64) {
+	vp := c.viewport()
+	return float64(vp.X) + (nx+1)/2*float64(vp.W), float64(vp.Y) + (ny+1)/2*float64(vp.H)
+}
+
+// ScreenToNDC converts a screen point to normalized device coordinates of the viewport.
+func (c *Coords) ScreenToNDC(x, y float64) (nx, ny float64) {
+	return c.FramebufferToNDC(c.ScreenToFramebuffer(x, y))
+}
+
+// NDCToScreen converts normalized device coordinates of the viewport to a screen point.
+func (c *Coords) NDCToScreen(nx, ny float64) (x, y float64) {
+	return c.FramebufferToScreen(c.NDCToFramebuffer(nx, ny))
+}
+
+// ScreenToWorld converts a screen point at the NDC depth nz (-1 for the near plane, 1 for the far plane) to world
+// space through the Camera. It returns the zero point if no Camera is set.
+func (c *Coords) ScreenToWorld(x, y, nz float64) (wx, wy, wz float64) {
+	if c.Camera == nil {
+		return 0, 0, 0
+	}
+	nx, ny := c.ScreenToNDC(x, y)
+	return c.Camera.NDCToWorld(nx, ny, nz)
+}
+
+// WorldToScreen converts a point in world space to a screen point and its NDC depth through the Camera. It returns the
+// zero point if no Camera is set.
+func (c *Coords) WorldToScreen(wx, wy, wz float64) (x, y, nz float64) {
+	if c.Camera == nil {
+		return 0, 0, 0
+	}
+	nx, ny, nz := c.Camera.WorldToNDC(wx, wy, wz)
+	x, y = c.NDCToScreen(nx, ny)
+	return x, y, nz
+}
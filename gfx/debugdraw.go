@@ -0,0 +1,195 @@
+package gfx
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"golang.org/x/image/font/basicfont"
+)
+
+// DebugDraw batches solid rectangles and fixed-width bitmap text for debug overlays. Coordinates are in screen units
+// with the origin at the top-left, matching cursor events. Text uses basicfont.Face7x13.
+type DebugDraw struct {
+	prog, vao, vbo, tex uint32
+	uScreen             int32
+
+	verts []debugVertex
+}
+
+type debugVertex struct {
+	x, y, u, v float32
+	color      [4]uint8
+}
+
+const debugVertexSize = 20
+
+var debugFont = basicfont.Face7x13
+
+const debugVertexSrc = `#version 410 core
+uniform vec2 uScreen;
+layout(location = 0) in vec2 aPos;
+layout(location = 1) in vec2 aUV;
+layout(location = 2) in vec4 aColor;
+out vec2 vUV;
+out vec4 vColor;
+void main() {
+	vUV = aUV;
+	vColor = aColor;
+	gl_Position = vec4(aPos.x/uScreen.x*2.0 - 1.0, 1.0 - aPos.y/uScreen.y*2.0, 0.0, 1.0);
+}
+`
+
+const debugFragmentSrc = `#version 410 core
+uniform sampler2D uTex;
+in vec2 vUV;
+in vec4 vColor;
+out vec4 fragColor;
+void main() {
+	fragColor = vec4(vColor.rgb, vColor.a * texture(uTex, vUV).r);
+}
+`
+
+// NewDebugDraw allocates the GL resources for a DebugDraw. It must be called with a current GL context.
+func NewDebugDraw() (*DebugDraw, error) {
+	prog, err := LoadProgram(debugVertexSrc, debugFragmentSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DebugDraw{prog: prog.ID}
+	d.uScreen = prog.Uniform("uScreen")
+
+	// The atlas is the font's glyph mask with one extra opaque row, sampled for solid rectangles.
+	mb := debugFont.Mask.Bounds()
+	atlas := image.NewAlpha(image.Rect(0, 0, mb.Dx(), mb.Dy()+1))
+	draw.Draw(atlas, mb.Sub(mb.Min), debugFont.Mask, mb.Min, draw.Src)
+	for x := 0; x < mb.Dx(); x++ {
+		atlas.SetAlpha(x, mb.Dy(), color.Alpha{0xff})
+	}
+
+	gl.GenTextures(1, &d.tex)
+	gl.BindTexture(gl.TEXTURE_2D, d.tex)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, int32(atlas.Rect.Dx()), int32(atlas.Rect.Dy()), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(atlas.Pix))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	gl.GenVertexArrays(1, &d.vao)
+	gl.GenBuffers(1, &d.vbo)
+	gl.BindVertexArray(d.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, d.vbo)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointerWithOffset(0, 2, gl.FLOAT, false, debugVertexSize, 0)
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, debugVertexSize, 8)
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointerWithOffset(2, 4, gl.UNSIGNED_BYTE, true, debugVertexSize, 16)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	return d, nil
+}
+
+func (d *DebugDraw) quad(x0, y0, x1, y1, u0, v0, u1, v1 float32, c color.RGBA) {
+	rgba := [4]uint8{c.R, c.G, c.B, c.A}
+	d.verts = append(d.verts,
+		debugVertex{x0, y0, u0, v0, rgba},
+		debugVertex{x1, y0, u1, v0, rgba},
+		debugVertex{x1, y1, u1, v1, rgba},
+		debugVertex{x0, y0, u0, v0, rgba},
+		debugVertex{x1, y1, u1, v1, rgba},
+		debugVertex{x0, y1, u0, v1, rgba},
+	)
+}
+
+func (d *DebugDraw) atlasSize() (w, h float32) {
+	mb := debugFont.Mask.Bounds()
+	return float32(mb.Dx()), float32(mb.Dy() + 1)
+}
+
+// FillRect queues a solid rectangle. Color is non-premultiplied.
+func (d *DebugDraw) FillRect(x, y, w, h float64, c color.RGBA) {
+	aw, ah := d.atlasSize()
+	v := (ah - 0.5) / ah
+	d.quad(float32(x), float32(y), float32(x+w), float32(y+h), 0.5/aw, v, 0.5/aw, v, c)
+}
+
+// TextSize returns the size of s when drawn by Text.
+func (d *DebugDraw) TextSize(s string) (w, h float64) {
+	n := 0
+	for range s {
+		n++
+	}
+	return float64(n * debugFont.Advance), float64(debugFont.Height)
+}
+
+// Text queues s to be drawn with its top-left corner at x, y. Color is non-premultiplied.
+func (d *DebugDraw) Text(x, y float64, s string, c color.RGBA) {
+	aw, ah := d.atlasSize()
+	gw, gh := float32(debugFont.Width), float32(debugFont.Height)
+	for _, r := range s {
+		if idx, ok := debugGlyph(r); ok && r != ' ' {
+			v0 := float32(idx) * gh / ah
+			v1 := float32(idx+1) * gh / ah
+			d.quad(float32(x), float32(y), float32(x)+gw, float32(y)+gh, 0, v0, gw/aw, v1, c)
+		}
+		x += float64(debugFont.Advance)
+	}
+}
+
+func debugGlyph(r rune) (int, bool) {
+	for _, rr := range debugFont.Ranges {
+		if r >= rr.Low && r < rr.High {
+			return rr.Offset + int(r-rr.Low), true
+		}
+	}
+	if r != '?' {
+		return debugGlyph('?')
+	}
+	return 0, false
+}
+
+// Flush draws all queued rectangles and text over the current framebuffer, treating it as width x height screen units,
+// and clears the queue. Blending is enabled and depth testing disabled while drawing; the blend state and depth testing
+// are restored afterwards.
+func (d *DebugDraw) Flush(width, height int) {
+	if len(d.verts) == 0 {
+		return
+	}
+
+	blend, depth := saveBlend(), gl.IsEnabled(gl.DEPTH_TEST)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+
+	gl.UseProgram(d.prog)
+	gl.Uniform2f(d.uScreen, float32(width), float32(height))
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, d.tex)
+	gl.BindVertexArray(d.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, d.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(d.verts)*debugVertexSize, gl.Ptr(d.verts), gl.STREAM_DRAW)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(d.verts)))
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.UseProgram(0)
+
+	blend.restore()
+	if depth {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+	d.verts = d.verts[:0]
+}
+
+// Delete frees the DebugDraw's GL resources.
+func (d *DebugDraw) Delete() {
+	gl.DeleteProgram(d.prog)
+	gl.DeleteVertexArrays(1, &d.vao)
+	gl.DeleteBuffers(1, &d.vbo)
+	gl.DeleteTextures(1, &d.tex)
+}
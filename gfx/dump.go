@@ -0,0 +1,169 @@
+package gfx
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// FrameDumper writes rendered frames to a directory as a numbered sequence of PNG files. Frames are read back
+// asynchronously through a ring of pixel buffer objects and encoded and written on a separate goroutine, so neither
+// readback nor encoding stalls rendering. If every buffer is still in flight or the encoder has fallen behind, frames
+// are dropped rather than waited on; Dropped reports how many. Dumping is gated by a toggle and is initially disabled.
+type FrameDumper struct {
+	// Dir is the directory frames are written to.
+	Dir string
+	// Pattern is the fmt pattern for file names, given the frame number. Defaults to "frame%06d.png".
+	Pattern string
+	// Every is how many rendered frames to skip between dumps. 0 or 1 dumps every frame.
+	Every int
+
+	enabled int32
+	frame   uint64 // Frames seen while enabled
+	dropped uint64
+
+	ring pixelRing
+
+	jobs chan dumpJob
+	wg   sync.WaitGroup
+
+	errMu sync.Mutex
+	err   error
+}
+
+type dumpJob struct {
+	n   uint64
+	img *image.RGBA
+}
+
+// dumpDepth is the number of frames a FrameDumper may have in flight on the GPU.
+const dumpDepth = 3
+
+// NewFrameDumper returns a FrameDumper writing every Nth frame to dir. It starts a goroutine that runs until Close is
+// called.
+func NewFrameDumper(dir string, every int) *FrameDumper {
+	d := &FrameDumper{
+		Dir:     dir,
+		Pattern: "frame%06d.png",
+		Every:   every,
+		jobs:    make(chan dumpJob, 8),
+	}
+	d.wg.Add(1)
+	go d.run()
+	return d
+}
+
+// SetEnabled turns frame dumping on or off. It may be called from any goroutine.
+func (d *FrameDumper) SetEnabled(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&d.enabled, v)
+}
+
+// Enabled reports whether frame dumping is on.
+func (d *FrameDumper) Enabled() bool {
+	return atomic.LoadInt32(&d.enabled) != 0
+}
+
+// Toggle flips frame dumping on or off, returning the new state.
+func (d *FrameDumper) Toggle() bool {
+	for {
+		old := atomic.LoadInt32(&d.enabled)
+		if atomic.CompareAndSwapInt32(&d.enabled, old, 1-old) {
+			return old == 0
+		}
+	}
+}
+
+// Capture queues earlier frames whose readbacks have finished to be written and, if dumping is enabled and the frame
+// isn't skipped, starts a readback of a frame of the given size from the current read framebuffer. Capture must be
+// called on the main thread after rendering and before swapping buffers, such as from a PostRender op.
+func (d *FrameDumper) Capture(width, height int) {
+	d.ring.complete(false, d.queue)
+
+	if !d.Enabled() {
+		return
+	}
+
+	n := d.frame
+	d.frame++
+	if d.Every > 1 && n%uint64(d.Every) != 0 {
+		return
+	}
+
+	if pb := d.ring.read(width, height, dumpDepth); pb != nil {
+		pb.n = n
+	} else {
+		atomic.AddUint64(&d.dropped, 1)
+	}
+}
+
+// queue queues pb's finished frame to be written, dropping it if the encoder is behind.
+func (d *FrameDumper) queue(pb *pixelBuffer) {
+	select {
+	case d.jobs <- dumpJob{pb.n, pb.image()}:
+	default:
+		atomic.AddUint64(&d.dropped, 1)
+	}
+}
+
+// Dropped returns the number of frames dropped because every readback buffer was in flight or the encoder was behind.
+func (d *FrameDumper) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// Op returns an Op that captures a frame the size of the current viewport. It is intended for use as, or in, a Sim's
+// PostRender op.
+func (d *FrameDumper) Op() gt3.Op {
+	return gt3.OpFn(func(float64, float64, time.Time) {
+		d.Capture(viewportSize())
+	})
+}
+
+func (d *FrameDumper) run() {
+	defer d.wg.Done()
+	for job := range d.jobs {
+		if err := d.write(job); err != nil {
+			d.errMu.Lock()
+			if d.err == nil {
+				d.err = err
+			}
+			d.errMu.Unlock()
+		}
+	}
+}
+
+func (d *FrameDumper) write(job dumpJob) error {
+	f, err := os.Create(filepath.Join(d.Dir, fmt.Sprintf(d.Pattern, job.n)))
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, job.img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close finishes frames still being read back, waits for queued frames to be written, frees the FrameDumper's buffers,
+// and stops its goroutine. It returns the first error encountered writing frames, if any. Close must be called on the
+// main thread with the same GL context current as for Capture. Capture must not be called after Close.
+func (d *FrameDumper) Close() error {
+	d.ring.complete(true, func(pb *pixelBuffer) { d.jobs <- dumpJob{pb.n, pb.image()} })
+	d.ring.delete()
+	close(d.jobs)
+	d.wg.Wait()
+
+	d.errMu.Lock()
+	defer d.errMu.Unlock()
+	return d.err
+}
@@ -0,0 +1,47 @@
+package gfx
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.spiff.io/gt3"
+)
+
+// EffectiveFramebuffer queries the configuration of the default framebuffer of the current GL context.
+func EffectiveFramebuffer() gt3.FramebufferConfig {
+	var (
+		fb      gt3.FramebufferConfig
+		samples int32
+		depth   int32
+		stencil int32
+		double  bool
+	)
+
+	prev := bindDrawFramebuffer(0)
+	defer bindDrawFramebuffer(prev)
+
+	gl.GetIntegerv(gl.SAMPLES, &samples)
+	gl.GetBooleanv(gl.DOUBLEBUFFER, &double)
+	gl.GetFramebufferAttachmentParameteriv(gl.DRAW_FRAMEBUFFER, gl.DEPTH, gl.FRAMEBUFFER_ATTACHMENT_DEPTH_SIZE, &depth)
+	gl.GetFramebufferAttachmentParameteriv(gl.DRAW_FRAMEBUFFER, gl.STENCIL, gl.FRAMEBUFFER_ATTACHMENT_STENCIL_SIZE, &stencil)
+
+	fb.Samples = int(samples)
+	fb.DepthBits = int(depth)
+	fb.StencilBits = int(stencil)
+	fb.DoubleBuffer = double
+	fb.SRGB = SRGBFramebuffer()
+	return fb
+}
+
+// ValidateFramebuffer compares the framebuffer requested for w with the effective configuration of its default
+// framebuffer. It returns the effective configuration and, if it doesn't satisfy the request, a
+// *gt3.FramebufferMismatchError. w's context must be current.
+func ValidateFramebuffer(w *gt3.Window) (gt3.FramebufferConfig, error) {
+	eff := EffectiveFramebuffer()
+	return eff, eff.Satisfies(w.RequestedFramebuffer())
+}
+
+func bindDrawFramebuffer(fbo uint32) (previous uint32) {
+	var prev int32
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &prev)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, fbo)
+	return uint32(prev)
+}
@@ -0,0 +1,215 @@
+package gfx
+
+import (
+	"image"
+	"time"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.spiff.io/gt3"
+)
+
+// AsyncReader reads back requested frames through a ring of pixel buffer objects. Unlike FrameDumper, it never drops a
+// requested capture: if every buffer is still in flight, it waits on the oldest.
+type AsyncReader struct {
+	sim      *gt3.Sim
+	pbos     []pixelBuffer
+	next     int
+	requests []func(image.Image)
+}
+
+// A pixelBuffer is a pixel buffer object that a frame is read back into asynchronously. A readback is issued into a
+// PBO on one frame and only mapped once the GPU has signaled that it's complete, usually a frame or two later, so
+// capturing a frame doesn't stall the GPU pipeline the way glReadPixels into client memory does.
+type pixelBuffer struct {
+	id            uint32
+	fence         uintptr
+	width, height int
+	size          int
+	n             uint64              // Frame number, for FrameDumper
+	callbacks     []func(image.Image) // Captures waiting on the readback, for AsyncReader
+}
+
+// pixelRing is a ring of pixel buffers for readers that drop frames rather than wait on the GPU, such as FrameDumper.
+// Buffers are created on the first read.
+type pixelRing struct {
+	pbos []pixelBuffer
+	next int // Next buffer to read into; the oldest in flight, if any are
+}
+
+// read starts a readback of a width x height region of the current read framebuffer into the next buffer and returns
+// it, or returns nil if every buffer is still in flight.
+func (r *pixelRing) read(width, height, depth int) *pixelBuffer {
+	if r.pbos == nil {
+		r.pbos = make([]pixelBuffer, depth)
+		for i := range r.pbos {
+			gl.GenBuffers(1, &r.pbos[i].id)
+		}
+	}
+	pb := &r.pbos[r.next]
+	if pb.fence != 0 {
+		return nil
+	}
+	r.next = (r.next + 1) % len(r.pbos)
+	pb.read(width, height)
+	return pb
+}
+
+// complete calls fn with each buffer whose readback has finished, oldest first, stopping at the first that hasn't
+// finished so that frames complete in the order they were read. If wait is true, it waits for every readback.
+func (r *pixelRing) complete(wait bool, fn func(*pixelBuffer)) {
+	for i := range r.pbos {
+		pb := &r.pbos[(r.next+i)%len(r.pbos)]
+		if pb.fence == 0 {
+			continue
+		}
+		if !pb.done(wait) {
+			return
+		}
+		fn(pb)
+	}
+}
+
+// delete frees the ring's buffers.
+func (r *pixelRing) delete() {
+	for i := range r.pbos {
+		r.pbos[i].delete()
+	}
+	r.pbos, r.next = nil, 0
+}
+
+// NewAsyncReader returns an AsyncReader with a ring of depth PBOs, delivering captured images through sim's Sched.
+// A depth of at least 2 is needed for readbacks to overlap rendering; values less than 2 are raised to 2. It must be
+// called with a current GL context.
+func NewAsyncReader(sim *gt3.Sim, depth int) *AsyncReader {
+	if depth < 2 {
+		depth = 2
+	}
+	r := &AsyncReader{sim: sim, pbos: make([]pixelBuffer, depth)}
+	for i := range r.pbos {
+		gl.GenBuffers(1, &r.pbos[i].id)
+	}
+	return r
+}
+
+// CaptureAsync requests a capture of the next frame processed by Update. Once the readback completes, fn is called on
+// the main goroutine, via the Sim's Sched, with the frame's image. CaptureAsync must be called from the main goroutine.
+func (r *AsyncReader) CaptureAsync(fn func(image.Image)) {
+	r.requests = append(r.requests, fn)
+}
+
+// Update completes any finished readbacks and, if captures have been requested, issues a readback of a width x height
+// region of the current read framebuffer. It must be called on the main thread after rendering and before swapping
+// buffers, such as from a PostRender op.
+func (r *AsyncReader) Update(width, height int) {
+	for i := range r.pbos {
+		r.complete(&r.pbos[i], false)
+	}
+
+	if len(r.requests) == 0 {
+		return
+	}
+
+	pb := &r.pbos[r.next]
+	if pb.fence != 0 {
+		// Every PBO is in flight: block on the oldest rather than dropping the request.
+		r.complete(pb, true)
+	}
+	r.next = (r.next + 1) % len(r.pbos)
+
+	pb.callbacks, r.requests = r.requests, nil
+	pb.read(width, height)
+}
+
+// read issues a readback of a width x height region of the current read framebuffer into pb, fenced so that its
+// completion can be polled.
+func (pb *pixelBuffer) read(width, height int) {
+	pb.width, pb.height = width, height
+	size := width * height * 4
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, pb.id)
+	if size != pb.size {
+		gl.BufferData(gl.PIXEL_PACK_BUFFER, size, nil, gl.STREAM_READ)
+		pb.size = size
+	}
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+
+	pb.fence = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+}
+
+// done reports whether pb's readback has finished, releasing its fence if so. If wait is true, it blocks until the
+// readback finishes or a second passes.
+func (pb *pixelBuffer) done(wait bool) bool {
+	if pb.fence == 0 {
+		return false
+	}
+
+	var timeout uint64
+	if wait {
+		timeout = uint64(time.Second)
+	}
+	switch gl.ClientWaitSync(pb.fence, gl.SYNC_FLUSH_COMMANDS_BIT, timeout) {
+	case gl.ALREADY_SIGNALED, gl.CONDITION_SATISFIED:
+	case gl.WAIT_FAILED:
+		// Nothing left to wait on; map the buffer anyway.
+	default:
+		return false
+	}
+	gl.DeleteSync(pb.fence)
+	pb.fence = 0
+	return true
+}
+
+// image maps pb and copies its finished readback into a new image, flipped to a top-left origin.
+func (pb *pixelBuffer) image() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, pb.width, pb.height))
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, pb.id)
+	if ptr := gl.MapBufferRange(gl.PIXEL_PACK_BUFFER, 0, pb.size, gl.MAP_READ_BIT); ptr != nil {
+		copy(img.Pix, unsafe.Slice((*byte)(ptr), pb.size))
+		gl.UnmapBuffer(gl.PIXEL_PACK_BUFFER)
+	}
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	flipRows(img.Pix, img.Stride, pb.height)
+	return img
+}
+
+// delete frees pb's buffer and any pending fence.
+func (pb *pixelBuffer) delete() {
+	if pb.fence != 0 {
+		gl.DeleteSync(pb.fence)
+		pb.fence = 0
+	}
+	gl.DeleteBuffers(1, &pb.id)
+}
+
+// Op returns an Op that calls Update with the size of the current viewport. It is intended for use as, or in, a Sim's
+// PostRender op.
+func (r *AsyncReader) Op() gt3.Op {
+	return gt3.OpFn(func(float64, float64, time.Time) {
+		r.Update(viewportSize())
+	})
+}
+
+// complete maps a PBO whose readback has finished and schedules delivery of its image. If wait is true, it blocks
+// until the readback finishes.
+func (r *AsyncReader) complete(pb *pixelBuffer, wait bool) {
+	if !pb.done(wait) {
+		return
+	}
+	img := pb.image()
+
+	for _, fn := range pb.callbacks {
+		fn := fn
+		r.sim.Sched(gt3.OpFn(func(float64, float64, time.Time) { fn(img) }))
+	}
+	pb.callbacks = nil
+}
+
+// Delete frees the AsyncReader's PBOs. Pending captures are discarded.
+func (r *AsyncReader) Delete() {
+	for i := range r.pbos {
+		r.pbos[i].delete()
+	}
+	r.pbos = nil
+}
@@ -0,0 +1,38 @@
+package gfx
+
+import (
+	"image"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ReadPixels reads a region of the current read framebuffer into a new RGBA image. OpenGL rows run bottom to top, so
+// rows are flipped to give the image a top-left origin.
+func ReadPixels(x, y, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width <= 0 || height <= 0 {
+		return img
+	}
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	flipRows(img.Pix, img.Stride, height)
+	return img
+}
+
+func flipRows(pix []byte, stride, height int) {
+	tmp := make([]byte, stride)
+	for top, bottom := 0, height-1; top < bottom; top, bottom = top+1, bottom-1 {
+		t, b := pix[top*stride:(top+1)*stride], pix[bottom*stride:(bottom+1)*stride]
+		copy(tmp, t)
+		copy(t, b)
+		copy(b, tmp)
+	}
+}
+
+// viewportSize returns the size of the current GL viewport.
+func viewportSize() (width, height int) {
+	var vp [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &vp[0])
+	return int(vp[2]), int(vp[3])
+}
@@ -0,0 +1,182 @@
+package gfx
+
+import (
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// Replay keeps a rolling buffer of the last few seconds of rendered frames, downscaled, which can be exported as an
+// animated GIF for sharing clips and bug reproductions.
+type Replay struct {
+	fps      int
+	maxWidth int
+
+	mu     sync.Mutex
+	frames []replayFrame // Ring buffer
+	next   int           // Index of the next frame to overwrite
+	full   bool
+	last   time.Time
+}
+
+type replayFrame struct {
+	img  *image.RGBA
+	when time.Time
+}
+
+// NewReplay returns a Replay holding the last seconds of frames, sampled at up to fps frames per second and
+// downscaled to at most maxWidth pixels wide. GIF frame delays are in hundredths of a second, so fps should be at most
+// 50. An fps less than 1 is treated as 1.
+func NewReplay(seconds float64, fps, maxWidth int) *Replay {
+	if fps < 1 {
+		fps = 1
+	}
+	n := int(seconds * float64(fps))
+	if n < 1 {
+		n = 1
+	}
+	return &Replay{
+		fps:      fps,
+		maxWidth: maxWidth,
+		frames:   make([]replayFrame, n),
+	}
+}
+
+// Capture reads back a frame of the given size from the current read framebuffer and adds it to the buffer, if at
+// least 1/fps seconds have passed since the last captured frame. Capture must be called on the main thread after
+// rendering and before swapping buffers, such as from a PostRender op.
+func (r *Replay) Capture(width, height int, when time.Time) {
+	if !r.last.IsZero() && when.Sub(r.last) < time.Second/time.Duration(r.fps) {
+		return
+	}
+	r.last = when
+
+	img := ReadPixels(0, 0, width, height)
+	if width > r.maxWidth && r.maxWidth > 0 {
+		img = fitImage(img, r.maxWidth, height*r.maxWidth/width)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frames[r.next] = replayFrame{img, when}
+	r.next = (r.next + 1) % len(r.frames)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Op returns an Op that captures frames the size of the current viewport. It is intended for use as, or in, a Sim's
+// PostRender op.
+func (r *Replay) Op() gt3.Op {
+	return gt3.OpFn(func(_, _ float64, when time.Time) {
+		w, h := viewportSize()
+		r.Capture(w, h, when)
+	})
+}
+
+// snapshot returns the buffered frames in order, oldest first.
+func (r *Replay) snapshot() []replayFrame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]replayFrame(nil), r.frames[:r.next]...)
+	}
+	frames := append([]replayFrame(nil), r.frames[r.next:]...)
+	return append(frames, r.frames[:r.next]...)
+}
+
+// WriteGIF encodes the buffered frames to w as an animated GIF. Frames are quantized to a fixed palette with
+// dithering, which is slow; WriteGIF should not be called from the main goroutine.
+func (r *Replay) WriteGIF(w io.Writer) error {
+	return writeGIF(w, r.snapshot())
+}
+
+func writeGIF(w io.Writer, frames []replayFrame) error {
+	anim := &gif.GIF{}
+	for i, f := range frames {
+		pimg := image.NewPaletted(f.img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(pimg, pimg.Rect, f.img, image.Point{})
+
+		delay := 0
+		if i+1 < len(frames) {
+			delay = int(frames[i+1].when.Sub(f.when) / (10 * time.Millisecond))
+		} else if i > 0 {
+			delay = anim.Delay[i-1]
+		}
+		anim.Image = append(anim.Image, pimg)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// Export writes the currently buffered frames to a GIF file at path on a separate goroutine. The frames are
+// snapshotted immediately, so capture can continue while the export runs. If done is not nil, it is called with the
+// result of the export from the export goroutine.
+func (r *Replay) Export(path string, done func(error)) {
+	frames := r.snapshot()
+	go func() {
+		err := exportGIF(path, frames)
+		if done != nil {
+			done(err)
+		}
+	}()
+}
+
+func exportGIF(path string, frames []replayFrame) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeGIF(f, frames); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Hotkey returns an EventHandler that exports the buffer when key is pressed, then passes every event on to next. The
+// path of each export is returned by path, and done, if not nil, receives the result as with Export.
+func (r *Replay) Hotkey(key gt3.Key, path func() string, done func(error), next gt3.EventHandler) gt3.EventHandler {
+	return gt3.EventHandlerFn(func(e gt3.Event, when time.Time) {
+		if ev, ok := e.(gt3.KeyEvent); ok && ev.Key == key && ev.Action == gt3.Press {
+			r.Export(path(), done)
+		}
+		if next != nil {
+			next.Event(e, when)
+		}
+	})
+}
+
+// fitImage scales src to fit a width x height image with nearest-neighbor sampling, letterboxed with black bars.
+func fitImage(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	r, scale := Letterbox(sw, sh, width, height, false)
+	if scale == 0 {
+		return dst
+	}
+
+	for y := 0; y < r.H; y++ {
+		sy := int(float64(y) / scale)
+		if sy >= sh {
+			sy = sh - 1
+		}
+		srow := src.Pix[sy*src.Stride:]
+		drow := dst.Pix[(r.Y+y)*dst.Stride+r.X*4:]
+		for x := 0; x < r.W; x++ {
+			sx := int(float64(x) / scale)
+			if sx >= sw {
+				sx = sw - 1
+			}
+			copy(drow[x*4:x*4+4], srow[sx*4:sx*4+4])
+		}
+	}
+	return dst
+}
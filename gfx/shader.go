@@ -0,0 +1,192 @@
+package gfx
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.spiff.io/gt3/g3m"
+)
+
+// ShaderError is returned when a shader fails to compile or a program fails to link. Its message includes the driver's
+// full info log, with each line that refers to a source line followed by that line of source.
+type ShaderError struct {
+	Stage string // "vertex", "fragment", or "link"
+	File  string // Source file name, if loaded from a file
+	Log   string // The driver's info log
+	Lines []int  // Source line numbers referred to by the log, in order
+	src   string
+}
+
+func (e *ShaderError) Error() string {
+	var b strings.Builder
+	if e.Stage == "link" {
+		b.WriteString("gfx: program link failed")
+	} else {
+		fmt.Fprintf(&b, "gfx: %s shader compile failed", e.Stage)
+	}
+	if e.File != "" {
+		b.WriteString(" (" + e.File + ")")
+	}
+	b.WriteByte(':')
+
+	src := strings.Split(e.src, "\n")
+	for _, line := range strings.Split(strings.TrimSpace(e.Log), "\n") {
+		b.WriteString("\n\t" + line)
+		if n, ok := logLine(line); ok && n >= 1 && n <= len(src) {
+			fmt.Fprintf(&b, "\n\t\t%d: %s", n, strings.TrimSpace(src[n-1]))
+		}
+	}
+	return b.String()
+}
+
+// logLinePattern matches the source line number in info log lines in the formats used by common drivers, such as
+// "0:12(3): error", "ERROR: 0:12: ...", and "0(12) : error".
+var logLinePattern = regexp.MustCompile(`^\s*(?:ERROR:|WARNING:)?\s*\d+[:(](\d+)`)
+
+func logLine(line string) (int, bool) {
+	m := logLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+func newShaderError(stage, file, log, src string) *ShaderError {
+	e := &ShaderError{Stage: stage, File: file, Log: strings.TrimRight(log, "\x00"), src: src}
+	for _, line := range strings.Split(e.Log, "\n") {
+		if n, ok := logLine(line); ok {
+			e.Lines = append(e.Lines, n)
+		}
+	}
+	return e
+}
+
+func compileShader(kind uint32, stage, file, src string) (uint32, error) {
+	shader := gl.CreateShader(kind)
+	csrc, free := gl.Strs(src + "\x00")
+	gl.ShaderSource(shader, 1, csrc, nil)
+	free()
+	gl.CompileShader(shader)
+
+	var status int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+	if status == gl.FALSE {
+		var n int32
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &n)
+		log := strings.Repeat("\x00", int(n)+1)
+		gl.GetShaderInfoLog(shader, n, nil, gl.Str(log))
+		gl.DeleteShader(shader)
+		return 0, newShaderError(stage, file, log, src)
+	}
+	return shader, nil
+}
+
+// Program is a linked shader program with cached uniform and attribute locations.
+type Program struct {
+	ID uint32
+
+	uniforms map[string]int32
+	attribs  map[string]int32
+}
+
+// LoadProgram compiles and links a program from vertex and fragment shader sources. If compiling or linking fails, the
+// error is a *ShaderError. It must be called with a current GL context.
+func LoadProgram(vertexSrc, fragmentSrc string) (*Program, error) {
+	return loadProgram(vertexSrc, fragmentSrc, "", "")
+}
+
+// LoadProgramFiles is the same as LoadProgram, except that shader sources are read from files.
+func LoadProgramFiles(vertexPath, fragmentPath string) (*Program, error) {
+	vs, err := os.ReadFile(vertexPath)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := os.ReadFile(fragmentPath)
+	if err != nil {
+		return nil, err
+	}
+	return loadProgram(string(vs), string(fs), vertexPath, fragmentPath)
+}
+
+func loadProgram(vertexSrc, fragmentSrc, vertexFile, fragmentFile string) (*Program, error) {
+	vs, err := compileShader(gl.VERTEX_SHADER, "vertex", vertexFile, vertexSrc)
+	if err != nil {
+		return nil, err
+	}
+	defer gl.DeleteShader(vs)
+
+	fs, err := compileShader(gl.FRAGMENT_SHADER, "fragment", fragmentFile, fragmentSrc)
+	if err != nil {
+		return nil, err
+	}
+	defer gl.DeleteShader(fs)
+
+	prog := gl.CreateProgram()
+	gl.AttachShader(prog, vs)
+	gl.AttachShader(prog, fs)
+	gl.LinkProgram(prog)
+
+	var status int32
+	gl.GetProgramiv(prog, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var n int32
+		gl.GetProgramiv(prog, gl.INFO_LOG_LENGTH, &n)
+		log := strings.Repeat("\x00", int(n)+1)
+		gl.GetProgramInfoLog(prog, n, nil, gl.Str(log))
+		gl.DeleteProgram(prog)
+		return nil, newShaderError("link", "", log, "")
+	}
+	return &Program{
+		ID:       prog,
+		uniforms: make(map[string]int32),
+		attribs:  make(map[string]int32),
+	}, nil
+}
+
+// Use makes p the current program.
+func (p *Program) Use() {
+	gl.UseProgram(p.ID)
+}
+
+// Uniform returns the location of a uniform, or -1 if the program has no active uniform with that name. Locations are
+// cached after the first lookup.
+func (p *Program) Uniform(name string) int32 {
+	loc, ok := p.uniforms[name]
+	if !ok {
+		loc = gl.GetUniformLocation(p.ID, gl.Str(name+"\x00"))
+		p.uniforms[name] = loc
+	}
+	return loc
+}
+
+// SetMat4 sets the mat4 uniform name to m. The program must be in use.
+func (p *Program) SetMat4(name string, m g3m.Mat4) {
+	gl.UniformMatrix4fv(p.Uniform(name), 1, false, &m[0])
+}
+
+// SetMat3 sets the mat3 uniform name to m. The program must be in use.
+func (p *Program) SetMat3(name string, m g3m.Mat3) {
+	gl.UniformMatrix3fv(p.Uniform(name), 1, false, &m[0])
+}
+
+// Attrib returns the location of a vertex attribute, or -1 if the program has no active attribute with that name.
+// Locations are cached after the first lookup.
+func (p *Program) Attrib(name string) int32 {
+	loc, ok := p.attribs[name]
+	if !ok {
+		loc = gl.GetAttribLocation(p.ID, gl.Str(name+"\x00"))
+		p.attribs[name] = loc
+	}
+	return loc
+}
+
+// Delete deletes the program.
+func (p *Program) Delete() {
+	gl.DeleteProgram(p.ID)
+	p.ID = 0
+}
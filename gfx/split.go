@@ -0,0 +1,105 @@
+// Package gfx provides OpenGL rendering helpers for gt3 programs.
+package gfx
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Rect is a rectangle in framebuffer pixels, with its origin at the bottom-left as in OpenGL.
+type Rect struct {
+	X, Y, W, H int
+}
+
+// SplitLayout divides the framebuffer rectangle fb into n player regions, in player order.
+type SplitLayout func(n int, fb Rect) []Rect
+
+var (
+	// SplitRows stacks player regions from top to bottom, each spanning the full width.
+	SplitRows SplitLayout = splitRows
+	// SplitColumns places player regions from left to right, each spanning the full height.
+	SplitColumns SplitLayout = splitColumns
+	// SplitGrid places player regions in the smallest square-ish grid that fits them, from the top-left, row by row.
+	// If the last row isn't full, its regions are widened to fill it.
+	SplitGrid SplitLayout = splitGrid
+)
+
+func splitRows(n int, fb Rect) []Rect {
+	rects := make([]Rect, n)
+	for i := range rects {
+		top, bottom := fb.H-i*fb.H/n, fb.H-(i+1)*fb.H/n
+		rects[i] = Rect{fb.X, fb.Y + bottom, fb.W, top - bottom}
+	}
+	return rects
+}
+
+func splitColumns(n int, fb Rect) []Rect {
+	rects := make([]Rect, n)
+	for i := range rects {
+		left, right := i*fb.W/n, (i+1)*fb.W/n
+		rects[i] = Rect{fb.X + left, fb.Y, right - left, fb.H}
+	}
+	return rects
+}
+
+func splitGrid(n int, fb Rect) []Rect {
+	if n <= 0 {
+		return nil
+	}
+
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+	rowRects := splitRows(rows, fb)
+
+	rects := make([]Rect, 0, n)
+	for r, row := range rowRects {
+		count := cols
+		if r == rows-1 {
+			count = n - r*cols
+		}
+		rects = append(rects, splitColumns(count, row)...)
+	}
+	return rects
+}
+
+// SplitScreen renders a scene once per local player, each into its own region of the framebuffer. C is the type of
+// the per-player camera passed to the render callback.
+type SplitScreen[C any] struct {
+	// Layout divides the framebuffer into player regions. If nil, SplitGrid is used.
+	Layout SplitLayout
+	// Cameras holds one camera per player. The number of players is len(Cameras).
+	Cameras []C
+}
+
+// Regions returns the player regions for a framebuffer of the given size.
+func (s *SplitScreen[C]) Regions(fbWidth, fbHeight int) []Rect {
+	layout := s.Layout
+	if layout == nil {
+		layout = SplitGrid
+	}
+	return layout(len(s.Cameras), Rect{0, 0, fbWidth, fbHeight})
+}
+
+// Render calls fn once per player with the player's index, region, and camera. Before each call, the GL viewport and
+// scissor box are set to the player's region and scissor testing is enabled. The viewport, scissor box, and scissor
+// test state are restored afterwards. Render must be called with a current GL context.
+func (s *SplitScreen[C]) Render(fbWidth, fbHeight int, fn func(player int, region Rect, camera C)) {
+	var viewport, scissor [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	gl.GetIntegerv(gl.SCISSOR_BOX, &scissor[0])
+	scissorEnabled := gl.IsEnabled(gl.SCISSOR_TEST)
+
+	gl.Enable(gl.SCISSOR_TEST)
+	for i, r := range s.Regions(fbWidth, fbHeight) {
+		gl.Viewport(int32(r.X), int32(r.Y), int32(r.W), int32(r.H))
+		gl.Scissor(int32(r.X), int32(r.Y), int32(r.W), int32(r.H))
+		fn(i, r, s.Cameras[i])
+	}
+
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	gl.Scissor(scissor[0], scissor[1], scissor[2], scissor[3])
+	if !scissorEnabled {
+		gl.Disable(gl.SCISSOR_TEST)
+	}
+}
@@ -0,0 +1,243 @@
+package gfx
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Sprite is a textured quad drawn by a SpriteBatch.
+type Sprite struct {
+	Texture *Texture
+
+	// X and Y are the position of the sprite's origin in screen units.
+	X, Y float64
+	// Width and Height are the sprite's unscaled size. If both are zero, the size of the texture region is used.
+	Width, Height float64
+	// OriginX and OriginY are the point the sprite is positioned, scaled, and rotated about, as a fraction of its size:
+	// 0, 0 is the top-left corner and 0.5, 0.5 is the center.
+	OriginX, OriginY float64
+	// Rotation is the sprite's rotation in radians. Since Y points down, positive rotations are clockwise on screen.
+	Rotation float64
+	// ScaleX and ScaleY scale the sprite. Zero is treated as 1.
+	ScaleX, ScaleY float64
+
+	// U0, V0, U1, and V1 are the texture coordinates of the sprite's top-left and bottom-right corners. If all are
+	// zero, the whole texture is used.
+	U0, V0, U1, V1 float32
+
+	// Color tints the sprite and is non-premultiplied. The zero value is treated as opaque white.
+	Color color.RGBA
+}
+
+// SpriteBatch accumulates sprites and draws them in order with as few draw calls as possible: consecutive sprites
+// sharing a texture are drawn together. Coordinates are in screen units with the origin at the top-left, as with
+// DebugDraw.
+type SpriteBatch struct {
+	prog, vao, vbo, ebo uint32
+	uScreen             int32
+
+	verts   []spriteVertex
+	runs    []spriteRun
+	indexed int // Quads covered by the element buffer
+	calls   int
+}
+
+type spriteVertex struct {
+	x, y, u, v float32
+	color      [4]uint8
+}
+
+const spriteVertexSize = 20
+
+// spriteRun is a range of consecutive quads sharing a texture.
+type spriteRun struct {
+	tex          uint32
+	first, count int
+}
+
+const spriteVertexSrc = `#version 410 core
+uniform vec2 uScreen;
+layout(location = 0) in vec2 aPos;
+layout(location = 1) in vec2 aUV;
+layout(location = 2) in vec4 aColor;
+out vec2 vUV;
+out vec4 vColor;
+void main() {
+	vUV = aUV;
+	vColor = aColor;
+	gl_Position = vec4(aPos.x/uScreen.x*2.0 - 1.0, 1.0 - aPos.y/uScreen.y*2.0, 0.0, 1.0);
+}
+`
+
+const spriteFragmentSrc = `#version 410 core
+uniform sampler2D uTex;
+in vec2 vUV;
+in vec4 vColor;
+out vec4 fragColor;
+void main() {
+	fragColor = vColor * texture(uTex, vUV);
+}
+`
+
+// NewSpriteBatch allocates the GL resources for a SpriteBatch. It must be called with a current GL context.
+func NewSpriteBatch() (*SpriteBatch, error) {
+	prog, err := LoadProgram(spriteVertexSrc, spriteFragmentSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &SpriteBatch{prog: prog.ID}
+	b.uScreen = prog.Uniform("uScreen")
+
+	gl.GenVertexArrays(1, &b.vao)
+	gl.GenBuffers(1, &b.vbo)
+	gl.GenBuffers(1, &b.ebo)
+	gl.BindVertexArray(b.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, b.ebo)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointerWithOffset(0, 2, gl.FLOAT, false, spriteVertexSize, 0)
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, spriteVertexSize, 8)
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointerWithOffset(2, 4, gl.UNSIGNED_BYTE, true, spriteVertexSize, 16)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	return b, nil
+}
+
+// Draw queues a sprite. Sprites with a nil Texture are ignored.
+func (b *SpriteBatch) Draw(s Sprite) {
+	t := s.Texture
+	if t == nil {
+		return
+	}
+
+	u0, v0, u1, v1 := s.U0, s.V0, s.U1, s.V1
+	if u0 == 0 && v0 == 0 && u1 == 0 && v1 == 0 {
+		u1, v1 = 1, 1
+	}
+	w, h := s.Width, s.Height
+	if w == 0 && h == 0 {
+		w = math.Abs(float64(u1-u0)) * float64(t.Width)
+		h = math.Abs(float64(v1-v0)) * float64(t.Height)
+	}
+	if s.ScaleX != 0 {
+		w *= s.ScaleX
+	}
+	if s.ScaleY != 0 {
+		h *= s.ScaleY
+	}
+	c := s.Color
+	if c == (color.RGBA{}) {
+		c = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	}
+	rgba := [4]uint8{c.R, c.G, c.B, c.A}
+
+	// Corners relative to the origin, then rotated and translated.
+	x0, y0 := -s.OriginX*w, -s.OriginY*h
+	x1, y1 := x0+w, y0+h
+	sin, cos := math.Sincos(s.Rotation)
+	corner := func(x, y float64, u, v float32) spriteVertex {
+		return spriteVertex{
+			x:     float32(s.X + x*cos - y*sin),
+			y:     float32(s.Y + x*sin + y*cos),
+			u:     u,
+			v:     v,
+			color: rgba,
+		}
+	}
+	b.verts = append(b.verts,
+		corner(x0, y0, u0, v0),
+		corner(x1, y0, u1, v0),
+		corner(x1, y1, u1, v1),
+		corner(x0, y1, u0, v1),
+	)
+
+	if n := len(b.runs); n > 0 && b.runs[n-1].tex == t.ID {
+		b.runs[n-1].count++
+		return
+	}
+	b.runs = append(b.runs, spriteRun{tex: t.ID, first: len(b.verts)/4 - 1, count: 1})
+}
+
+// Len returns the number of queued sprites.
+func (b *SpriteBatch) Len() int {
+	return len(b.verts) / 4
+}
+
+// DrawCalls returns the number of draw calls made by the last Flush.
+func (b *SpriteBatch) DrawCalls() int {
+	return b.calls
+}
+
+// growIndices extends the element buffer to cover at least quads quads. Must be called with the VAO bound.
+func (b *SpriteBatch) growIndices(quads int) {
+	if quads <= b.indexed {
+		return
+	}
+	n := b.indexed * 2
+	if n < 256 {
+		n = 256
+	}
+	for n < quads {
+		n *= 2
+	}
+	indices := make([]uint32, 0, n*6)
+	for i := uint32(0); i < uint32(n); i++ {
+		q := i * 4
+		indices = append(indices, q, q+1, q+2, q, q+2, q+3)
+	}
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+	b.indexed = n
+}
+
+// Flush draws all queued sprites over the current framebuffer, treating it as width x height screen units, and clears
+// the queue. Blending is enabled with non-premultiplied alpha and depth testing disabled while drawing; the blend state
+// and depth testing are restored afterwards. Texture unit 0 is left unbound.
+func (b *SpriteBatch) Flush(width, height int) {
+	b.calls = 0
+	if len(b.verts) == 0 {
+		return
+	}
+
+	blend, depth := saveBlend(), gl.IsEnabled(gl.DEPTH_TEST)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+
+	gl.UseProgram(b.prog)
+	gl.Uniform2f(b.uScreen, float32(width), float32(height))
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindVertexArray(b.vao)
+	b.growIndices(b.Len())
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(b.verts)*spriteVertexSize, gl.Ptr(b.verts), gl.STREAM_DRAW)
+	for _, run := range b.runs {
+		gl.BindTexture(gl.TEXTURE_2D, run.tex)
+		gl.DrawElementsWithOffset(gl.TRIANGLES, int32(run.count*6), gl.UNSIGNED_INT, uintptr(run.first*6*4))
+		b.calls++
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.UseProgram(0)
+
+	blend.restore()
+	if depth {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+	b.verts = b.verts[:0]
+	b.runs = b.runs[:0]
+}
+
+// Delete frees the SpriteBatch's GL resources. Textures drawn with it are not deleted.
+func (b *SpriteBatch) Delete() {
+	gl.DeleteProgram(b.prog)
+	gl.DeleteVertexArrays(1, &b.vao)
+	gl.DeleteBuffers(1, &b.vbo)
+	gl.DeleteBuffers(1, &b.ebo)
+}
@@ -0,0 +1,49 @@
+package gfx
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.spiff.io/gt3"
+)
+
+// SRGBFramebuffer reports whether the currently bound draw framebuffer's color buffer is sRGB-encoded. For the default
+// framebuffer, this reports whether the driver granted a window's gt3.SRGB request.
+func SRGBFramebuffer() bool {
+	var fbo int32
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &fbo)
+
+	attachment := uint32(gl.COLOR_ATTACHMENT0)
+	if fbo == 0 {
+		var double bool
+		gl.GetBooleanv(gl.DOUBLEBUFFER, &double)
+		attachment = gl.FRONT_LEFT
+		if double {
+			attachment = gl.BACK_LEFT
+		}
+	}
+
+	var encoding int32
+	gl.GetFramebufferAttachmentParameteriv(gl.DRAW_FRAMEBUFFER, attachment, gl.FRAMEBUFFER_ATTACHMENT_COLOR_ENCODING, &encoding)
+	return encoding == gl.SRGB
+}
+
+// SetSRGB enables or disables GL_FRAMEBUFFER_SRGB, returning whether it was previously enabled.
+func SetSRGB(enabled bool) (previous bool) {
+	previous = gl.IsEnabled(gl.FRAMEBUFFER_SRGB)
+	if enabled {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	} else {
+		gl.Disable(gl.FRAMEBUFFER_SRGB)
+	}
+	return previous
+}
+
+// WithSRGB returns an Op that runs op with GL_FRAMEBUFFER_SRGB enabled or disabled, restoring the previous state
+// afterwards. Use it to wrap render passes that write linear color (enabled) or already-encoded color such as UI
+// textures (disabled).
+func WithSRGB(enabled bool, op gt3.Op) gt3.Op {
+	return gt3.ContextOpFn(func(ctx gt3.OpContext) {
+		prev := SetSRGB(enabled)
+		defer SetSRGB(prev)
+		gt3.RunOp(op, ctx)
+	})
+}
@@ -0,0 +1,158 @@
+package gfx
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/jpeg" // Register JPEG decoding for LoadTextureFile
+	_ "image/png"  // Register PNG decoding for LoadTextureFile
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ErrEmptyTexture is returned when loading a texture from an image with no pixels.
+var ErrEmptyTexture = errors.New("gfx: texture image is empty")
+
+// FilterMode is a texture sampling filter.
+type FilterMode int32
+
+// Filter modes.
+const (
+	Linear  FilterMode = gl.LINEAR
+	Nearest FilterMode = gl.NEAREST
+)
+
+// WrapMode is how a texture is sampled outside of [0, 1].
+type WrapMode int32
+
+// Wrap modes.
+const (
+	ClampToEdge    WrapMode = gl.CLAMP_TO_EDGE
+	Repeat         WrapMode = gl.REPEAT
+	MirroredRepeat WrapMode = gl.MIRRORED_REPEAT
+)
+
+type textureConfig struct {
+	filter  FilterMode
+	wrapS   WrapMode
+	wrapT   WrapMode
+	mipmaps bool
+	srgb    bool
+}
+
+// TextureOption configures a texture created by LoadTexture.
+type TextureOption func(*textureConfig)
+
+// Filter sets the texture's minification and magnification filter. The default is Linear. When mipmaps are
+// generated, minification also blends between mipmap levels with the same filter.
+func Filter(f FilterMode) TextureOption {
+	return func(c *textureConfig) { c.filter = f }
+}
+
+// Wrap sets the texture's wrap mode on both axes. The default is ClampToEdge.
+func Wrap(w WrapMode) TextureOption {
+	return func(c *textureConfig) { c.wrapS, c.wrapT = w, w }
+}
+
+// WrapST sets the texture's wrap modes for the S and T axes separately.
+func WrapST(s, t WrapMode) TextureOption {
+	return func(c *textureConfig) { c.wrapS, c.wrapT = s, t }
+}
+
+// Mipmaps generates a full mipmap chain for the texture.
+func Mipmaps() TextureOption {
+	return func(c *textureConfig) { c.mipmaps = true }
+}
+
+// SRGBTexture stores the texture as sRGB, so that samples are converted to linear color. Use it for color images but
+// not for data such as normal maps.
+func SRGBTexture() TextureOption {
+	return func(c *textureConfig) { c.srgb = true }
+}
+
+// Texture is a 2D texture with 8-bit RGBA texels.
+type Texture struct {
+	ID     uint32
+	Width  int
+	Height int
+}
+
+// LoadTexture uploads img to a new texture. Pixels are stored with non-premultiplied alpha, and the image's first row
+// is the texture's first row, at t = 0. It must be called with a current GL context.
+func LoadTexture(img image.Image, opts ...TextureOption) (*Texture, error) {
+	conf := textureConfig{filter: Linear, wrapS: ClampToEdge, wrapT: ClampToEdge}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, ErrEmptyTexture
+	}
+	pix := nrgbaPixels(img)
+
+	internal := int32(gl.RGBA8)
+	if conf.srgb {
+		internal = gl.SRGB8_ALPHA8
+	}
+	minFilter := int32(conf.filter)
+	if conf.mipmaps {
+		minFilter = gl.LINEAR_MIPMAP_LINEAR
+		if conf.filter == Nearest {
+			minFilter = gl.NEAREST_MIPMAP_NEAREST
+		}
+	}
+
+	t := &Texture{Width: b.Dx(), Height: b.Dy()}
+	gl.GenTextures(1, &t.ID)
+	gl.BindTexture(gl.TEXTURE_2D, t.ID)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internal, int32(t.Width), int32(t.Height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(conf.filter))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, int32(conf.wrapS))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, int32(conf.wrapT))
+	if conf.mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return t, nil
+}
+
+// LoadTextureFile decodes a PNG or JPEG image from path and uploads it as by LoadTexture.
+func LoadTextureFile(path string, opts ...TextureOption) (*Texture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return LoadTexture(img, opts...)
+}
+
+// nrgbaPixels returns img's pixels as tightly packed, non-premultiplied RGBA, converting only if necessary.
+func nrgbaPixels(img image.Image) []byte {
+	b := img.Bounds()
+	if m, ok := img.(*image.NRGBA); ok && m.Stride == 4*b.Dx() {
+		return m.Pix[:4*b.Dx()*b.Dy()]
+	}
+	m := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(m, m.Rect, img, b.Min, draw.Src)
+	return m.Pix
+}
+
+// Bind binds the texture to the given texture unit, counting from zero.
+func (t *Texture) Bind(unit int) {
+	gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+	gl.BindTexture(gl.TEXTURE_2D, t.ID)
+}
+
+// Delete deletes the texture.
+func (t *Texture) Delete() {
+	gl.DeleteTextures(1, &t.ID)
+	t.ID = 0
+}
@@ -0,0 +1,210 @@
+package gfx
+
+import (
+	"image"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// Recorder streams rendered frames to an ffmpeg subprocess, which encodes them to a video file. The output format is
+// chosen by ffmpeg from the file extension (e.g., .mp4 or .webm).
+//
+// Frames are read back asynchronously through a ring of pixel buffer objects, as by FrameDumper, and written to ffmpeg
+// on a separate goroutine, so neither readback nor encoding stalls rendering. If every buffer is still in flight or
+// ffmpeg has fallen behind, frames are dropped rather than waited on; Dropped reports how many.
+//
+// A video's resolution is fixed, so when the size of captured frames changes, such as when the window is resized, the
+// Recorder finishes the current video and starts a new segment at the new size. Segments after the first are numbered
+// before the file extension: recording to clip.mp4 writes clip.mp4, then clip-1.mp4, and so on.
+type Recorder struct {
+	path string
+	fps  int
+	args []string
+
+	ring    pixelRing
+	frames  chan *image.RGBA
+	dropped uint64
+	wg      sync.WaitGroup
+
+	// The current segment. Owned by the encoding goroutine once NewRecorder returns.
+	width, height int
+	cmd           *exec.Cmd
+	stdin         io.WriteCloser
+
+	mu       sync.Mutex
+	segments []string
+	err      error
+}
+
+// recordDepth is the number of frames a Recorder may have in flight on the GPU.
+const recordDepth = 3
+
+// FFmpegPath is the ffmpeg executable used by Recorders.
+var FFmpegPath = "ffmpeg"
+
+// NewRecorder starts ffmpeg to encode a width x height video at fps frames per second to path, overwriting any
+// existing file. Additional ffmpeg output arguments, such as codec options, may be given in args, and are used for
+// every segment.
+func NewRecorder(path string, width, height, fps int, args ...string) (*Recorder, error) {
+	r := &Recorder{
+		path:   path,
+		fps:    fps,
+		args:   args,
+		frames: make(chan *image.RGBA, 4),
+	}
+	if err := r.start(width, height); err != nil {
+		return nil, err
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// start starts ffmpeg encoding a new width x height segment.
+func (r *Recorder) start(width, height int) error {
+	path := r.path
+	if n := len(r.Segments()); n > 0 {
+		ext := filepath.Ext(path)
+		path = strings.TrimSuffix(path, ext) + "-" + strconv.Itoa(n) + ext
+	}
+
+	argv := []string{
+		"-y", "-loglevel", "error",
+		"-f", "rawvideo", "-pix_fmt", "rgba",
+		"-s", strconv.Itoa(width) + "x" + strconv.Itoa(height),
+		"-r", strconv.Itoa(r.fps),
+		"-i", "-",
+		"-pix_fmt", "yuv420p",
+	}
+	argv = append(argv, r.args...)
+	argv = append(argv, path)
+
+	cmd := exec.Command(FFmpegPath, argv...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	r.width, r.height = width, height
+	r.cmd, r.stdin = cmd, stdin
+	r.mu.Lock()
+	r.segments = append(r.segments, path)
+	r.mu.Unlock()
+	return nil
+}
+
+// end closes ffmpeg's input and waits for it to finish the current segment.
+func (r *Recorder) end() {
+	if r.cmd == nil {
+		return
+	}
+	if err := r.stdin.Close(); err != nil {
+		r.setErr(err)
+	}
+	if err := r.cmd.Wait(); err != nil {
+		r.setErr(err)
+	}
+	r.cmd, r.stdin = nil, nil
+}
+
+// Capture queues earlier frames whose readbacks have finished to be encoded and starts a readback of a frame of the
+// given size from the current read framebuffer. Empty frames, such as while the window is iconified, are skipped.
+// Capture must be called on the main thread after rendering and before swapping buffers, such as from a PostRender op.
+func (r *Recorder) Capture(width, height int) {
+	r.ring.complete(false, r.queue)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if r.ring.read(width, height, recordDepth) == nil {
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+// queue queues pb's finished frame to be encoded, dropping it if ffmpeg is behind.
+func (r *Recorder) queue(pb *pixelBuffer) {
+	select {
+	case r.frames <- pb.image():
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+// Dropped returns the number of frames dropped because every readback buffer was in flight or ffmpeg was behind.
+func (r *Recorder) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
+
+// Segments returns the paths of the videos written so far, starting with the path passed to NewRecorder. It may be
+// called from any goroutine.
+func (r *Recorder) Segments() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.segments...)
+}
+
+// Op returns an Op that captures a frame the size of the current viewport. It is intended for use as, or in, a Sim's
+// PostRender op, so that frames are recorded at the render cadence.
+func (r *Recorder) Op() gt3.Op {
+	return gt3.OpFn(func(float64, float64, time.Time) {
+		r.Capture(viewportSize())
+	})
+}
+
+func (r *Recorder) run() {
+	defer r.wg.Done()
+	for img := range r.frames {
+		if r.failed() {
+			continue
+		}
+		if w, h := img.Rect.Dx(), img.Rect.Dy(); w != r.width || h != r.height {
+			r.end()
+			if err := r.start(w, h); err != nil {
+				r.setErr(err)
+				continue
+			}
+		}
+		if _, err := r.stdin.Write(img.Pix); err != nil {
+			r.setErr(err)
+		}
+	}
+}
+
+func (r *Recorder) failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err != nil
+}
+
+func (r *Recorder) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Close finishes frames still being read back, waits for queued frames to be encoded and ffmpeg to exit, and frees the
+// Recorder's buffers. It returns the first error encountered writing frames or running ffmpeg. Close must be called on
+// the main thread with the same GL context current as for Capture. Capture must not be called after Close.
+func (r *Recorder) Close() error {
+	r.ring.complete(true, func(pb *pixelBuffer) { r.frames <- pb.image() })
+	r.ring.delete()
+	close(r.frames)
+	r.wg.Wait()
+	r.end()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
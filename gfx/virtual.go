@@ -0,0 +1,149 @@
+package gfx
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.spiff.io/gt3"
+)
+
+// Letterbox returns the largest rectangle with the aspect ratio of a logical screen that fits centered within a target
+// of the given size, along with the scale from logical to target units. If integer is true and the target is at least
+// as large as the logical screen, the scale is rounded down to a whole number for pixel-perfect output.
+func Letterbox(logicalW, logicalH, targetW, targetH int, integer bool) (r Rect, scale float64) {
+	if logicalW <= 0 || logicalH <= 0 {
+		return Rect{}, 0
+	}
+
+	scale = math.Min(float64(targetW)/float64(logicalW), float64(targetH)/float64(logicalH))
+	if integer && scale >= 1 {
+		scale = math.Floor(scale)
+	}
+
+	w, h := int(float64(logicalW)*scale), int(float64(logicalH)*scale)
+	return Rect{(targetW - w) / 2, (targetH - h) / 2, w, h}, scale
+}
+
+// ErrIncompleteFramebuffer is returned when a framebuffer object could not be completed by the driver.
+var ErrIncompleteFramebuffer = errors.New("gfx: framebuffer incomplete")
+
+// VirtualScreen is an offscreen render target with a fixed logical resolution that is scaled to fit the window, with
+// letterbox or pillarbox bars filling the remaining space.
+type VirtualScreen struct {
+	Width, Height int
+
+	// Filter is the GL filter used when scaling to the window. It defaults to gl.NEAREST for crisp pixel art.
+	Filter int32
+	// IntegerScale restricts scaling to whole multiples of the logical resolution.
+	IntegerScale bool
+	// BarColor is the color the bars are cleared to.
+	BarColor [4]float32
+
+	fbo, color, depth uint32
+
+	winW, winH int // Window size in screen coordinates, for cursor mapping
+}
+
+// NewVirtualScreen allocates a virtual screen of the given logical size with color and depth/stencil attachments. It
+// must be called with a current GL context.
+func NewVirtualScreen(width, height int) (*VirtualScreen, error) {
+	v := &VirtualScreen{Width: width, Height: height, Filter: gl.NEAREST}
+
+	gl.GenTextures(1, &v.color)
+	gl.BindTexture(gl.TEXTURE_2D, v.color)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	gl.GenRenderbuffers(1, &v.depth)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, v.depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(width), int32(height))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.GenFramebuffers(1, &v.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, v.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, v.color, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, v.depth)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		v.Delete()
+		return nil, ErrIncompleteFramebuffer
+	}
+	return v, nil
+}
+
+// Texture returns the GL texture holding the virtual screen's color buffer.
+func (v *VirtualScreen) Texture() uint32 { return v.color }
+
+// Begin binds the virtual screen as the draw framebuffer and sets the viewport to its logical resolution. Render ops
+// draw between Begin and End as though the window were the logical size.
+func (v *VirtualScreen) Begin() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, v.fbo)
+	gl.Viewport(0, 0, int32(v.Width), int32(v.Height))
+}
+
+// End binds the default framebuffer, clears it to BarColor, and scales the virtual screen into the letterboxed region
+// of a framebuffer of the given size.
+func (v *VirtualScreen) End(fbWidth, fbHeight int) {
+	r, _ := Letterbox(v.Width, v.Height, fbWidth, fbHeight, v.IntegerScale)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
+	gl.ClearColor(v.BarColor[0], v.BarColor[1], v.BarColor[2], v.BarColor[3])
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, v.fbo)
+	gl.BlitFramebuffer(
+		0, 0, int32(v.Width), int32(v.Height),
+		int32(r.X), int32(r.Y), int32(r.X+r.W), int32(r.Y+r.H),
+		gl.COLOR_BUFFER_BIT, uint32(v.Filter),
+	)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, 0)
+}
+
+// Delete frees the virtual screen's GL resources.
+func (v *VirtualScreen) Delete() {
+	gl.DeleteFramebuffers(1, &v.fbo)
+	gl.DeleteRenderbuffers(1, &v.depth)
+	gl.DeleteTextures(1, &v.color)
+	v.fbo, v.depth, v.color = 0, 0, 0
+}
+
+// SetWindowSize sets the window size, in screen coordinates, used to map cursor positions. It is updated
+// automatically by ResizeEvents passing through the handler returned by Events.
+func (v *VirtualScreen) SetWindowSize(width, height int) {
+	v.winW, v.winH = width, height
+}
+
+// ToLogical maps a cursor position in window screen coordinates to logical coordinates. Inside reports whether the
+// position falls within the virtual screen rather than on a bar.
+func (v *VirtualScreen) ToLogical(x, y float64) (lx, ly float64, inside bool) {
+	r, scale := Letterbox(v.Width, v.Height, v.winW, v.winH, v.IntegerScale)
+	if scale == 0 {
+		return x, y, false
+	}
+
+	lx, ly = (x-float64(r.X))/scale, (y-float64(r.Y))/scale
+	inside = lx >= 0 && ly >= 0 && lx < float64(v.Width) && ly < float64(v.Height)
+	return lx, ly, inside
+}
+
+// Events returns an EventHandler that maps CursorPosEvents to logical coordinates before passing them to next. All
+// other events are passed through unchanged.
+func (v *VirtualScreen) Events(next gt3.EventHandler) gt3.EventHandler {
+	return gt3.EventHandlerFn(func(e gt3.Event, when time.Time) {
+		switch ev := e.(type) {
+		case gt3.ResizeEvent:
+			v.SetWindowSize(ev.Width, ev.Height)
+		case gt3.CursorPosEvent:
+			ev.X, ev.Y, _ = v.ToLogical(ev.X, ev.Y)
+			e = ev
+		}
+		next.Event(e, when)
+	})
+}
@@ -0,0 +1,487 @@
+//go:build !android && !ios
+
+package gt3
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// GLFW backend
+
+var (
+	glfwWindowsMu sync.Mutex
+	glfwWindows   = map[*glfw.Window]*Window{}
+)
+
+// GLFWWindow returns the Window for a GLFW window. The same *Window is returned for a given *glfw.Window until it is
+// released with ReleaseGLFWWindow. GLFWWindow returns nil if w is nil.
+func GLFWWindow(w *glfw.Window) *Window {
+	if w == nil {
+		return nil
+	}
+
+	glfwWindowsMu.Lock()
+	defer glfwWindowsMu.Unlock()
+	if wnd, ok := glfwWindows[w]; ok {
+		return wnd
+	}
+	wnd := WrapWindow(w)
+	glfwWindows[w] = wnd
+	return wnd
+}
+
+// ReleaseGLFWWindow releases the Window associated with w and drops its window data. It should be called when w is
+// destroyed if w is not destroyed through Window.Destroy.
+func ReleaseGLFWWindow(w *glfw.Window) {
+	glfwWindowsMu.Lock()
+	wnd := glfwWindows[w]
+	delete(glfwWindows, w)
+	glfwWindowsMu.Unlock()
+	if wnd != nil {
+		wnd.clearData()
+	}
+}
+
+// GLFW returns the GLFW window wrapped by w, or nil if w is not a GLFW window.
+func (w *Window) GLFW() *glfw.Window {
+	if w == nil {
+		return nil
+	}
+	gw, _ := w.native.(*glfw.Window)
+	return gw
+}
+
+// releaseNative releases w's GLFW window, if it has one, as by ReleaseGLFWWindow.
+func releaseNative(w *Window) {
+	if gw := w.GLFW(); gw != nil {
+		ReleaseGLFWWindow(gw)
+	}
+}
+
+// nativeState returns the state of w's GLFW window, if it has one.
+func nativeState(w *Window) (WindowState, bool) {
+	if gw := w.GLFW(); gw != nil {
+		return glfwWindowState(gw), true
+	}
+	return WindowState{}, false
+}
+
+// glfwClock is the default Clock, reading GLFW's timer and the system clock.
+type glfwClock struct{}
+
+func (glfwClock) Now() float64    { return glfw.GetTime() }
+func (glfwClock) Wall() time.Time { return time.Now() }
+
+func defaultClock() Clock { return glfwClock{} }
+
+// resetClock restarts GLFW's timer at zero when a Sim starts, if c is the GLFW clock.
+func resetClock(c Clock) {
+	if _, ok := c.(glfwClock); ok {
+		glfw.SetTime(0)
+	}
+}
+
+// GLFW returns the GLFW key for k.
+func (k Key) GLFW() glfw.Key { return glfw.Key(k) }
+
+// GLFW returns the GLFW modifier keys for m.
+func (m ModifierKey) GLFW() glfw.ModifierKey { return glfw.ModifierKey(m) }
+
+// GLFW returns the GLFW mouse button for b.
+func (b MouseButton) GLFW() glfw.MouseButton { return glfw.MouseButton(b) }
+
+// GLFW returns the GLFW action for a.
+func (a Action) GLFW() glfw.Action { return glfw.Action(a) }
+
+// SetEventCallbacks sets w's GLFW callbacks for the given event types to post events to handler, as by SetEventMask
+// with the mask returned by EventMaskOf.
+func SetEventCallbacks(w *glfw.Window, handler EventHandler, eventTypes ...Event) {
+	SetEventMask(w, handler, EventMaskOf(eventTypes...))
+}
+
+// SetEventMask sets w's GLFW callbacks for the event types in mask to post events to handler. Setting callbacks
+// replaces any previously set for the same event types; to deliver a window's events to several handlers, pass a
+// Dispatcher and Subscribe handlers to it.
+//
+// If mask includes EventRawMotion, cursor motion is also posted as RawMotionEvents with Device 0, and raw mouse motion
+// is enabled for w if the platform supports it, in which case motion is unaccelerated while the cursor is disabled.
+func SetEventMask(w *glfw.Window, handler EventHandler, mask EventMask) {
+	debugAssertMainThread()
+	s := &eventProvider{events: handler}
+	if mask&EventRefresh != 0 {
+		w.SetRefreshCallback(s.postRefreshEvent)
+	}
+	if mask&EventCharMods != 0 {
+		w.SetCharModsCallback(s.postCharModsEvent)
+	}
+	if mask&EventCursorEnter != 0 {
+		w.SetCursorEnterCallback(s.postCursorEnterEvent)
+	}
+	if mask&EventDrop != 0 {
+		w.SetDropCallback(s.postDropEvent)
+	}
+	if mask&EventFramebufferSize != 0 {
+		w.SetFramebufferSizeCallback(s.postFramebufferSizeEvent)
+	}
+	if mask&EventIconify != 0 {
+		w.SetIconifyCallback(s.postIconifyEvent)
+	}
+	if mask&EventKey != 0 {
+		w.SetKeyCallback(s.postKeyEvent)
+	}
+	if mask&EventMouse != 0 {
+		w.SetMouseButtonCallback(s.postMouseEvent)
+	}
+	if mask&EventChar != 0 {
+		w.SetCharCallback(s.postCharEvent)
+	}
+	if mask&EventClose != 0 {
+		w.SetCloseCallback(s.postCloseEvent)
+	}
+	if mask&EventFocus != 0 {
+		w.SetFocusCallback(s.postFocusEvent)
+	}
+	if mask&EventPosition != 0 {
+		w.SetPosCallback(s.postPositionEvent)
+	}
+	if mask&EventResize != 0 {
+		w.SetSizeCallback(s.postResizeEvent)
+	}
+	if mask&EventScroll != 0 {
+		w.SetScrollCallback(s.postScrollEvent)
+	}
+	if mask&EventMaximize != 0 {
+		w.SetMaximizeCallback(s.postMaximizeEvent)
+	}
+	if mask&EventContentScale != 0 {
+		w.SetContentScaleCallback(s.postContentScaleEvent)
+	}
+
+	if cursor, raw := mask&EventCursorPos != 0, mask&EventRawMotion != 0; cursor || raw {
+		s.cursor, s.raw = cursor, raw
+		if raw && glfw.RawMouseMotionSupported() {
+			w.SetInputMode(glfw.RawMouseMotion, glfw.True)
+		}
+		w.SetCursorPosCallback(s.postCursorPosEvent)
+	}
+}
+
+// ClearEventCallbacks removes all of w's GLFW callbacks.
+func ClearEventCallbacks(w *glfw.Window) {
+	w.SetRefreshCallback(nil)
+	w.SetCharModsCallback(nil)
+	w.SetCursorEnterCallback(nil)
+	w.SetCursorPosCallback(nil)
+	w.SetDropCallback(nil)
+	w.SetFramebufferSizeCallback(nil)
+	w.SetIconifyCallback(nil)
+	w.SetKeyCallback(nil)
+	w.SetMouseButtonCallback(nil)
+	w.SetCharCallback(nil)
+	w.SetCloseCallback(nil)
+	w.SetFocusCallback(nil)
+	w.SetPosCallback(nil)
+	w.SetSizeCallback(nil)
+	w.SetScrollCallback(nil)
+	w.SetMaximizeCallback(nil)
+	w.SetContentScaleCallback(nil)
+}
+
+// Event provider (hook)
+
+type eventProvider struct {
+	events EventHandler
+
+	// Cursor motion
+	cursor, raw bool // Post CursorPosEvents and RawMotionEvents, respectively
+	moved       bool // Set once the first cursor position is seen
+	x, y        float64
+}
+
+func (p *eventProvider) event(e Event) {
+	if e != nil {
+		p.events.Event(e, time.Now())
+	}
+}
+
+func (p *eventProvider) postRefreshEvent(Window *glfw.Window) {
+	p.event(RefreshEvent{GLFWWindow(Window)})
+}
+
+func (p *eventProvider) postCharModsEvent(Window *glfw.Window, Char rune, Mods glfw.ModifierKey) {
+	p.event(CharModsEvent{GLFWWindow(Window), Char, ModifierKey(Mods)})
+}
+
+func (p *eventProvider) postCursorEnterEvent(Window *glfw.Window, Entered bool) {
+	p.event(CursorEnterEvent{GLFWWindow(Window), Entered})
+}
+
+func (p *eventProvider) postCursorPosEvent(Window *glfw.Window, X float64, Y float64) {
+	if p.raw && p.moved {
+		p.event(RawMotionEvent{0, X - p.x, Y - p.y})
+	}
+	p.x, p.y, p.moved = X, Y, true
+	if p.cursor {
+		p.event(CursorPosEvent{GLFWWindow(Window), X, Y})
+	}
+}
+
+func (p *eventProvider) postDropEvent(Window *glfw.Window, Names []string) {
+	p.event(DropEvent{GLFWWindow(Window), Names})
+}
+
+func (p *eventProvider) postFramebufferSizeEvent(Window *glfw.Window, Width int, Height int) {
+	p.event(FramebufferSizeEvent{GLFWWindow(Window), Width, Height})
+}
+
+func (p *eventProvider) postIconifyEvent(Window *glfw.Window, Iconified bool) {
+	p.event(IconifyEvent{GLFWWindow(Window), Iconified})
+}
+
+func (p *eventProvider) postKeyEvent(window *glfw.Window, key glfw.Key, code int, action glfw.Action, mods glfw.ModifierKey) {
+	p.event(KeyEvent{GLFWWindow(window), Key(key), code, Action(action), ModifierKey(mods)})
+}
+
+func (p *eventProvider) postMouseEvent(window *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	p.event(MouseEvent{GLFWWindow(window), MouseButton(button), Action(action), ModifierKey(mods)})
+}
+
+func (p *eventProvider) postCharEvent(Window *glfw.Window, Char rune) {
+	p.event(CharEvent{GLFWWindow(Window), Char})
+}
+
+func (p *eventProvider) postCloseEvent(Window *glfw.Window) {
+	p.event(CloseEvent{GLFWWindow(Window)})
+}
+
+func (p *eventProvider) postFocusEvent(Window *glfw.Window, Focused bool) {
+	p.event(FocusEvent{GLFWWindow(Window), Focused})
+}
+
+func (p *eventProvider) postPositionEvent(Window *glfw.Window, X int, Y int) {
+	p.event(PositionEvent{GLFWWindow(Window), X, Y})
+}
+
+func (p *eventProvider) postResizeEvent(Window *glfw.Window, Width int, Height int) {
+	p.event(ResizeEvent{GLFWWindow(Window), Width, Height})
+}
+
+func (p *eventProvider) postScrollEvent(Window *glfw.Window, XOff float64, YOff float64) {
+	p.event(ScrollEvent{GLFWWindow(Window), XOff, YOff})
+}
+
+func (p *eventProvider) postMaximizeEvent(Window *glfw.Window, Maximized bool) {
+	p.event(MaximizeEvent{GLFWWindow(Window), Maximized})
+}
+
+func (p *eventProvider) postContentScaleEvent(Window *glfw.Window, X float32, Y float32) {
+	p.event(ContentScaleEvent{GLFWWindow(Window), float64(X), float64(Y)})
+}
+
+// SetJoystickCallback posts JoystickConnectedEvents and JoystickDisconnectedEvents to handler, tracking devices in reg.
+// A JoystickConnectedEvent is posted immediately for each joystick already present. Devices are matched across
+// reconnection by GUID. Since GLFW has a single joystick callback, SetJoystickCallback replaces any previous one; a nil
+// handler removes it. It must be called from the main goroutine.
+func SetJoystickCallback(reg *DeviceRegistry, handler EventHandler) {
+	if handler == nil {
+		glfw.SetJoystickCallback(nil)
+		return
+	}
+
+	p := &eventProvider{events: handler}
+	glfw.SetJoystickCallback(func(joy glfw.Joystick, event glfw.PeripheralEvent) {
+		switch event {
+		case glfw.Connected:
+			p.event(JoystickConnectedEvent{reg.Connect(int(joy), joy.GetName(), joy.GetGUID())})
+		case glfw.Disconnected:
+			if dev, ok := reg.Disconnect(int(joy)); ok {
+				p.event(JoystickDisconnectedEvent{dev})
+			}
+		}
+	})
+
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if joy.Present() {
+			p.event(JoystickConnectedEvent{reg.Connect(int(joy), joy.GetName(), joy.GetGUID())})
+		}
+	}
+}
+
+// DefaultAxisThreshold is the default amount a gamepad axis must move before a GamepadPoller posts a GamepadAxisEvent.
+const DefaultAxisThreshold = 1.0 / 128
+
+// GamepadPoller polls GLFW joysticks and posts GamepadButtonEvents and GamepadAxisEvents for changes in their state, so
+// that gamepads flow through the same EventHandlers as other input. Devices are identified by their IDs in a
+// DeviceRegistry, normally the one passed to SetJoystickCallback. GamepadPoller is an op that polls when run; it should
+// be run from the PreFrame op after glfw.PollEvents. Joysticks with a GLFW gamepad mapping report buttons and axes in
+// the standard gamepad layout; other joysticks report them in their native order. It must only be used from the main
+// goroutine.
+type GamepadPoller struct {
+	// Threshold is the amount an axis must move from its last posted value to post a new GamepadAxisEvent.
+	Threshold float64
+
+	reg     *DeviceRegistry
+	handler EventHandler
+	pads    [glfw.JoystickLast + 1]gamepadState
+}
+
+type gamepadState struct {
+	id      DeviceID
+	buttons []glfw.Action
+	axes    []float64
+}
+
+// NewGamepadPoller returns a GamepadPoller posting events to handler for devices in reg.
+func NewGamepadPoller(reg *DeviceRegistry, handler EventHandler) *GamepadPoller {
+	return &GamepadPoller{
+		Threshold: DefaultAxisThreshold,
+		reg:       reg,
+		handler:   handler,
+	}
+}
+
+func (p *GamepadPoller) Do(float64, float64, time.Time) {
+	p.Poll()
+}
+
+// Poll reads the state of every connected joystick and posts events for buttons and axes that changed since the last
+// poll. Buttons held when a device is first seen are posted as presses. When a device disconnects, its held buttons
+// are posted as releases.
+func (p *GamepadPoller) Poll() {
+	now := time.Now()
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		pad := &p.pads[joy]
+		dev, ok := p.reg.Slot(int(joy))
+		if !ok || !joy.Present() {
+			p.release(pad, now)
+			continue
+		}
+		if pad.id != dev.ID {
+			p.release(pad, now)
+			pad.id = dev.ID
+		}
+
+		var (
+			buttons []glfw.Action
+			axes    []float32
+		)
+		if state := joy.GetGamepadState(); joy.IsGamepad() && state != nil {
+			buttons, axes = state.Buttons[:], state.Axes[:]
+		} else {
+			buttons, axes = joy.GetButtons(), joy.GetAxes()
+		}
+
+		for i, b := range buttons {
+			last := glfw.Release
+			if i < len(pad.buttons) {
+				last = pad.buttons[i]
+			}
+			if b == last {
+				continue
+			}
+			action := Release
+			if b == glfw.Press {
+				action = Press
+			}
+			p.handler.Event(GamepadButtonEvent{pad.id, i, action}, now)
+		}
+		pad.buttons = append(pad.buttons[:0], buttons...)
+
+		for len(pad.axes) < len(axes) {
+			pad.axes = append(pad.axes, 0)
+		}
+		for i, a := range axes {
+			v := float64(a)
+			// Small moves are always posted on reaching rest or either extreme, so handlers see exact end values.
+			last := pad.axes[i]
+			if v == last || (math.Abs(v-last) < p.Threshold && v != 0 && math.Abs(v) != 1) {
+				continue
+			}
+			pad.axes[i] = v
+			p.handler.Event(GamepadAxisEvent{pad.id, i, v}, now)
+		}
+	}
+}
+
+// release posts releases for a pad's held buttons and forgets its state.
+func (p *GamepadPoller) release(pad *gamepadState, now time.Time) {
+	if pad.id == 0 {
+		return
+	}
+	for i, b := range pad.buttons {
+		if b == glfw.Press {
+			p.handler.Event(GamepadButtonEvent{pad.id, i, Release}, now)
+		}
+	}
+	*pad = gamepadState{buttons: pad.buttons[:0], axes: pad.axes[:0]}
+}
+
+// LayoutName returns the name of a printable key as labeled on the user's current keyboard layout, such as "z" for KeyY
+// on a German layout. It returns "" for non-printable keys. It must be called from the main goroutine.
+func (k Key) LayoutName() string {
+	return glfw.GetKeyName(k.GLFW(), 0)
+}
+
+// Paste reads the clipboard and posts its text to handler. If chars is false, the text is posted as a single
+// PasteEvent; otherwise, it's posted as a CharEvent per rune, so that handlers only accepting typed text handle pastes
+// too. Nothing is posted if the clipboard is empty or doesn't hold text. Paste must be called from the main goroutine.
+func Paste(w *Window, handler EventHandler, chars bool) {
+	gw := w.GLFW()
+	if gw == nil {
+		return
+	}
+	text := gw.GetClipboardString()
+	if text == "" {
+		return
+	}
+
+	p := &eventProvider{events: handler}
+	if !chars {
+		p.event(PasteEvent{w, text})
+		return
+	}
+	for _, r := range text {
+		p.event(CharEvent{w, r})
+	}
+}
+
+// PasteShortcuts returns an EventHandler that pastes, as with Paste, when the platform's paste shortcut is pressed:
+// Ctrl+V, Shift+Insert, or, on macOS, Super+V. Shortcut key events are consumed; all other events are passed to next.
+// The returned handler must receive events on the main goroutine.
+func PasteShortcuts(next EventHandler, chars bool) EventHandler {
+	paste := ModControl
+	if runtime.GOOS == "darwin" {
+		paste = ModSuper
+	}
+
+	return EventHandlerFn(func(e Event, when time.Time) {
+		if ev, ok := e.(KeyEvent); ok && ev.Action != Release {
+			if (ev.Key == KeyV && ev.Mods == paste) || (ev.Key == KeyInsert && ev.Mods == ModShift) {
+				Paste(ev.Window, next, chars)
+				return
+			}
+		}
+		next.Event(e, when)
+	})
+}
+
+func glfwWindowState(w *glfw.Window) WindowState {
+	var s WindowState
+	s.Width, s.Height = w.GetSize()
+	s.FramebufferWidth, s.FramebufferHeight = w.GetFramebufferSize()
+	s.X, s.Y = w.GetPos()
+	s.Focused = w.GetAttrib(glfw.Focused) != 0
+	s.Iconified = w.GetAttrib(glfw.Iconified) != 0
+	s.Maximized = w.GetAttrib(glfw.Maximized) != 0
+	sx, _ := w.GetContentScale()
+	s.ContentScale = float64(sx)
+	if m := w.GetMonitor(); m != nil {
+		s.Monitor = m.GetName()
+	}
+	return s
+}
@@ -0,0 +1,159 @@
+package gt3
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// OpGraph is an op that runs a set of ops in parallel on the Sim's worker pool, ordered by the resources each op
+// declares it reads and writes. An op runs after every op added before it that writes a resource it reads or writes,
+// or that reads a resource it writes. Independent ops run concurrently. OpGraph.DoContext returns once every op has
+// run, so an OpGraph used as the Frame op finishes before rendering.
+//
+// Ops in a graph may run off the main goroutine, so they must not call GL or GLFW, and their OpContext's Arena is nil.
+// If an op panics, the panic is re-raised on the calling goroutine as a *PanicError once all running ops finish.
+type OpGraph struct {
+	nodes []graphNode
+	built bool
+}
+
+type graphNode struct {
+	name          string
+	reads, writes []string
+	op            Op
+	deps          int   // Number of ops this op waits on
+	next          []int // Ops waiting on this op
+}
+
+type graphResult struct {
+	node int
+	err  *PanicError // Recovered panic
+}
+
+// Add adds an op to the graph with the names of the resources it reads and writes. Add must not be called while the
+// graph is running.
+func (g *OpGraph) Add(name string, reads, writes []string, op Op) {
+	g.nodes = append(g.nodes, graphNode{name: name, reads: reads, writes: writes, op: op})
+	g.built = false
+}
+
+func overlaps(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// build computes dependencies between ops.
+func (g *OpGraph) build() {
+	for j := range g.nodes {
+		g.nodes[j].deps, g.nodes[j].next = 0, nil
+	}
+	for j := range g.nodes {
+		nj := &g.nodes[j]
+		for i := 0; i < j; i++ {
+			ni := &g.nodes[i]
+			if overlaps(nj.writes, ni.reads) || overlaps(nj.writes, ni.writes) || overlaps(nj.reads, ni.writes) {
+				nj.deps++
+				ni.next = append(ni.next, j)
+			}
+		}
+	}
+	g.built = true
+}
+
+func (g *OpGraph) runNode(node int, ctx OpContext) (res graphResult) {
+	res.node = node
+	defer func() {
+		if v := recover(); v != nil {
+			res.err = &PanicError{Op: fmt.Sprintf("op %q", g.nodes[node].name), Value: v, Stack: debug.Stack()}
+		}
+	}()
+	RunOp(g.nodes[node].op, ctx)
+	return res
+}
+
+func (g *OpGraph) Do(step, frameTime float64, when time.Time) {
+	g.DoContext(OpContext{Step: step, FrameTime: frameTime, When: when})
+}
+
+func (g *OpGraph) DoContext(ctx OpContext) {
+	if len(g.nodes) == 0 {
+		return
+	}
+	if !g.built {
+		g.build()
+	}
+
+	pool := ctx.pool()
+	ctx.Arena = nil
+	var (
+		waiting = make([]int, len(g.nodes))
+		done    = make(chan graphResult, len(g.nodes))
+		ready   []int
+		running int
+		failed  *graphResult
+	)
+	for i, n := range g.nodes {
+		if waiting[i] = n.deps; n.deps == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for running > 0 || (len(ready) > 0 && failed == nil) {
+		if len(ready) == 0 || failed != nil {
+			running--
+			ready = g.finish(<-done, waiting, ready, &failed)
+			continue
+		}
+
+		// Run the op on an idle worker, or inline if there is none.
+		node := ready[0]
+		ready = ready[1:]
+		if pool.trySubmit(func() { done <- g.runNode(node, ctx) }) {
+			running++
+			continue
+		}
+		ready = g.finish(g.runNode(node, ctx), waiting, ready, &failed)
+	}
+
+	if failed != nil {
+		panic(failed.err)
+	}
+}
+
+func (g *OpGraph) finish(res graphResult, waiting, ready []int, failed **graphResult) []int {
+	if res.err != nil && *failed == nil {
+		*failed = &res
+	}
+	for _, j := range g.nodes[res.node].next {
+		if waiting[j]--; waiting[j] == 0 {
+			ready = append(ready, j)
+		}
+	}
+	return ready
+}
+
+// PanicError is re-raised on the calling goroutine when work run on a worker goroutine panics, such as by ParallelFor
+// or an OpGraph. It wraps the recovered value, so errors.As and errors.Is see panics with error values, and keeps the
+// stack of the goroutine that panicked.
+type PanicError struct {
+	Op    string      // What panicked, such as "ParallelFor" or an OpGraph op's name
+	Value interface{} // The recovered value
+	Stack []byte      // The stack of the panicking goroutine, as from debug.Stack
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("gt3: %s panicked: %v\n\n%s", e.Op, e.Value, e.Stack)
+}
+
+// Unwrap returns the recovered value if it's an error, and nil otherwise.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
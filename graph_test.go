@@ -0,0 +1,74 @@
+package gt3
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOpGraphOrder(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	op := func(name string) Op {
+		return OpFn(func(float64, float64, time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, name)
+		})
+	}
+
+	var g OpGraph
+	g.Add("input", nil, []string{"input"}, op("input"))
+	g.Add("physics", []string{"input"}, []string{"bodies"}, op("physics"))
+	g.Add("audio", nil, []string{"audio"}, op("audio"))
+	g.Add("ai", []string{"bodies"}, []string{"agents"}, op("ai"))
+	g.Add("animate", []string{"bodies", "agents"}, nil, op("animate"))
+	for i := 0; i < 100; i++ {
+		ran = nil
+		g.Do(1.0/60, 0, time.Time{})
+		if len(ran) != 5 {
+			t.Fatalf("ran %v; want 5 ops", ran)
+		}
+		index := map[string]int{}
+		for i, name := range ran {
+			index[name] = i
+		}
+		if !(index["input"] < index["physics"] && index["physics"] < index["ai"] && index["ai"] < index["animate"]) {
+			t.Fatalf("ran %v; want input, physics, ai, and animate in order", ran)
+		}
+	}
+}
+
+func TestOpGraphPanic(t *testing.T) {
+	errOp := errors.New("op failed")
+	ran := false
+
+	var g OpGraph
+	g.Add("fail", nil, []string{"x"}, OpFn(func(float64, float64, time.Time) { panic(errOp) }))
+	g.Add("after", []string{"x"}, nil, OpFn(func(float64, float64, time.Time) { ran = true }))
+
+	v := func() (v interface{}) {
+		defer func() { v = recover() }()
+		g.Do(1.0/60, 0, time.Time{})
+		return nil
+	}()
+	err, ok := v.(*PanicError)
+	if !ok {
+		t.Fatalf("Do() panicked with %#v; want a *PanicError", v)
+	}
+	if want := `op "fail"`; err.Op != want {
+		t.Errorf("Op = %q; want %q", err.Op, want)
+	}
+	if !errors.Is(err, errOp) {
+		t.Errorf("errors.Is(%v, %v) = false; want true", err, errOp)
+	}
+	if len(err.Stack) == 0 {
+		t.Error("Stack is empty")
+	}
+	if ran {
+		t.Error("op depending on the panicking op ran")
+	}
+}
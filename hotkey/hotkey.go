@@ -0,0 +1,128 @@
+// Package hotkey delivers system-wide hotkeys as gt3 HotkeyEvents, even while the application's windows are
+// unfocused, for tools such as overlays and recorders. Keys are read through go.spiff.io/gt3/rawinput, so hotkeys are
+// available on Linux, Windows, and macOS, with the same permissions it requires: read access to evdev devices on
+// Linux, and the Input Monitoring permission on macOS.
+package hotkey
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/rawinput"
+)
+
+// ErrDuplicate is returned when registering a hotkey whose name or combination is already registered.
+var ErrDuplicate = errors.New("hotkey: hotkey already registered")
+
+// Combo is a key pressed while holding exactly the given modifiers. Left and right modifier keys are equivalent.
+type Combo struct {
+	Key  gt3.Key
+	Mods gt3.ModifierKey
+}
+
+// Hotkeys watches keyboards for registered key combinations.
+type Hotkeys struct {
+	src *rawinput.Source
+
+	mu     sync.Mutex
+	combos map[Combo]string
+	held   map[gt3.DeviceID]map[gt3.Key]bool
+}
+
+// Open opens all readable keyboards for hotkey detection. It returns rawinput.ErrUnsupported on platforms without a
+// raw input backend, and rawinput.ErrNoDevices if no keyboard could be opened.
+func Open() (*Hotkeys, error) {
+	src, err := rawinput.Open(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Hotkeys{
+		src:    src,
+		combos: make(map[Combo]string),
+		held:   make(map[gt3.DeviceID]map[gt3.Key]bool),
+	}, nil
+}
+
+// Register registers a hotkey under the given name. HotkeyEvents for it carry the name.
+func (h *Hotkeys) Register(name string, combo Combo) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, dup := h.combos[combo]; dup {
+		return ErrDuplicate
+	}
+	for _, n := range h.combos {
+		if n == name {
+			return ErrDuplicate
+		}
+	}
+	h.combos[combo] = name
+	return nil
+}
+
+// Unregister removes the hotkey with the given name.
+func (h *Hotkeys) Unregister(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c, n := range h.combos {
+		if n == name {
+			delete(h.combos, c)
+		}
+	}
+}
+
+// Poll posts a HotkeyEvent to handler for each registered combination pressed since the last call to Poll. It should be
+// called from the main goroutine, typically in the Sim's PreFrame op.
+func (h *Hotkeys) Poll(handler gt3.EventHandler) {
+	h.src.Poll(gt3.EventHandlerFn(func(e gt3.Event, when time.Time) {
+		ev, ok := e.(gt3.RawKeyEvent)
+		if !ok {
+			return
+		}
+		held := h.held[ev.Device]
+		if held == nil {
+			held = make(map[gt3.Key]bool)
+			h.held[ev.Device] = held
+		}
+
+		switch ev.Action {
+		case gt3.Release:
+			delete(held, ev.Key)
+			return
+		case gt3.Repeat:
+			return
+		}
+		held[ev.Key] = true
+
+		h.mu.Lock()
+		name, ok := h.combos[Combo{ev.Key, mods(held)}]
+		h.mu.Unlock()
+		if ok {
+			handler.Event(gt3.HotkeyEvent{Name: name, Device: ev.Device}, when)
+		}
+	}))
+}
+
+// mods returns the modifiers held.
+func mods(held map[gt3.Key]bool) (m gt3.ModifierKey) {
+	for _, k := range [...]struct {
+		l, r gt3.Key
+		mod  gt3.ModifierKey
+	}{
+		{gt3.KeyLeftShift, gt3.KeyRightShift, gt3.ModShift},
+		{gt3.KeyLeftControl, gt3.KeyRightControl, gt3.ModControl},
+		{gt3.KeyLeftAlt, gt3.KeyRightAlt, gt3.ModAlt},
+		{gt3.KeyLeftSuper, gt3.KeyRightSuper, gt3.ModSuper},
+	} {
+		if held[k.l] || held[k.r] {
+			m |= k.mod
+		}
+	}
+	return m
+}
+
+// Close stops watching keyboards.
+func (h *Hotkeys) Close() error {
+	return h.src.Close()
+}
@@ -0,0 +1,75 @@
+package gt3
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// IdleGC configures garbage collection during idle loop time. When the Sim's loop has nothing to do until its next
+// frame or render, it may run a collection then instead of leaving the runtime to collect mid-frame. Idle time only
+// exists when render FPS is limited, since an unlimited Sim renders on every loop iteration.
+type IdleGC struct {
+	// GCSlack is the minimum idle time before the next frame or render for which runtime.GC is called. Zero disables
+	// idle collection.
+	GCSlack time.Duration
+	// FreeSlack is the minimum idle time for which debug.FreeOSMemory is called instead, returning memory to the
+	// operating system. It should be larger than GCSlack, since FreeOSMemory is more expensive. Zero disables it.
+	FreeSlack time.Duration
+	// MinInterval is the minimum time between idle collections. Regardless of MinInterval, at most one collection runs
+	// between a Sim's frames and renders.
+	MinInterval time.Duration
+}
+
+type idleGC struct {
+	IdleGC
+	last          time.Time
+	tick, renders uint64 // Sim tick and render count at the last collection
+
+	gc, free func() // runtime.GC and debug.FreeOSMemory, replaced in tests
+}
+
+// SetIdleGC enables garbage collection during idle loop time with the given configuration. A zero IdleGC disables
+// it. SetIdleGC must be called before Run.
+func (s *Sim) SetIdleGC(conf IdleGC) {
+	if conf.GCSlack <= 0 && conf.FreeSlack <= 0 {
+		s.idleGC = nil
+		return
+	}
+	s.idleGC = &idleGC{IdleGC: conf, gc: runtime.GC, free: debug.FreeOSMemory}
+}
+
+// collectIdle runs a collection if there's enough time before the next frame or render.
+func (s *Sim) collectIdle() {
+	s.fpsrw.RLock()
+	rlimit := s.rfps > 0
+	s.fpsrw.RUnlock()
+	if !rlimit {
+		return
+	}
+
+	next := s.simTime
+	if s.renderTime < next {
+		next = s.renderTime
+	}
+	slack := time.Duration((next - s.Now()) * float64(time.Second))
+	s.idleGC.collect(slack, s.Tick(), s.RenderCount(), time.Now())
+}
+
+// collect runs a collection if slack is long enough, unless one already ran since the Sim's last frame or render, so
+// that an idle gap spanning many loop iterations gets at most one collection.
+func (gc *idleGC) collect(slack time.Duration, tick, renders uint64, now time.Time) {
+	if !gc.last.IsZero() && (tick == gc.tick && renders == gc.renders || now.Sub(gc.last) < gc.MinInterval) {
+		return
+	}
+
+	switch {
+	case gc.FreeSlack > 0 && slack >= gc.FreeSlack:
+		gc.free()
+	case gc.GCSlack > 0 && slack >= gc.GCSlack:
+		gc.gc()
+	default:
+		return
+	}
+	gc.last, gc.tick, gc.renders = now, tick, renders
+}
@@ -0,0 +1,58 @@
+package gt3
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleGC(t *testing.T) {
+	gcs, frees := 0, 0
+	gc := &idleGC{
+		IdleGC: IdleGC{GCSlack: 2 * time.Millisecond, FreeSlack: 10 * time.Millisecond},
+		gc:     func() { gcs++ },
+		free:   func() { frees++ },
+	}
+
+	now := time.Unix(1000, 0)
+	tests := []struct {
+		name          string
+		slack         time.Duration
+		tick, renders uint64
+		gcs, frees    int // Total collections after the step
+	}{
+		{"Busy", time.Millisecond, 1, 1, 0, 0},
+		{"Idle", 5 * time.Millisecond, 1, 1, 1, 0},
+		{"SameGap", 4 * time.Millisecond, 1, 1, 1, 0},
+		{"SameGapLongSlack", 20 * time.Millisecond, 1, 1, 1, 0},
+		{"AfterRender", 5 * time.Millisecond, 1, 2, 2, 0},
+		{"AfterFrame", 20 * time.Millisecond, 2, 2, 2, 1},
+		{"AfterFrameBusy", time.Millisecond, 3, 2, 2, 1},
+		{"NextGap", 3 * time.Millisecond, 3, 2, 3, 1},
+	}
+	for _, tt := range tests {
+		now = now.Add(time.Millisecond)
+		gc.collect(tt.slack, tt.tick, tt.renders, now)
+		if gcs != tt.gcs || frees != tt.frees {
+			t.Errorf("%s: runtime.GC ran %d times, debug.FreeOSMemory %d; want %d and %d",
+				tt.name, gcs, frees, tt.gcs, tt.frees)
+		}
+	}
+}
+
+func TestIdleGCMinInterval(t *testing.T) {
+	gcs := 0
+	gc := &idleGC{
+		IdleGC: IdleGC{GCSlack: time.Millisecond, MinInterval: time.Second},
+		gc:     func() { gcs++ },
+	}
+
+	now := time.Unix(1000, 0)
+	for tick := uint64(1); tick <= 10; tick++ {
+		gc.collect(time.Second, tick, tick, now)
+		now = now.Add(250 * time.Millisecond)
+	}
+	// Collections at 0s, 1s, and 2s.
+	if gcs != 3 {
+		t.Errorf("runtime.GC ran %d times; want 3", gcs)
+	}
+}
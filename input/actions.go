@@ -0,0 +1,222 @@
+package input
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// ActionEvent is posted by an ActionMap when a named action's input changes. Value is 1 or 0 for buttons and keys,
+// and the axis position for gamepad axes.
+type ActionEvent struct {
+	gt3.CustomEvent
+	Name    string
+	Pressed bool
+	Value   float64
+}
+
+// AxisPressThreshold is how far a gamepad axis must be from rest for an action bound to it to be pressed.
+const AxisPressThreshold = 0.5
+
+// ActionMap maps keys, mouse buttons, and gamepad inputs to named actions, so that games handle "jump" rather than
+// KeyEvents for KeySpace, and players can rebind controls. An action bound to several inputs is pressed while any of
+// them is held. A key or mouse binding matches when its key or button is pressed with at least its modifiers held.
+// Gamepad inputs are taken from GamepadButtonEvents and GamepadAxisEvents, whose buttons and axes are assumed to follow
+// the standard gamepad layout of GamepadInput. An ActionMap must only be used from the main goroutine.
+type ActionMap struct {
+	// Next receives ActionEvents and all events that don't match a binding.
+	Next gt3.EventHandler
+
+	bindings map[string][]Binding
+	held     map[Binding]bool
+	pressed  map[string]int // Number of held bindings per action
+}
+
+// NewActionMap returns an empty ActionMap posting to next.
+func NewActionMap(next gt3.EventHandler) *ActionMap {
+	return &ActionMap{
+		Next:     next,
+		bindings: make(map[string][]Binding),
+		held:     make(map[Binding]bool),
+		pressed:  make(map[string]int),
+	}
+}
+
+// Bind adds bindings to an action.
+func (m *ActionMap) Bind(action string, bindings ...Binding) {
+	m.bindings[action] = append(m.bindings[action], bindings...)
+}
+
+// BindString adds bindings to an action given as binding descriptors parsed by ParseBinding.
+func (m *ActionMap) BindString(action string, descs ...string) error {
+	bindings := make([]Binding, len(descs))
+	for i, desc := range descs {
+		b, err := ParseBinding(desc)
+		if err != nil {
+			return err
+		}
+		bindings[i] = b
+	}
+	m.Bind(action, bindings...)
+	return nil
+}
+
+// Unbind removes all of an action's bindings, releasing it if it's pressed.
+func (m *ActionMap) Unbind(action string) {
+	for _, b := range m.bindings[action] {
+		delete(m.held, b)
+	}
+	delete(m.bindings, action)
+	if m.pressed[action] > 0 {
+		delete(m.pressed, action)
+		m.Next.Event(ActionEvent{Name: action}, time.Now())
+	}
+}
+
+// Bindings returns an action's bindings.
+func (m *ActionMap) Bindings(action string) []Binding {
+	return append([]Binding(nil), m.bindings[action]...)
+}
+
+// Actions returns the names of all actions with bindings, sorted.
+func (m *ActionMap) Actions() []string {
+	names := make([]string, 0, len(m.bindings))
+	for name := range m.bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pressed reports whether any of an action's bindings is held.
+func (m *ActionMap) Pressed(action string) bool {
+	return m.pressed[action] > 0
+}
+
+// MarshalJSON encodes the map's bindings as an object of action names to arrays of binding descriptors.
+func (m *ActionMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.bindings)
+}
+
+// UnmarshalJSON replaces the map's bindings with those encoded by MarshalJSON. Held actions are released.
+func (m *ActionMap) UnmarshalJSON(data []byte) error {
+	var bindings map[string][]Binding
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return err
+	}
+	for _, action := range m.Actions() {
+		m.Unbind(action)
+	}
+	for action, bs := range bindings {
+		m.Bind(action, bs...)
+	}
+	return nil
+}
+
+// Save writes the map's bindings to w as JSON.
+func (m *ActionMap) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(m)
+}
+
+// Load replaces the map's bindings with JSON read from r.
+func (m *ActionMap) Load(r io.Reader) error {
+	return json.NewDecoder(r).Decode(m)
+}
+
+// gamepadAxes maps standard gamepad axis indices to GamepadInputs.
+var gamepadAxes = [...]GamepadInput{
+	GamepadLeftX, GamepadLeftY,
+	GamepadRightX, GamepadRightY,
+	GamepadLeftTrigger, GamepadRightTrigger,
+}
+
+func (m *ActionMap) Event(e gt3.Event, when time.Time) {
+	matched := false
+	switch ev := e.(type) {
+	case gt3.KeyEvent:
+		if ev.Action == gt3.Repeat {
+			break
+		}
+		matched = m.button(ev.Action == gt3.Press, ev.Mods, when, func(b Binding) bool {
+			return b.Device == DeviceKeyboard && b.Key == ev.Key
+		})
+	case gt3.MouseEvent:
+		matched = m.button(ev.Action == gt3.Press, ev.Mods, when, func(b Binding) bool {
+			return b.Device == DeviceMouse && b.Button == ev.Button
+		})
+	case gt3.GamepadButtonEvent:
+		matched = m.button(ev.Action == gt3.Press, 0, when, func(b Binding) bool {
+			return b.Device == DeviceGamepad && b.Gamepad == GamepadInput(ev.Button) && b.Gamepad < GamepadLeftTrigger
+		})
+	case gt3.GamepadAxisEvent:
+		if ev.Axis >= 0 && ev.Axis < len(gamepadAxes) {
+			matched = m.axis(gamepadAxes[ev.Axis], ev.Value, when)
+		}
+	}
+	if !matched {
+		m.Next.Event(e, when)
+	}
+}
+
+// button updates actions with bindings matching a pressed or released button or key, and reports whether any did.
+func (m *ActionMap) button(press bool, mods gt3.ModifierKey, when time.Time, match func(Binding) bool) (matched bool) {
+	for _, action := range m.Actions() {
+		for _, b := range m.bindings[action] {
+			if !match(b) {
+				continue
+			}
+			matched = true
+			if press && mods&b.Mods == b.Mods && !m.held[b] {
+				m.held[b] = true
+				m.hold(action, 1, when)
+			} else if !press && m.held[b] {
+				delete(m.held, b)
+				m.hold(action, -1, when)
+			}
+		}
+	}
+	return matched
+}
+
+// axis posts ActionEvents for actions bound to a gamepad axis, and reports whether any are.
+func (m *ActionMap) axis(input GamepadInput, v float64, when time.Time) (matched bool) {
+	down := math.Abs(v) >= AxisPressThreshold
+	for _, action := range m.Actions() {
+		for _, b := range m.bindings[action] {
+			if b.Device != DeviceGamepad || b.Gamepad != input {
+				continue
+			}
+			matched = true
+			if down != m.held[b] {
+				if down {
+					m.held[b] = true
+					m.pressed[action]++
+				} else {
+					delete(m.held, b)
+					m.pressed[action]--
+				}
+			}
+			m.Next.Event(ActionEvent{Name: action, Pressed: m.pressed[action] > 0, Value: v}, when)
+		}
+	}
+	return matched
+}
+
+// hold adds delta to the number of an action's held bindings, posting an ActionEvent if that presses or releases it.
+func (m *ActionMap) hold(action string, delta int, when time.Time) {
+	was := m.pressed[action] > 0
+	m.pressed[action] += delta
+	if now := m.pressed[action] > 0; now != was {
+		value := 0.0
+		if now {
+			value = 1
+		}
+		m.Next.Event(ActionEvent{Name: action, Pressed: now, Value: value}, when)
+	}
+}
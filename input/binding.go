@@ -0,0 +1,235 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.spiff.io/gt3"
+)
+
+// Device is the kind of device a Binding refers to.
+type Device int
+
+// Devices.
+const (
+	DeviceKeyboard Device = iota
+	DeviceMouse
+	DeviceGamepad
+)
+
+// GamepadInput is a button or axis of a standard gamepad layout.
+type GamepadInput int
+
+// Gamepad inputs.
+const (
+	GamepadA GamepadInput = iota
+	GamepadB
+	GamepadX
+	GamepadY
+	GamepadLeftBumper
+	GamepadRightBumper
+	GamepadBack
+	GamepadStart
+	GamepadGuide
+	GamepadLeftThumb
+	GamepadRightThumb
+	GamepadDPadUp
+	GamepadDPadRight
+	GamepadDPadDown
+	GamepadDPadLeft
+	GamepadLeftTrigger
+	GamepadRightTrigger
+	GamepadLeftX
+	GamepadLeftY
+	GamepadRightX
+	GamepadRightY
+)
+
+var gamepadNames = [...]string{
+	GamepadA:            "A",
+	GamepadB:            "B",
+	GamepadX:            "X",
+	GamepadY:            "Y",
+	GamepadLeftBumper:   "LeftBumper",
+	GamepadRightBumper:  "RightBumper",
+	GamepadBack:         "Back",
+	GamepadStart:        "Start",
+	GamepadGuide:        "Guide",
+	GamepadLeftThumb:    "LeftThumb",
+	GamepadRightThumb:   "RightThumb",
+	GamepadDPadUp:       "DPadUp",
+	GamepadDPadRight:    "DPadRight",
+	GamepadDPadDown:     "DPadDown",
+	GamepadDPadLeft:     "DPadLeft",
+	GamepadLeftTrigger:  "LeftTrigger",
+	GamepadRightTrigger: "RightTrigger",
+	GamepadLeftX:        "LeftX",
+	GamepadLeftY:        "LeftY",
+	GamepadRightX:       "RightX",
+	GamepadRightY:       "RightY",
+}
+
+func (g GamepadInput) String() string {
+	if g >= 0 && int(g) < len(gamepadNames) {
+		return gamepadNames[g]
+	}
+	return "GamepadInput(" + strconv.Itoa(int(g)) + ")"
+}
+
+// Binding is a single physical input that an action can be bound to: a key with modifiers, a mouse button with
+// modifiers, or a gamepad button or axis. Only the field for the Binding's Device is meaningful.
+type Binding struct {
+	Device  Device
+	Mods    gt3.ModifierKey
+	Key     gt3.Key
+	Button  gt3.MouseButton
+	Gamepad GamepadInput
+}
+
+// ErrBadBinding is wrapped by errors returned by ParseBinding.
+var ErrBadBinding = errors.New("input: invalid binding")
+
+var modNames = [...]struct {
+	mod  gt3.ModifierKey
+	name string
+}{
+	{gt3.ModControl, "Ctrl"},
+	{gt3.ModAlt, "Alt"},
+	{gt3.ModShift, "Shift"},
+	{gt3.ModSuper, "Super"},
+}
+
+// String formats b as a binding descriptor, such as "Ctrl+Shift+S", "Mouse4", or "Gamepad:RightTrigger". Modifiers
+// are always written in the order Ctrl, Alt, Shift, Super.
+func (b Binding) String() string {
+	var s strings.Builder
+	if b.Device != DeviceGamepad {
+		for _, m := range modNames {
+			if b.Mods&m.mod != 0 {
+				s.WriteString(m.name)
+				s.WriteByte('+')
+			}
+		}
+	}
+
+	switch b.Device {
+	case DeviceKeyboard:
+		if name, ok := keyNames[b.Key]; ok {
+			s.WriteString(name)
+		} else {
+			s.WriteString("Key" + strconv.Itoa(int(b.Key)))
+		}
+	case DeviceMouse:
+		s.WriteString("Mouse" + strconv.Itoa(int(b.Button)+1))
+	case DeviceGamepad:
+		s.WriteString("Gamepad:" + b.Gamepad.String())
+	}
+	return s.String()
+}
+
+// MarshalText implements encoding.TextMarshaler using String.
+func (b Binding) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseBinding.
+func (b *Binding) UnmarshalText(text []byte) error {
+	parsed, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+var (
+	keysByName   map[string]gt3.Key
+	modsByName   map[string]gt3.ModifierKey
+	padsByName   map[string]GamepadInput
+	extraAliases = map[string]string{
+		"control": "ctrl",
+		"option":  "alt",
+		"cmd":     "super",
+		"win":     "super",
+		"meta":    "super",
+		"esc":     "escape",
+		"return":  "enter",
+		"del":     "delete",
+		"ins":     "insert",
+		"pgup":    "pageup",
+		"pgdn":    "pagedown",
+	}
+)
+
+func init() {
+	keysByName = make(map[string]gt3.Key, len(keyNames))
+	for k, name := range keyNames {
+		keysByName[strings.ToLower(name)] = k
+	}
+	modsByName = make(map[string]gt3.ModifierKey, len(modNames))
+	for _, m := range modNames {
+		modsByName[strings.ToLower(m.name)] = m.mod
+	}
+	padsByName = make(map[string]GamepadInput, len(gamepadNames))
+	for g, name := range gamepadNames {
+		padsByName[strings.ToLower(name)] = GamepadInput(g)
+	}
+}
+
+func canonicalName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if alias, ok := extraAliases[s]; ok {
+		return alias
+	}
+	return s
+}
+
+// ParseBinding parses a binding descriptor as formatted by Binding.String. Parsing is case-insensitive, and common
+// aliases, such as "Control", "Cmd", and "Esc", are accepted. Gamepad bindings don't take modifiers.
+func ParseBinding(s string) (Binding, error) {
+	if rest, ok := cutPrefixFold(strings.TrimSpace(s), "gamepad:"); ok {
+		g, ok := padsByName[canonicalName(rest)]
+		if !ok {
+			return Binding{}, fmt.Errorf("%w: unknown gamepad input %q", ErrBadBinding, rest)
+		}
+		return Binding{Device: DeviceGamepad, Gamepad: g}, nil
+	}
+
+	parts := strings.Split(s, "+")
+	var b Binding
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modsByName[canonicalName(part)]
+		if !ok {
+			return Binding{}, fmt.Errorf("%w: unknown modifier %q", ErrBadBinding, part)
+		}
+		b.Mods |= mod
+	}
+
+	last := canonicalName(parts[len(parts)-1])
+	if k, ok := keysByName[last]; ok {
+		b.Key = k
+		return b, nil
+	}
+	if n, ok := cutPrefixFold(last, "mouse"); ok {
+		if btn, err := strconv.Atoi(n); err == nil && btn >= 1 && btn <= int(gt3.MouseButtonLast)+1 {
+			b.Device, b.Button = DeviceMouse, gt3.MouseButton(btn-1)
+			return b, nil
+		}
+	}
+	if n, ok := cutPrefixFold(last, "key"); ok {
+		if code, err := strconv.Atoi(n); err == nil {
+			b.Key = gt3.Key(code)
+			return b, nil
+		}
+	}
+	return Binding{}, fmt.Errorf("%w: unknown key or button %q", ErrBadBinding, parts[len(parts)-1])
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,77 @@
+package input
+
+import (
+	"errors"
+	"testing"
+
+	"go.spiff.io/gt3"
+)
+
+func TestBindingRoundTrip(t *testing.T) {
+	tests := []struct {
+		b    Binding
+		want string
+	}{
+		{Binding{Key: gt3.KeyS}, "S"},
+		{Binding{Key: gt3.KeyS, Mods: gt3.ModShift | gt3.ModControl}, "Ctrl+Shift+S"},
+		{Binding{Key: gt3.KeyF5, Mods: gt3.ModSuper | gt3.ModAlt}, "Alt+Super+F5"},
+		{Binding{Key: gt3.KeyPageUp}, "PageUp"},
+		{Binding{Key: gt3.Key(1000)}, "Key1000"},
+		{Binding{Device: DeviceMouse, Button: gt3.MouseButton4}, "Mouse4"},
+		{Binding{Device: DeviceMouse, Button: gt3.MouseButtonLeft, Mods: gt3.ModControl}, "Ctrl+Mouse1"},
+		{Binding{Device: DeviceGamepad, Gamepad: GamepadRightTrigger}, "Gamepad:RightTrigger"},
+	}
+	for _, tt := range tests {
+		if got := tt.b.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q; want %q", tt.b, got, tt.want)
+		}
+		parsed, err := ParseBinding(tt.want)
+		if err != nil {
+			t.Errorf("ParseBinding(%q) error: %v", tt.want, err)
+			continue
+		}
+		if parsed != tt.b {
+			t.Errorf("ParseBinding(%q) = %#v; want %#v", tt.want, parsed, tt.b)
+		}
+	}
+}
+
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Binding
+	}{
+		{"ctrl+s", Binding{Key: gt3.KeyS, Mods: gt3.ModControl}},
+		{"Control + Shift + s", Binding{Key: gt3.KeyS, Mods: gt3.ModControl | gt3.ModShift}},
+		{"Cmd+Esc", Binding{Key: gt3.KeyEscape, Mods: gt3.ModSuper}},
+		{"option+pgdn", Binding{Key: gt3.KeyPageDown, Mods: gt3.ModAlt}},
+		{"  gamepad:a ", Binding{Device: DeviceGamepad, Gamepad: GamepadA}},
+		{"MOUSE2", Binding{Device: DeviceMouse, Button: gt3.MouseButton2}},
+	}
+	for _, tt := range tests {
+		got, err := ParseBinding(tt.s)
+		if err != nil {
+			t.Errorf("ParseBinding(%q) error: %v", tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBinding(%q) = %#v; want %#v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseBindingErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"Ctrl+",
+		"Hyper+S",
+		"Mouse0",
+		"Mouse99",
+		"Gamepad:Turbo",
+		"NotAKey",
+	} {
+		if b, err := ParseBinding(s); !errors.Is(err, ErrBadBinding) {
+			t.Errorf("ParseBinding(%q) = %#v, %v; want error wrapping ErrBadBinding", s, b, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package input
+
+import (
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// cursorSamples is the number of cursor positions kept by a CursorSmoother.
+const cursorSamples = 8
+
+type cursorSample struct {
+	x, y float64
+	when time.Time
+}
+
+// CursorSmoother produces a cursor position for any point in time from recent CursorPosEvents, so that cursor-driven
+// visuals move smoothly when rendering faster than the platform delivers cursor events. It is an EventHandler and
+// should receive cursor events for a single window. A CursorSmoother must only be used from the main goroutine.
+//
+// Positions are evaluated Delay in the past and interpolated between the samples around that time. Past the newest
+// sample, the position is either held or, if Predict is set, extrapolated from the newest two samples for up to
+// MaxPredict. A small Delay (about one event interval) with no prediction gives smooth, slightly latent motion; no
+// Delay with prediction gives responsive motion that may overshoot.
+type CursorSmoother struct {
+	Delay      time.Duration
+	Predict    bool
+	MaxPredict time.Duration
+
+	samples [cursorSamples]cursorSample
+	n       int // Number of samples recorded, up to cursorSamples
+	head    int // Index of the newest sample
+}
+
+// Event records the position of a CursorPosEvent. Other events are ignored.
+func (c *CursorSmoother) Event(e gt3.Event, when time.Time) {
+	ev, ok := e.(gt3.CursorPosEvent)
+	if !ok {
+		return
+	}
+	c.head = (c.head + 1) % cursorSamples
+	c.samples[c.head] = cursorSample{ev.X, ev.Y, when}
+	if c.n < cursorSamples {
+		c.n++
+	}
+}
+
+// Reset discards all recorded samples, such as after the cursor is warped.
+func (c *CursorSmoother) Reset() {
+	c.n = 0
+}
+
+// sample returns the i-th newest sample.
+func (c *CursorSmoother) sample(i int) cursorSample {
+	return c.samples[(c.head-i+cursorSamples)%cursorSamples]
+}
+
+// Position returns the smoothed cursor position at the given time, typically the OpContext.When of a render op. If no
+// cursor events have been recorded, Position returns ok=false.
+func (c *CursorSmoother) Position(at time.Time) (x, y float64, ok bool) {
+	if c.n == 0 {
+		return 0, 0, false
+	}
+	at = at.Add(-c.Delay)
+
+	newest := c.sample(0)
+	if !at.Before(newest.when) {
+		if !c.Predict || c.n < 2 {
+			return newest.x, newest.y, true
+		}
+		ahead := at.Sub(newest.when)
+		if c.MaxPredict > 0 && ahead > c.MaxPredict {
+			ahead = c.MaxPredict
+		}
+		return lerpSample(c.sample(1), newest, newest.when.Add(ahead))
+	}
+
+	for i := 1; i < c.n; i++ {
+		if older := c.sample(i); !at.Before(older.when) {
+			return lerpSample(older, c.sample(i-1), at)
+		}
+	}
+	oldest := c.sample(c.n - 1)
+	return oldest.x, oldest.y, true
+}
+
+// lerpSample linearly interpolates, or extrapolates, between two samples at the given time.
+func lerpSample(a, b cursorSample, at time.Time) (x, y float64, ok bool) {
+	span := b.when.Sub(a.when)
+	if span <= 0 {
+		return b.x, b.y, true
+	}
+	t := float64(at.Sub(a.when)) / float64(span)
+	return a.x + (b.x-a.x)*t, a.y + (b.y-a.y)*t, true
+}
@@ -0,0 +1,203 @@
+// Package input provides input handling built on gt3 events.
+package input
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"go.spiff.io/gt3"
+)
+
+// Layout is the set of named buttons and axes carried by a Frame. Peers exchanging Frames must use identical
+// Layouts, since Frames are encoded by position rather than by name.
+type Layout struct {
+	buttons   []string
+	axes      []string
+	buttonIdx map[string]int
+	axisIdx   map[string]int
+}
+
+// NewLayout returns a Layout for the given button and axis names. Names are sorted, so the order they're given in
+// doesn't matter. NewLayout panics if a button or axis name is repeated.
+func NewLayout(buttons, axes []string) *Layout {
+	l := &Layout{
+		buttons: append([]string(nil), buttons...),
+		axes:    append([]string(nil), axes...),
+	}
+	sort.Strings(l.buttons)
+	sort.Strings(l.axes)
+	l.buttonIdx = layoutIndex(l.buttons)
+	l.axisIdx = layoutIndex(l.axes)
+	return l
+}
+
+func layoutIndex(names []string) map[string]int {
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		if _, dup := index[name]; dup {
+			panic("input: duplicate layout name " + name)
+		}
+		index[name] = i
+	}
+	return index
+}
+
+// Buttons returns the Layout's button names.
+func (l *Layout) Buttons() []string { return append([]string(nil), l.buttons...) }
+
+// Axes returns the Layout's axis names.
+func (l *Layout) Axes() []string { return append([]string(nil), l.axes...) }
+
+// NewFrame returns an empty Frame for tick.
+func (l *Layout) NewFrame(tick uint64) *Frame {
+	return &Frame{
+		Tick:    tick,
+		layout:  l,
+		buttons: make([]byte, (len(l.buttons)+7)/8),
+		axes:    make([]float32, len(l.axes)),
+	}
+}
+
+// Source provides resolved input state by name.
+type Source interface {
+	Button(name string) bool
+	Axis(name string) float64
+}
+
+// Capture returns a Frame for tick holding the state of every button and axis in the Layout, as reported by src.
+func (l *Layout) Capture(tick uint64, src Source) *Frame {
+	f := l.NewFrame(tick)
+	for i, name := range l.buttons {
+		f.setButton(i, src.Button(name))
+	}
+	for i, name := range l.axes {
+		f.axes[i] = float32(src.Axis(name))
+	}
+	return f
+}
+
+// Frame is the resolved input for a single sim tick.
+type Frame struct {
+	Tick uint64
+
+	layout  *Layout
+	buttons []byte // Bitset
+	axes    []float32
+}
+
+// Layout returns the Frame's Layout.
+func (f *Frame) Layout() *Layout { return f.layout }
+
+// Button returns whether the named button is down. Unknown names are never down.
+func (f *Frame) Button(name string) bool {
+	i, ok := f.layout.buttonIdx[name]
+	return ok && f.buttons[i/8]&(1<<uint(i%8)) != 0
+}
+
+// SetButton sets whether the named button is down. Unknown names are ignored.
+func (f *Frame) SetButton(name string, down bool) {
+	if i, ok := f.layout.buttonIdx[name]; ok {
+		f.setButton(i, down)
+	}
+}
+
+func (f *Frame) setButton(i int, down bool) {
+	if down {
+		f.buttons[i/8] |= 1 << uint(i%8)
+	} else {
+		f.buttons[i/8] &^= 1 << uint(i%8)
+	}
+}
+
+// Axis returns the value of the named axis. Unknown names are always 0.
+func (f *Frame) Axis(name string) float64 {
+	if i, ok := f.layout.axisIdx[name]; ok {
+		return float64(f.axes[i])
+	}
+	return 0
+}
+
+// SetAxis sets the value of the named axis. Unknown names are ignored.
+func (f *Frame) SetAxis(name string, value float64) {
+	if i, ok := f.layout.axisIdx[name]; ok {
+		f.axes[i] = float32(value)
+	}
+}
+
+// MarshalBinary encodes the Frame as its tick followed by a bitset of button states and the axis values.
+func (f *Frame) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(f.buttons)+4*len(f.axes))
+	buf = buf[:binary.PutUvarint(buf, f.Tick)]
+	buf = append(buf, f.buttons...)
+	for _, v := range f.axes {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+		buf = append(buf, b[:]...)
+	}
+	return buf, nil
+}
+
+// ErrFrameSize is returned when decoding a Frame whose encoded size doesn't match its Layout.
+var ErrFrameSize = errors.New("input: encoded frame does not match layout")
+
+// Decode decodes a Frame encoded by Frame.MarshalBinary using the Layout l.
+func (l *Layout) Decode(data []byte) (*Frame, error) {
+	tick, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrFrameSize
+	}
+	data = data[n:]
+
+	f := l.NewFrame(tick)
+	if len(data) != len(f.buttons)+4*len(f.axes) {
+		return nil, ErrFrameSize
+	}
+	data = data[copy(f.buttons, data):]
+	for i := range f.axes {
+		f.axes[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return f, nil
+}
+
+// Queue holds Frames received from a remote peer until the local Sim reaches their tick. A Queue may be pushed to
+// from any goroutine.
+type Queue struct {
+	mu     sync.Mutex
+	frames map[uint64]*Frame
+}
+
+// Push adds a Frame to the queue, replacing any queued Frame for the same tick.
+func (q *Queue) Push(f *Frame) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.frames == nil {
+		q.frames = make(map[uint64]*Frame)
+	}
+	q.frames[f.Tick] = f
+}
+
+// Pop removes and returns the Frame for tick, if one has been pushed. Frames for earlier ticks are discarded.
+func (q *Queue) Pop(tick uint64) (*Frame, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	f, ok := q.frames[tick]
+	for t := range q.frames {
+		if t <= tick {
+			delete(q.frames, t)
+		}
+	}
+	return f, ok
+}
+
+// ApplyOp returns an Op that, when run as part of a Sim's Frame phase, pops the Frame for the current tick from q and
+// passes it to apply. If no Frame has been received for the tick, apply is not called.
+func ApplyOp(q *Queue, apply func(*Frame)) gt3.Op {
+	return gt3.ContextOpFn(func(ctx gt3.OpContext) {
+		if f, ok := q.Pop(ctx.Frame.Tick); ok {
+			apply(f)
+		}
+	})
+}
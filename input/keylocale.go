@@ -0,0 +1,155 @@
+package input
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.spiff.io/gt3"
+)
+
+// KeyLocale holds user-facing names of keys, modifiers, and buttons in one language. Names missing from a locale fall
+// back to English.
+type KeyLocale struct {
+	Keys    map[gt3.Key]string
+	Mods    map[gt3.ModifierKey]string
+	Gamepad map[GamepadInput]string
+	// Mouse formats a mouse button number, starting from 1. If nil, the English format is used.
+	Mouse func(n int) string
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]*KeyLocale{
+		"en": {
+			Keys: map[gt3.Key]string{
+				gt3.KeyEnter: "Enter", gt3.KeyEscape: "Esc", gt3.KeySpace: "Space", gt3.KeyBackspace: "Backspace",
+				gt3.KeyDelete: "Delete", gt3.KeyInsert: "Insert", gt3.KeyPageUp: "Page Up", gt3.KeyPageDown: "Page Down",
+				gt3.KeyUp: "Up", gt3.KeyDown: "Down", gt3.KeyLeft: "Left", gt3.KeyRight: "Right",
+				gt3.KeyCapsLock: "Caps Lock", gt3.KeyPrintScreen: "Print Screen", gt3.KeyLeftShift: "Left Shift",
+				gt3.KeyRightShift: "Right Shift", gt3.KeyLeftControl: "Left Ctrl", gt3.KeyRightControl: "Right Ctrl",
+				gt3.KeyLeftAlt: "Left Alt", gt3.KeyRightAlt: "Right Alt",
+			},
+			Mods: map[gt3.ModifierKey]string{
+				gt3.ModControl: "Ctrl", gt3.ModAlt: "Alt", gt3.ModShift: "Shift", gt3.ModSuper: "Super",
+			},
+		},
+		"de": {
+			Keys: map[gt3.Key]string{
+				gt3.KeyEnter: "Eingabetaste", gt3.KeyEscape: "Esc", gt3.KeySpace: "Leertaste",
+				gt3.KeyBackspace: "Rücktaste", gt3.KeyDelete: "Entf", gt3.KeyInsert: "Einfg", gt3.KeyHome: "Pos1",
+				gt3.KeyEnd: "Ende", gt3.KeyPageUp: "Bild auf", gt3.KeyPageDown: "Bild ab", gt3.KeyUp: "Pfeil oben",
+				gt3.KeyDown: "Pfeil unten", gt3.KeyLeft: "Pfeil links", gt3.KeyRight: "Pfeil rechts",
+				gt3.KeyCapsLock: "Feststelltaste", gt3.KeyPrintScreen: "Druck", gt3.KeyTab: "Tabulator",
+				gt3.KeyLeftShift: "Umschalt links", gt3.KeyRightShift: "Umschalt rechts",
+				gt3.KeyLeftControl: "Strg links", gt3.KeyRightControl: "Strg rechts", gt3.KeyLeftAlt: "Alt",
+				gt3.KeyRightAlt: "Alt Gr",
+			},
+			Mods: map[gt3.ModifierKey]string{
+				gt3.ModControl: "Strg", gt3.ModAlt: "Alt", gt3.ModShift: "Umschalt", gt3.ModSuper: "Windows",
+			},
+			Mouse: func(n int) string { return "Maustaste " + strconv.Itoa(n) },
+		},
+		"fr": {
+			Keys: map[gt3.Key]string{
+				gt3.KeyEnter: "Entrée", gt3.KeyEscape: "Échap", gt3.KeySpace: "Espace", gt3.KeyBackspace: "Retour arrière",
+				gt3.KeyDelete: "Suppr", gt3.KeyInsert: "Inser", gt3.KeyHome: "Origine", gt3.KeyEnd: "Fin",
+				gt3.KeyPageUp: "Page préc.", gt3.KeyPageDown: "Page suiv.", gt3.KeyUp: "Haut", gt3.KeyDown: "Bas",
+				gt3.KeyLeft: "Gauche", gt3.KeyRight: "Droite", gt3.KeyCapsLock: "Verr. maj.", gt3.KeyTab: "Tab",
+				gt3.KeyPrintScreen: "Impr. écran", gt3.KeyLeftShift: "Maj gauche", gt3.KeyRightShift: "Maj droite",
+				gt3.KeyLeftControl: "Ctrl gauche", gt3.KeyRightControl: "Ctrl droite", gt3.KeyRightAlt: "Alt Gr",
+			},
+			Mods: map[gt3.ModifierKey]string{
+				gt3.ModControl: "Ctrl", gt3.ModAlt: "Alt", gt3.ModShift: "Maj", gt3.ModSuper: "Windows",
+			},
+			Mouse: func(n int) string { return "Bouton souris " + strconv.Itoa(n) },
+		},
+	}
+)
+
+// RegisterKeyLocale adds or replaces the locale for a language tag, such as "es" or "pt-BR".
+func RegisterKeyLocale(lang string, l *KeyLocale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[strings.ToLower(lang)] = l
+}
+
+// lookupLocale returns the locale for lang, falling back from a region-specific tag to its base language.
+func lookupLocale(lang string) *KeyLocale {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if l, ok := locales[lang]; ok {
+		return l
+	}
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		return locales[lang[:i]]
+	}
+	return nil
+}
+
+// KeyNamer produces user-facing names for bindings in a language. If Layout is set, it's used to name printable keys
+// as labeled on the user's keyboard layout; gt3.Key.LayoutName is suitable when running under GLFW.
+type KeyNamer struct {
+	Lang   string
+	Layout func(gt3.Key) string
+}
+
+// KeyName returns the user-facing name of k.
+func (n KeyNamer) KeyName(k gt3.Key) string {
+	if n.Layout != nil {
+		if name := n.Layout(k); name != "" {
+			return strings.ToUpper(name)
+		}
+	}
+	for _, l := range [...]*KeyLocale{lookupLocale(n.Lang), lookupLocale("en")} {
+		if l == nil {
+			continue
+		}
+		if name, ok := l.Keys[k]; ok {
+			return name
+		}
+	}
+	return Binding{Key: k}.String()
+}
+
+// Name returns the user-facing name of b, such as "Strg+Umschalt+S".
+func (n KeyNamer) Name(b Binding) string {
+	loc, en := lookupLocale(n.Lang), lookupLocale("en")
+
+	var s strings.Builder
+	if b.Device != DeviceGamepad {
+		for _, m := range modNames {
+			if b.Mods&m.mod == 0 {
+				continue
+			}
+			name := m.name
+			if loc != nil && loc.Mods[m.mod] != "" {
+				name = loc.Mods[m.mod]
+			} else if en.Mods[m.mod] != "" {
+				name = en.Mods[m.mod]
+			}
+			s.WriteString(name)
+			s.WriteByte('+')
+		}
+	}
+
+	switch b.Device {
+	case DeviceKeyboard:
+		s.WriteString(n.KeyName(b.Key))
+	case DeviceMouse:
+		num := int(b.Button) + 1
+		if loc != nil && loc.Mouse != nil {
+			s.WriteString(loc.Mouse(num))
+		} else {
+			s.WriteString("Mouse " + strconv.Itoa(num))
+		}
+	case DeviceGamepad:
+		if loc != nil && loc.Gamepad[b.Gamepad] != "" {
+			s.WriteString(loc.Gamepad[b.Gamepad])
+		} else {
+			s.WriteString(b.Gamepad.String())
+		}
+	}
+	return s.String()
+}
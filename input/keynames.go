@@ -0,0 +1,128 @@
+package input
+
+import "go.spiff.io/gt3"
+
+// keyNames maps Keys to the names used in binding descriptors. Names are the Key constant names without their Key
+// prefix.
+var keyNames = map[gt3.Key]string{
+	gt3.KeySpace:        "Space",
+	gt3.KeyApostrophe:   "Apostrophe",
+	gt3.KeyComma:        "Comma",
+	gt3.KeyMinus:        "Minus",
+	gt3.KeyPeriod:       "Period",
+	gt3.KeySlash:        "Slash",
+	gt3.Key0:            "0",
+	gt3.Key1:            "1",
+	gt3.Key2:            "2",
+	gt3.Key3:            "3",
+	gt3.Key4:            "4",
+	gt3.Key5:            "5",
+	gt3.Key6:            "6",
+	gt3.Key7:            "7",
+	gt3.Key8:            "8",
+	gt3.Key9:            "9",
+	gt3.KeySemicolon:    "Semicolon",
+	gt3.KeyEqual:        "Equal",
+	gt3.KeyA:            "A",
+	gt3.KeyB:            "B",
+	gt3.KeyC:            "C",
+	gt3.KeyD:            "D",
+	gt3.KeyE:            "E",
+	gt3.KeyF:            "F",
+	gt3.KeyG:            "G",
+	gt3.KeyH:            "H",
+	gt3.KeyI:            "I",
+	gt3.KeyJ:            "J",
+	gt3.KeyK:            "K",
+	gt3.KeyL:            "L",
+	gt3.KeyM:            "M",
+	gt3.KeyN:            "N",
+	gt3.KeyO:            "O",
+	gt3.KeyP:            "P",
+	gt3.KeyQ:            "Q",
+	gt3.KeyR:            "R",
+	gt3.KeyS:            "S",
+	gt3.KeyT:            "T",
+	gt3.KeyU:            "U",
+	gt3.KeyV:            "V",
+	gt3.KeyW:            "W",
+	gt3.KeyX:            "X",
+	gt3.KeyY:            "Y",
+	gt3.KeyZ:            "Z",
+	gt3.KeyLeftBracket:  "LeftBracket",
+	gt3.KeyBackslash:    "Backslash",
+	gt3.KeyRightBracket: "RightBracket",
+	gt3.KeyGraveAccent:  "GraveAccent",
+	gt3.KeyWorld1:       "World1",
+	gt3.KeyWorld2:       "World2",
+	gt3.KeyEscape:       "Escape",
+	gt3.KeyEnter:        "Enter",
+	gt3.KeyTab:          "Tab",
+	gt3.KeyBackspace:    "Backspace",
+	gt3.KeyInsert:       "Insert",
+	gt3.KeyDelete:       "Delete",
+	gt3.KeyRight:        "Right",
+	gt3.KeyLeft:         "Left",
+	gt3.KeyDown:         "Down",
+	gt3.KeyUp:           "Up",
+	gt3.KeyPageUp:       "PageUp",
+	gt3.KeyPageDown:     "PageDown",
+	gt3.KeyHome:         "Home",
+	gt3.KeyEnd:          "End",
+	gt3.KeyCapsLock:     "CapsLock",
+	gt3.KeyScrollLock:   "ScrollLock",
+	gt3.KeyNumLock:      "NumLock",
+	gt3.KeyPrintScreen:  "PrintScreen",
+	gt3.KeyPause:        "Pause",
+	gt3.KeyF1:           "F1",
+	gt3.KeyF2:           "F2",
+	gt3.KeyF3:           "F3",
+	gt3.KeyF4:           "F4",
+	gt3.KeyF5:           "F5",
+	gt3.KeyF6:           "F6",
+	gt3.KeyF7:           "F7",
+	gt3.KeyF8:           "F8",
+	gt3.KeyF9:           "F9",
+	gt3.KeyF10:          "F10",
+	gt3.KeyF11:          "F11",
+	gt3.KeyF12:          "F12",
+	gt3.KeyF13:          "F13",
+	gt3.KeyF14:          "F14",
+	gt3.KeyF15:          "F15",
+	gt3.KeyF16:          "F16",
+	gt3.KeyF17:          "F17",
+	gt3.KeyF18:          "F18",
+	gt3.KeyF19:          "F19",
+	gt3.KeyF20:          "F20",
+	gt3.KeyF21:          "F21",
+	gt3.KeyF22:          "F22",
+	gt3.KeyF23:          "F23",
+	gt3.KeyF24:          "F24",
+	gt3.KeyF25:          "F25",
+	gt3.KeyKP0:          "KP0",
+	gt3.KeyKP1:          "KP1",
+	gt3.KeyKP2:          "KP2",
+	gt3.KeyKP3:          "KP3",
+	gt3.KeyKP4:          "KP4",
+	gt3.KeyKP5:          "KP5",
+	gt3.KeyKP6:          "KP6",
+	gt3.KeyKP7:          "KP7",
+	gt3.KeyKP8:          "KP8",
+	gt3.KeyKP9:          "KP9",
+	gt3.KeyKPDecimal:    "KPDecimal",
+	gt3.KeyKPDivide:     "KPDivide",
+	gt3.KeyKPMultiply:   "KPMultiply",
+	gt3.KeyKPSubtract:   "KPSubtract",
+	gt3.KeyKPAdd:        "KPAdd",
+	gt3.KeyKPEnter:      "KPEnter",
+	gt3.KeyKPEqual:      "KPEqual",
+	gt3.KeyLeftShift:    "LeftShift",
+	gt3.KeyLeftControl:  "LeftControl",
+	gt3.KeyLeftAlt:      "LeftAlt",
+	gt3.KeyLeftSuper:    "LeftSuper",
+	gt3.KeyRightShift:   "RightShift",
+	gt3.KeyRightControl: "RightControl",
+	gt3.KeyRightAlt:     "RightAlt",
+	gt3.KeyRightSuper:   "RightSuper",
+	gt3.KeyMenu:         "Menu",
+}
@@ -0,0 +1,131 @@
+package input
+
+import (
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// Smoother filters a stream of 2D motion deltas, such as per-tick mouse motion.
+type Smoother interface {
+	Smooth(dx, dy float64) (float64, float64)
+	Reset()
+}
+
+// NoSmoothing is a Smoother that returns deltas unchanged.
+type NoSmoothing struct{}
+
+func (NoSmoothing) Smooth(dx, dy float64) (float64, float64) { return dx, dy }
+func (NoSmoothing) Reset()                                   {}
+
+// ExpSmoothing is a Smoother that applies an exponential moving average to deltas. Alpha is the weight, from 0 to 1,
+// of the newest delta; lower values smooth more.
+type ExpSmoothing struct {
+	Alpha float64
+
+	x, y   float64
+	primed bool
+}
+
+func (s *ExpSmoothing) Smooth(dx, dy float64) (float64, float64) {
+	if !s.primed {
+		s.x, s.y, s.primed = dx, dy, true
+		return dx, dy
+	}
+	s.x += (dx - s.x) * s.Alpha
+	s.y += (dy - s.y) * s.Alpha
+	return s.x, s.y
+}
+
+func (s *ExpSmoothing) Reset() {
+	s.x, s.y, s.primed = 0, 0, false
+}
+
+// AverageSmoothing is a Smoother that averages the last N deltas.
+type AverageSmoothing struct {
+	dx, dy []float64
+	next   int
+	n      int
+}
+
+// NewAverageSmoothing returns an AverageSmoothing over n samples. Values of n less than 1 are treated as 1.
+func NewAverageSmoothing(n int) *AverageSmoothing {
+	if n < 1 {
+		n = 1
+	}
+	return &AverageSmoothing{dx: make([]float64, n), dy: make([]float64, n)}
+}
+
+func (s *AverageSmoothing) Smooth(dx, dy float64) (float64, float64) {
+	s.dx[s.next], s.dy[s.next] = dx, dy
+	s.next = (s.next + 1) % len(s.dx)
+	if s.n < len(s.dx) {
+		s.n++
+	}
+
+	var sx, sy float64
+	for i := 0; i < s.n; i++ {
+		sx += s.dx[i]
+		sy += s.dy[i]
+	}
+	return sx / float64(s.n), sy / float64(s.n)
+}
+
+func (s *AverageSmoothing) Reset() {
+	s.next, s.n = 0, 0
+}
+
+// MouseMotion accumulates cursor motion from CursorPosEvents and yields it once per sim tick, passed through a
+// Smoother. It is an EventHandler and should receive cursor events for a single window. A MouseMotion must only be used
+// from the main goroutine.
+type MouseMotion struct {
+	// Smoother filters per-tick deltas. If nil, deltas are not filtered.
+	Smoother Smoother
+	// Sensitivity scales deltas before smoothing. If zero, deltas are not scaled.
+	Sensitivity float64
+
+	lastX, lastY float64
+	known        bool
+	dx, dy       float64
+}
+
+// Event accumulates the motion of a CursorPosEvent. Other events are ignored.
+func (m *MouseMotion) Event(e gt3.Event, when time.Time) {
+	ev, ok := e.(gt3.CursorPosEvent)
+	if !ok {
+		return
+	}
+	if m.known {
+		m.dx += ev.X - m.lastX
+		m.dy += ev.Y - m.lastY
+	}
+	m.lastX, m.lastY, m.known = ev.X, ev.Y, true
+}
+
+// AddDelta accumulates relative motion, such as from a raw motion device, alongside cursor events.
+func (m *MouseMotion) AddDelta(dx, dy float64) {
+	m.dx += dx
+	m.dy += dy
+}
+
+// Delta returns the motion accumulated since the last call to Delta, scaled and smoothed. It should be called once per
+// sim tick, typically from the Frame op, so that smoothing is independent of event rate.
+func (m *MouseMotion) Delta() (dx, dy float64) {
+	dx, dy = m.dx, m.dy
+	m.dx, m.dy = 0, 0
+	if m.Sensitivity != 0 {
+		dx, dy = dx*m.Sensitivity, dy*m.Sensitivity
+	}
+	if m.Smoother != nil {
+		dx, dy = m.Smoother.Smooth(dx, dy)
+	}
+	return dx, dy
+}
+
+// Reset discards accumulated motion and smoothing state, such as after the cursor is warped or captured.
+func (m *MouseMotion) Reset() {
+	m.known, m.dx, m.dy = false, 0, 0
+	if m.Smoother != nil {
+		m.Smoother.Reset()
+	}
+}
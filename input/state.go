@@ -0,0 +1,114 @@
+package input
+
+import (
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// State is a snapshot of keyboard and mouse input for per-tick game logic, answering questions like "is W held right
+// now?" without handling events directly. It consumes KeyEvents, MouseEvents, CursorPosEvents, ScrollEvents, and
+// FocusEvents, normally delivered while polling for events in the PreFrame op. Presses and releases are latched to
+// ticks as by ButtonTracker, and cursor and scroll deltas accumulated between ticks are reported for the next tick
+// that queries them. A State must only be used from the main goroutine.
+type State struct {
+	Keyboard *Keyboard
+	Mouse    *Mouse
+
+	sim  *gt3.Sim
+	tick uint64 // Tick+1 of the current deltas
+
+	havePos            bool
+	dx, dy             float64 // Current tick's deltas
+	sx, sy             float64
+	pendDX, pendDY     float64 // Deltas accumulated for the next tick
+	pendSX, pendSY     float64
+	lastPosX, lastPosY float64
+}
+
+// NewState returns a State latching to ticks of s.
+func NewState(s *gt3.Sim) *State {
+	return &State{
+		Keyboard: NewKeyboard(s),
+		Mouse:    NewMouse(s),
+		sim:      s,
+	}
+}
+
+// Event updates the input state. State implements gt3.EventHandler.
+func (st *State) Event(e gt3.Event, when time.Time) {
+	st.Keyboard.Event(e, when)
+	st.Mouse.Event(e, when)
+	switch ev := e.(type) {
+	case gt3.CursorPosEvent:
+		if st.havePos {
+			st.pendDX += ev.X - st.lastPosX
+			st.pendDY += ev.Y - st.lastPosY
+		}
+		st.lastPosX, st.lastPosY, st.havePos = ev.X, ev.Y, true
+	case gt3.ScrollEvent:
+		st.pendSX += ev.XOff
+		st.pendSY += ev.YOff
+	case gt3.CursorEnterEvent:
+		// Don't report the jump between leaving and re-entering the window as motion.
+		st.havePos = st.havePos && ev.Entered
+	}
+}
+
+// latch moves accumulated deltas into the current tick's deltas on the first query of a tick.
+func (st *State) latch() {
+	tick := st.sim.Tick() + 1
+	if st.tick == tick {
+		return
+	}
+	st.tick = tick
+	st.dx, st.dy, st.pendDX, st.pendDY = st.pendDX, st.pendDY, 0, 0
+	st.sx, st.sy, st.pendSX, st.pendSY = st.pendSX, st.pendSY, 0, 0
+}
+
+// IsKeyDown reports whether k is held.
+func (st *State) IsKeyDown(k gt3.Key) bool {
+	return st.Keyboard.IsDown(k)
+}
+
+// WasKeyPressed reports whether k was pressed in the current tick.
+func (st *State) WasKeyPressed(k gt3.Key) bool {
+	return st.Keyboard.WasPressedThisTick(k)
+}
+
+// WasKeyReleased reports whether k was released in the current tick.
+func (st *State) WasKeyReleased(k gt3.Key) bool {
+	return st.Keyboard.WasReleasedThisTick(k)
+}
+
+// IsButtonDown reports whether b is held.
+func (st *State) IsButtonDown(b gt3.MouseButton) bool {
+	return st.Mouse.IsDown(b)
+}
+
+// WasButtonPressed reports whether b was pressed in the current tick.
+func (st *State) WasButtonPressed(b gt3.MouseButton) bool {
+	return st.Mouse.WasPressedThisTick(b)
+}
+
+// WasButtonReleased reports whether b was released in the current tick.
+func (st *State) WasButtonReleased(b gt3.MouseButton) bool {
+	return st.Mouse.WasReleasedThisTick(b)
+}
+
+// Cursor returns the cursor's last known position.
+func (st *State) Cursor() (x, y float64) {
+	return st.Mouse.X, st.Mouse.Y
+}
+
+// CursorDelta returns how far the cursor moved for the current tick.
+func (st *State) CursorDelta() (dx, dy float64) {
+	st.latch()
+	return st.dx, st.dy
+}
+
+// ScrollDelta returns the scroll offset accumulated for the current tick.
+func (st *State) ScrollDelta() (dx, dy float64) {
+	st.latch()
+	return st.sx, st.sy
+}
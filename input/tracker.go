@@ -0,0 +1,167 @@
+package input
+
+import (
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// ButtonTracker tracks the state of a set of buttons and latches presses and releases to the sim tick they're
+// delivered in. Events delivered during PreFrame, such as by polling for events, are attributed to the first sim frame
+// run after them, so edge queries made by the Frame op see each press or release in exactly one tick, even if several
+// frames run in one loop iteration. A ButtonTracker must only be used from the main goroutine.
+type ButtonTracker[B comparable] struct {
+	sim *gt3.Sim
+
+	down     map[B]bool
+	pressed  map[B]uint64 // Tick+1 of the last press
+	released map[B]uint64 // Tick+1 of the last release
+}
+
+// NewButtonTracker returns a ButtonTracker latching to ticks of s.
+func NewButtonTracker[B comparable](s *gt3.Sim) *ButtonTracker[B] {
+	return &ButtonTracker[B]{
+		sim:      s,
+		down:     make(map[B]bool),
+		pressed:  make(map[B]uint64),
+		released: make(map[B]uint64),
+	}
+}
+
+// Press records that b was pressed. Repeated presses without a release are ignored.
+func (t *ButtonTracker[B]) Press(b B) {
+	if t.down[b] {
+		return
+	}
+	t.down[b] = true
+	t.pressed[b] = t.sim.Tick() + 1
+}
+
+// Release records that b was released.
+func (t *ButtonTracker[B]) Release(b B) {
+	if !t.down[b] {
+		return
+	}
+	delete(t.down, b)
+	t.released[b] = t.sim.Tick() + 1
+}
+
+// Set presses or releases b.
+func (t *ButtonTracker[B]) Set(b B, down bool) {
+	if down {
+		t.Press(b)
+	} else {
+		t.Release(b)
+	}
+}
+
+// IsDown reports whether b is held.
+func (t *ButtonTracker[B]) IsDown(b B) bool {
+	return t.down[b]
+}
+
+// WasPressedThisTick reports whether b was pressed in the current sim tick. It remains true for the tick even if b was
+// also released in it.
+func (t *ButtonTracker[B]) WasPressedThisTick(b B) bool {
+	return t.pressed[b] == t.sim.Tick()+1
+}
+
+// WasReleasedThisTick reports whether b was released in the current sim tick.
+func (t *ButtonTracker[B]) WasReleasedThisTick(b B) bool {
+	return t.released[b] == t.sim.Tick()+1
+}
+
+// ReleaseAll releases every held button, such as when the window loses focus.
+func (t *ButtonTracker[B]) ReleaseAll() {
+	for b := range t.down {
+		t.Release(b)
+	}
+}
+
+// Keyboard tracks key state from KeyEvents. All keys are released when the window loses focus.
+type Keyboard struct {
+	*ButtonTracker[gt3.Key]
+}
+
+// NewKeyboard returns a Keyboard latching to ticks of s.
+func NewKeyboard(s *gt3.Sim) *Keyboard {
+	return &Keyboard{NewButtonTracker[gt3.Key](s)}
+}
+
+// Event updates key state. Key repeats are ignored.
+func (k *Keyboard) Event(e gt3.Event, when time.Time) {
+	switch ev := e.(type) {
+	case gt3.KeyEvent:
+		switch ev.Action {
+		case gt3.Press:
+			k.Press(ev.Key)
+		case gt3.Release:
+			k.Release(ev.Key)
+		}
+	case gt3.FocusEvent:
+		if !ev.Focused {
+			k.ReleaseAll()
+		}
+	}
+}
+
+// Mouse tracks mouse button state and cursor position from MouseEvents and CursorPosEvents.
+type Mouse struct {
+	*ButtonTracker[gt3.MouseButton]
+
+	X, Y float64
+}
+
+// NewMouse returns a Mouse latching to ticks of s.
+func NewMouse(s *gt3.Sim) *Mouse {
+	return &Mouse{ButtonTracker: NewButtonTracker[gt3.MouseButton](s)}
+}
+
+// Event updates mouse state.
+func (m *Mouse) Event(e gt3.Event, when time.Time) {
+	switch ev := e.(type) {
+	case gt3.MouseEvent:
+		m.Set(ev.Button, ev.Action != gt3.Release)
+	case gt3.CursorPosEvent:
+		m.X, m.Y = ev.X, ev.Y
+	case gt3.FocusEvent:
+		if !ev.Focused {
+			m.ReleaseAll()
+		}
+	}
+}
+
+// Gamepad tracks gamepad button state, either from GamepadButtonEvents, such as those posted by a gt3.GamepadPoller, or
+// by calling Update with the gamepad's button states from the PreFrame op.
+type Gamepad struct {
+	*ButtonTracker[int]
+
+	// Device is the device whose events are tracked. If zero, events from all devices are tracked.
+	Device gt3.DeviceID
+}
+
+// NewGamepad returns a Gamepad latching to ticks of s.
+func NewGamepad(s *gt3.Sim) *Gamepad {
+	return &Gamepad{ButtonTracker: NewButtonTracker[int](s)}
+}
+
+// Event updates button state from GamepadButtonEvents. Gamepad implements gt3.EventHandler.
+func (g *Gamepad) Event(e gt3.Event, _ time.Time) {
+	ev, ok := e.(gt3.GamepadButtonEvent)
+	if !ok || (g.Device != 0 && ev.Device != g.Device) {
+		return
+	}
+	switch ev.Action {
+	case gt3.Press:
+		g.Press(ev.Button)
+	case gt3.Release:
+		g.Release(ev.Button)
+	}
+}
+
+// Update sets the state of each button, indexed by button number.
+func (g *Gamepad) Update(buttons []bool) {
+	for i, down := range buttons {
+		g.Set(i, down)
+	}
+}
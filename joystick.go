@@ -0,0 +1,133 @@
+package gt3
+
+import "sync"
+
+// DeviceID identifies an input device. A device's ID is stable for the life of its DeviceRegistry, including across
+// disconnection and reconnection.
+type DeviceID uint64
+
+// Device describes an input device known to a DeviceRegistry.
+type Device struct {
+	ID        DeviceID
+	Name      string
+	GUID      string // May be empty if the backend doesn't report GUIDs
+	Slot      int    // Backend joystick slot, valid while Connected
+	Connected bool
+}
+
+type (
+	// JoystickConnectedEvent is posted when a joystick or gamepad is connected, including once for each device present
+	// when joystick events are first enabled.
+	JoystickConnectedEvent struct {
+		Device Device
+	}
+
+	// JoystickDisconnectedEvent is posted when a joystick or gamepad is disconnected.
+	JoystickDisconnectedEvent struct {
+		Device Device
+	}
+
+	// GamepadButtonEvent is posted when a gamepad button is pressed or released.
+	GamepadButtonEvent struct {
+		Device DeviceID
+		Button int
+		Action Action
+	}
+
+	// GamepadAxisEvent is posted when a gamepad axis moves. Value is in [-1, 1].
+	GamepadAxisEvent struct {
+		Device DeviceID
+		Axis   int
+		Value  float64
+	}
+)
+
+func (JoystickConnectedEvent) isEvent()    {}
+func (JoystickDisconnectedEvent) isEvent() {}
+func (GamepadButtonEvent) isEvent()        {}
+func (GamepadAxisEvent) isEvent()          {}
+
+// DeviceRegistry assigns stable IDs to joystick devices. When a device connects, it's matched to a disconnected device
+// with the same GUID, or the same name if it has no GUID, and given that device's ID, so that a player's controller
+// assignment can be restored after the device is unplugged and reconnected. The zero value is an empty DeviceRegistry
+// ready for use. A DeviceRegistry may be used from any goroutine.
+type DeviceRegistry struct {
+	mu      sync.Mutex
+	devices []*Device
+	lastID  DeviceID
+}
+
+// Connect records that a device was connected in the given slot and returns it.
+func (r *DeviceRegistry) Connect(slot int, name, guid string) Device {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.devices {
+		if d.Connected && d.Slot == slot {
+			// Missed a disconnect
+			d.Connected = false
+		}
+	}
+
+	for _, d := range r.devices {
+		if d.Connected || d.Name != name || d.GUID != guid {
+			continue
+		}
+		d.Slot, d.Connected = slot, true
+		return *d
+	}
+
+	r.lastID++
+	d := &Device{ID: r.lastID, Name: name, GUID: guid, Slot: slot, Connected: true}
+	r.devices = append(r.devices, d)
+	return *d
+}
+
+// Disconnect records that the device in the given slot was disconnected and returns it. If no device is connected in
+// the slot, ok is false.
+func (r *DeviceRegistry) Disconnect(slot int) (dev Device, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.devices {
+		if d.Connected && d.Slot == slot {
+			d.Connected = false
+			return *d, true
+		}
+	}
+	return Device{}, false
+}
+
+// Device returns the device with the given ID.
+func (r *DeviceRegistry) Device(id DeviceID) (dev Device, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.devices {
+		if d.ID == id {
+			return *d, true
+		}
+	}
+	return Device{}, false
+}
+
+// Slot returns the device connected in the given slot.
+func (r *DeviceRegistry) Slot(slot int) (dev Device, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.devices {
+		if d.Connected && d.Slot == slot {
+			return *d, true
+		}
+	}
+	return Device{}, false
+}
+
+// Devices returns all known devices, connected or not, in the order they were first connected.
+func (r *DeviceRegistry) Devices() []Device {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	devs := make([]Device, len(r.devices))
+	for i, d := range r.devices {
+		devs[i] = *d
+	}
+	return devs
+}
@@ -0,0 +1,39 @@
+//go:build !android && !ios
+
+package gt3
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func init() {
+	// GLFW and most GL drivers must be used from the main OS thread. Locking here, while package initialization runs on
+	// the main thread, keeps the main goroutine there for Main.
+	runtime.LockOSThread()
+}
+
+// Main initializes GLFW, creates a Sim, passes it to setup, and runs it, terminating GLFW once the Sim stops. The Sim
+// simulates at DefaultFPS and doesn't limit rendering; setup may change either with SetFPS and SetRenderFPS. Main
+// returns nil once the Sim is stopped, or the error returned by glfw.Init or setup.
+//
+// Main must be called from the main goroutine, normally directly from func main. Since gt3 locks the main goroutine to
+// the main OS thread during initialization, programs using Main don't need to call runtime.LockOSThread themselves.
+func Main(setup func(*Sim) error) error {
+	if err := glfw.Init(); err != nil {
+		return err
+	}
+	defer glfw.Terminate()
+
+	s := NewSim(DefaultFPS, 0, nil)
+	if err := setup(s); err != nil {
+		return err
+	}
+
+	if err := s.Run(); !errors.Is(err, ErrStopped) {
+		return err
+	}
+	return nil
+}
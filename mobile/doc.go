@@ -0,0 +1,22 @@
+// Package mobile runs gt3 Sims in Android and iOS apps built with golang.org/x/mobile. Events from the app's event
+// channel are translated into gt3 events on a Window standing in for the app's screen, and the Sim is stepped from the
+// app's paint events instead of running its own loop, so that Op and EventHandler code written for gt3 runs unchanged
+// on mobile.
+//
+// Events are translated as follows:
+//
+//   - Lifecycle changes post an IconifyEvent when the app becomes visible or invisible, a FocusEvent when it gains or
+//     loses focus, and a CloseEvent when it's destroyed, after which the Sim is stopped.
+//   - Size changes post a FramebufferSizeEvent, a ResizeEvent, and a ContentScaleEvent. Sizes are in pixels, and the
+//     content scale is the screen's density relative to 160 DPI.
+//   - Touches post TouchEvents and, with EmulateMouse, mouse events for the first finger down.
+//   - Hardware keys post KeyEvents, and CharEvents for keys that produce text.
+//
+// The Sim is only stepped while the app is visible, so time spent in the background is caught up on return unless
+// the Sim is paused with Sim.PauseOnIconify or limited with Sim.SetMaxCatchUp.
+//
+// Rendering must use golang.org/x/mobile/gl through the Window's DrawContext, since GLFW and go-gl aren't available on
+// mobile. The Sim's Render op draws the frame, which is published once the step that rendered it returns.
+//
+// The package is only built for Android and iOS.
+package mobile
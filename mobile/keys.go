@@ -0,0 +1,127 @@
+//go:build android || ios
+
+package mobile
+
+import (
+	"golang.org/x/mobile/event/key"
+
+	"go.spiff.io/gt3"
+)
+
+// keys maps gomobile key codes to gt3 Keys.
+var keys = map[key.Code]gt3.Key{
+	key.CodeA:                  gt3.KeyA,
+	key.CodeB:                  gt3.KeyB,
+	key.CodeC:                  gt3.KeyC,
+	key.CodeD:                  gt3.KeyD,
+	key.CodeE:                  gt3.KeyE,
+	key.CodeF:                  gt3.KeyF,
+	key.CodeG:                  gt3.KeyG,
+	key.CodeH:                  gt3.KeyH,
+	key.CodeI:                  gt3.KeyI,
+	key.CodeJ:                  gt3.KeyJ,
+	key.CodeK:                  gt3.KeyK,
+	key.CodeL:                  gt3.KeyL,
+	key.CodeM:                  gt3.KeyM,
+	key.CodeN:                  gt3.KeyN,
+	key.CodeO:                  gt3.KeyO,
+	key.CodeP:                  gt3.KeyP,
+	key.CodeQ:                  gt3.KeyQ,
+	key.CodeR:                  gt3.KeyR,
+	key.CodeS:                  gt3.KeyS,
+	key.CodeT:                  gt3.KeyT,
+	key.CodeU:                  gt3.KeyU,
+	key.CodeV:                  gt3.KeyV,
+	key.CodeW:                  gt3.KeyW,
+	key.CodeX:                  gt3.KeyX,
+	key.CodeY:                  gt3.KeyY,
+	key.CodeZ:                  gt3.KeyZ,
+	key.Code1:                  gt3.Key1,
+	key.Code2:                  gt3.Key2,
+	key.Code3:                  gt3.Key3,
+	key.Code4:                  gt3.Key4,
+	key.Code5:                  gt3.Key5,
+	key.Code6:                  gt3.Key6,
+	key.Code7:                  gt3.Key7,
+	key.Code8:                  gt3.Key8,
+	key.Code9:                  gt3.Key9,
+	key.Code0:                  gt3.Key0,
+	key.CodeReturnEnter:        gt3.KeyEnter,
+	key.CodeEscape:             gt3.KeyEscape,
+	key.CodeDeleteBackspace:    gt3.KeyBackspace,
+	key.CodeTab:                gt3.KeyTab,
+	key.CodeSpacebar:           gt3.KeySpace,
+	key.CodeHyphenMinus:        gt3.KeyMinus,
+	key.CodeEqualSign:          gt3.KeyEqual,
+	key.CodeLeftSquareBracket:  gt3.KeyLeftBracket,
+	key.CodeRightSquareBracket: gt3.KeyRightBracket,
+	key.CodeBackslash:          gt3.KeyBackslash,
+	key.CodeSemicolon:          gt3.KeySemicolon,
+	key.CodeApostrophe:         gt3.KeyApostrophe,
+	key.CodeGraveAccent:        gt3.KeyGraveAccent,
+	key.CodeComma:              gt3.KeyComma,
+	key.CodeFullStop:           gt3.KeyPeriod,
+	key.CodeSlash:              gt3.KeySlash,
+	key.CodeCapsLock:           gt3.KeyCapsLock,
+	key.CodeF1:                 gt3.KeyF1,
+	key.CodeF2:                 gt3.KeyF2,
+	key.CodeF3:                 gt3.KeyF3,
+	key.CodeF4:                 gt3.KeyF4,
+	key.CodeF5:                 gt3.KeyF5,
+	key.CodeF6:                 gt3.KeyF6,
+	key.CodeF7:                 gt3.KeyF7,
+	key.CodeF8:                 gt3.KeyF8,
+	key.CodeF9:                 gt3.KeyF9,
+	key.CodeF10:                gt3.KeyF10,
+	key.CodeF11:                gt3.KeyF11,
+	key.CodeF12:                gt3.KeyF12,
+	key.CodeF13:                gt3.KeyF13,
+	key.CodeF14:                gt3.KeyF14,
+	key.CodeF15:                gt3.KeyF15,
+	key.CodeF16:                gt3.KeyF16,
+	key.CodeF17:                gt3.KeyF17,
+	key.CodeF18:                gt3.KeyF18,
+	key.CodeF19:                gt3.KeyF19,
+	key.CodeF20:                gt3.KeyF20,
+	key.CodeF21:                gt3.KeyF21,
+	key.CodeF22:                gt3.KeyF22,
+	key.CodeF23:                gt3.KeyF23,
+	key.CodeF24:                gt3.KeyF24,
+	key.CodePause:              gt3.KeyPause,
+	key.CodeInsert:             gt3.KeyInsert,
+	key.CodeHome:               gt3.KeyHome,
+	key.CodePageUp:             gt3.KeyPageUp,
+	key.CodeDeleteForward:      gt3.KeyDelete,
+	key.CodeEnd:                gt3.KeyEnd,
+	key.CodePageDown:           gt3.KeyPageDown,
+	key.CodeRightArrow:         gt3.KeyRight,
+	key.CodeLeftArrow:          gt3.KeyLeft,
+	key.CodeDownArrow:          gt3.KeyDown,
+	key.CodeUpArrow:            gt3.KeyUp,
+	key.CodeKeypadNumLock:      gt3.KeyNumLock,
+	key.CodeKeypadSlash:        gt3.KeyKPDivide,
+	key.CodeKeypadAsterisk:     gt3.KeyKPMultiply,
+	key.CodeKeypadHyphenMinus:  gt3.KeyKPSubtract,
+	key.CodeKeypadPlusSign:     gt3.KeyKPAdd,
+	key.CodeKeypadEnter:        gt3.KeyKPEnter,
+	key.CodeKeypad1:            gt3.KeyKP1,
+	key.CodeKeypad2:            gt3.KeyKP2,
+	key.CodeKeypad3:            gt3.KeyKP3,
+	key.CodeKeypad4:            gt3.KeyKP4,
+	key.CodeKeypad5:            gt3.KeyKP5,
+	key.CodeKeypad6:            gt3.KeyKP6,
+	key.CodeKeypad7:            gt3.KeyKP7,
+	key.CodeKeypad8:            gt3.KeyKP8,
+	key.CodeKeypad9:            gt3.KeyKP9,
+	key.CodeKeypad0:            gt3.KeyKP0,
+	key.CodeKeypadFullStop:     gt3.KeyKPDecimal,
+	key.CodeKeypadEqualSign:    gt3.KeyKPEqual,
+	key.CodeLeftControl:        gt3.KeyLeftControl,
+	key.CodeLeftShift:          gt3.KeyLeftShift,
+	key.CodeLeftAlt:            gt3.KeyLeftAlt,
+	key.CodeLeftGUI:            gt3.KeyLeftSuper,
+	key.CodeRightControl:       gt3.KeyRightControl,
+	key.CodeRightShift:         gt3.KeyRightShift,
+	key.CodeRightAlt:           gt3.KeyRightAlt,
+	key.CodeRightGUI:           gt3.KeyRightSuper,
+}
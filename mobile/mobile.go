@@ -0,0 +1,266 @@
+//go:build android || ios
+
+package mobile
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/mobile/app"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/paint"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/event/touch"
+	"golang.org/x/mobile/gl"
+
+	"go.spiff.io/gt3"
+)
+
+// baseDPI is the screen density with a content scale of 1, Android's baseline density.
+const baseDPI = 160
+
+// Window is the native window of a gt3.Window standing in for an app's screen. It implements gt3.StateWindow.
+type Window struct {
+	app   app.App
+	glctx gl.Context
+	state gt3.WindowState
+}
+
+var _ gt3.StateWindow = (*Window)(nil)
+
+// NewWindow returns a gt3.Window for a's screen. Its native window is a *Window.
+func NewWindow(a app.App) *gt3.Window {
+	return gt3.WrapWindow(&Window{app: a})
+}
+
+// App returns the app the window belongs to.
+func (w *Window) App() app.App {
+	return w.app
+}
+
+// DrawContext returns the app's GL context, or nil while the app isn't visible. It must only be used from the Sim's
+// main goroutine.
+func (w *Window) DrawContext() gl.Context {
+	return w.glctx
+}
+
+// WindowState returns the window's last known state.
+func (w *Window) WindowState() gt3.WindowState {
+	return w.state
+}
+
+type config struct {
+	emulateMouse bool
+}
+
+// Option configures Run.
+type Option func(*config)
+
+// EmulateMouse sets whether the first finger down also posts a left MouseEvent and CursorPosEvents as it moves, so
+// that handlers written for a mouse work on a touch screen. Mouse emulation is off by default.
+func EmulateMouse(enabled bool) Option {
+	return func(c *config) { c.emulateMouse = enabled }
+}
+
+// Main runs a gomobile app driving a Sim, as gt3.Main does with GLFW. It creates a Sim simulating at gt3.DefaultFPS
+// and a Window for the app's screen, passes both to setup, and then runs the Sim with Run. Main must be called from
+// func main in place of app.Main. It returns nil once the Sim is stopped, or the error returned by setup.
+func Main(setup func(s *gt3.Sim, w *gt3.Window) error, opts ...Option) error {
+	var err error
+	app.Main(func(a app.App) {
+		s := gt3.NewSim(gt3.DefaultFPS, 0, nil)
+		w := NewWindow(a)
+		if err = setup(s, w); err != nil {
+			return
+		}
+		if err = Run(a, s, w, opts...); errors.Is(err, gt3.ErrStopped) {
+			err = nil
+		}
+	})
+	return err
+}
+
+// Run drives s from a's events, dispatching translated events to w's Dispatcher and stepping s with each paint event
+// while the app is visible. w must have been created by NewWindow for a. Run must be called from the goroutine
+// receiving a's events, normally the function passed to app.Main, which becomes the Sim's main goroutine. It returns
+// the error returned by s.Step once the Sim stops, including when the app is destroyed.
+func Run(a app.App, s *gt3.Sim, w *gt3.Window, opts ...Option) error {
+	var conf config
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	d := &driver{
+		config: conf,
+		app:    a,
+		sim:    s,
+		win:    w,
+		native: w.Native().(*Window),
+	}
+	w.Events().SetSim(s)
+	s.Start()
+	for e := range a.Events() {
+		if err := d.event(a.Filter(e)); err != nil {
+			return err
+		}
+	}
+	return gt3.ErrStopped
+}
+
+// driver translates an app's events for Run.
+type driver struct {
+	config
+	app    app.App
+	sim    *gt3.Sim
+	win    *gt3.Window
+	native *Window
+
+	mouseTouch touch.Sequence // Touch emulating the mouse, if mouseDown
+	mouseDown  bool
+}
+
+func (d *driver) post(e gt3.Event, when time.Time) {
+	d.win.Events().Event(e, when)
+}
+
+func (d *driver) event(e interface{}) error {
+	when := time.Now()
+	switch e := e.(type) {
+	case lifecycle.Event:
+		return d.lifecycle(e, when)
+	case size.Event:
+		d.size(e, when)
+	case touch.Event:
+		d.touch(e, when)
+	case key.Event:
+		d.key(e, when)
+	case paint.Event:
+		return d.paint(e)
+	}
+	return nil
+}
+
+func (d *driver) lifecycle(e lifecycle.Event, when time.Time) error {
+	state := &d.native.state
+	switch e.Crosses(lifecycle.StageVisible) {
+	case lifecycle.CrossOn:
+		d.native.glctx, _ = e.DrawContext.(gl.Context)
+		state.Iconified = false
+		d.post(gt3.IconifyEvent{Window: d.win, Iconified: false}, when)
+		d.app.Send(paint.Event{})
+	case lifecycle.CrossOff:
+		d.native.glctx = nil
+		state.Iconified = true
+		d.post(gt3.IconifyEvent{Window: d.win, Iconified: true}, when)
+	}
+	switch e.Crosses(lifecycle.StageFocused) {
+	case lifecycle.CrossOn:
+		state.Focused = true
+		d.post(gt3.FocusEvent{Window: d.win, Focused: true}, when)
+	case lifecycle.CrossOff:
+		state.Focused = false
+		d.post(gt3.FocusEvent{Window: d.win, Focused: false}, when)
+	}
+	if e.Crosses(lifecycle.StageAlive) == lifecycle.CrossOff {
+		d.post(gt3.CloseEvent{Window: d.win}, when)
+		d.sim.Stop()
+		return d.sim.Step()
+	}
+	return nil
+}
+
+func (d *driver) size(e size.Event, when time.Time) {
+	scale := float64(e.PixelsPerPt) * 72 / baseDPI
+	state := &d.native.state
+	state.Width, state.Height = e.WidthPx, e.HeightPx
+	state.FramebufferWidth, state.FramebufferHeight = e.WidthPx, e.HeightPx
+	state.ContentScale = scale
+	d.post(gt3.ResizeEvent{Window: d.win, Width: e.WidthPx, Height: e.HeightPx}, when)
+	d.post(gt3.FramebufferSizeEvent{Window: d.win, Width: e.WidthPx, Height: e.HeightPx}, when)
+	d.post(gt3.ContentScaleEvent{Window: d.win, X: scale, Y: scale}, when)
+}
+
+var touchPhases = [...]gt3.TouchPhase{
+	touch.TypeBegin: gt3.TouchBegin,
+	touch.TypeMove:  gt3.TouchMove,
+	touch.TypeEnd:   gt3.TouchEnd,
+}
+
+func (d *driver) touch(e touch.Event, when time.Time) {
+	if int(e.Type) >= len(touchPhases) {
+		return
+	}
+	x, y := float64(e.X), float64(e.Y)
+	d.post(gt3.TouchEvent{Window: d.win, ID: int64(e.Sequence), Phase: touchPhases[e.Type], X: x, Y: y}, when)
+	if !d.emulateMouse {
+		return
+	}
+
+	switch {
+	case e.Type == touch.TypeBegin && !d.mouseDown:
+		d.mouseTouch, d.mouseDown = e.Sequence, true
+		d.post(gt3.CursorPosEvent{Window: d.win, X: x, Y: y}, when)
+		d.post(gt3.MouseEvent{Window: d.win, Button: gt3.MouseButtonLeft, Action: gt3.Press}, when)
+	case !d.mouseDown || e.Sequence != d.mouseTouch:
+	case e.Type == touch.TypeMove:
+		d.post(gt3.CursorPosEvent{Window: d.win, X: x, Y: y}, when)
+	case e.Type == touch.TypeEnd:
+		d.mouseDown = false
+		d.post(gt3.CursorPosEvent{Window: d.win, X: x, Y: y}, when)
+		d.post(gt3.MouseEvent{Window: d.win, Button: gt3.MouseButtonLeft, Action: gt3.Release}, when)
+	}
+}
+
+func (d *driver) key(e key.Event, when time.Time) {
+	k, ok := keys[e.Code]
+	if !ok {
+		k = gt3.KeyUnknown
+	}
+	var action gt3.Action
+	switch e.Direction {
+	case key.DirPress:
+		action = gt3.Press
+	case key.DirRelease:
+		action = gt3.Release
+	default:
+		action = gt3.Repeat
+	}
+	d.post(gt3.KeyEvent{Window: d.win, Key: k, Code: int(e.Code), Action: action, Mods: mods(e.Modifiers)}, when)
+	if e.Rune >= 0 && action != gt3.Release {
+		d.post(gt3.CharEvent{Window: d.win, Char: e.Rune}, when)
+	}
+}
+
+func mods(m key.Modifiers) (mods gt3.ModifierKey) {
+	if m&key.ModShift != 0 {
+		mods |= gt3.ModShift
+	}
+	if m&key.ModControl != 0 {
+		mods |= gt3.ModControl
+	}
+	if m&key.ModAlt != 0 {
+		mods |= gt3.ModAlt
+	}
+	if m&key.ModMeta != 0 {
+		mods |= gt3.ModSuper
+	}
+	return mods
+}
+
+// paint steps the Sim and publishes the frame if it rendered one, then requests another paint event. Paint events
+// sent by the system rather than by the driver are ignored, so that they don't add up to more than one step per
+// frame.
+func (d *driver) paint(e paint.Event) error {
+	if e.External || d.native.glctx == nil {
+		return nil
+	}
+	renders := d.sim.RenderCount()
+	if err := d.sim.Step(); err != nil {
+		return err
+	}
+	if d.sim.RenderCount() != renders {
+		d.app.Publish()
+	}
+	d.app.Send(paint.Event{})
+	return nil
+}
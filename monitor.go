@@ -0,0 +1,79 @@
+package gt3
+
+// Monitor describes a connected monitor. Positions and sizes are in screen coordinates on the virtual desktop.
+type Monitor struct {
+	Name          string
+	X, Y          int
+	Width, Height int
+	RefreshRate   int
+	// DPI is the monitor's approximate pixel density, derived from its reported physical size. It is zero if the
+	// physical size is unknown.
+	DPI float64
+
+	native interface{} // *glfw.Monitor for GLFW monitors
+}
+
+// Span is a rectangle on the virtual desktop covering several monitors.
+type Span struct {
+	X, Y          int
+	Width, Height int
+	Monitors      []Monitor
+	// RefreshRate is the lowest refresh rate of the spanned monitors, which is the highest rate that can be presented
+	// without tearing on all of them.
+	RefreshRate int
+}
+
+// SpanMonitors returns the smallest Span covering the given monitors.
+func SpanMonitors(monitors ...Monitor) Span {
+	var s Span
+	if len(monitors) == 0 {
+		return s
+	}
+	x0, y0 := monitors[0].X, monitors[0].Y
+	x1, y1 := x0+monitors[0].Width, y0+monitors[0].Height
+	rate := monitors[0].RefreshRate
+	for _, m := range monitors[1:] {
+		x0, y0 = minInt(x0, m.X), minInt(y0, m.Y)
+		x1, y1 = maxInt(x1, m.X+m.Width), maxInt(y1, m.Y+m.Height)
+		if m.RefreshRate > 0 && (rate == 0 || m.RefreshRate < rate) {
+			rate = m.RefreshRate
+		}
+	}
+	return Span{
+		X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0,
+		Monitors:    append([]Monitor(nil), monitors...),
+		RefreshRate: rate,
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Viewport returns the viewport, in framebuffer pixels with a bottom-left origin as used by glViewport, covering the
+// i-th spanned monitor in a window covering the span with the given framebuffer size. Gaps between monitors of
+// different sizes are not covered by any viewport.
+func (s Span) Viewport(i, fbWidth, fbHeight int) (x, y, width, height int) {
+	if i < 0 || i >= len(s.Monitors) || s.Width == 0 || s.Height == 0 {
+		return 0, 0, 0, 0
+	}
+	m := s.Monitors[i]
+	sx := float64(fbWidth) / float64(s.Width)
+	sy := float64(fbHeight) / float64(s.Height)
+	x = int(float64(m.X-s.X) * sx)
+	width = int(float64(m.X-s.X+m.Width)*sx) - x
+	top := int(float64(m.Y-s.Y) * sy)
+	height = int(float64(m.Y-s.Y+m.Height)*sy) - top
+	y = fbHeight - top - height
+	return x, y, width, height
+}
@@ -0,0 +1,47 @@
+//go:build !android && !ios
+
+package gt3
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// GLFW returns the GLFW monitor for m.
+func (m Monitor) GLFW() *glfw.Monitor {
+	gm, _ := m.native.(*glfw.Monitor)
+	return gm
+}
+
+// Monitors returns all connected monitors, with the primary monitor first. It must be called from the main goroutine.
+func Monitors() []Monitor {
+	var monitors []Monitor
+	for _, gm := range glfw.GetMonitors() {
+		mode := gm.GetVideoMode()
+		if mode == nil {
+			continue
+		}
+		m := Monitor{Name: gm.GetName(), Width: mode.Width, Height: mode.Height, RefreshRate: mode.RefreshRate, native: gm}
+		m.X, m.Y = gm.GetPos()
+		if wmm, _ := gm.GetPhysicalSize(); wmm > 0 {
+			m.DPI = float64(mode.Width) / (float64(wmm) / 25.4)
+		}
+		monitors = append(monitors, m)
+	}
+	return monitors
+}
+
+// NewSpanningWindow creates an undecorated window covering span, for simulators and video walls that render across
+// several monitors. The window doesn't iconify on focus loss. Since it isn't fullscreen on any one monitor, its swap
+// interval is synchronized to only one of them; render at the span's RefreshRate to avoid presenting faster than the
+// slowest monitor. NewSpanningWindow must be called from the main goroutine.
+func NewSpanningWindow(title string, span Span, opts ...WindowOption) (*Window, error) {
+	opts = append([]WindowOption{func(c *WindowConfig) {
+		c.Hint(glfw.Decorated, glfw.False)
+		c.Hint(glfw.AutoIconify, glfw.False)
+		c.Hint(glfw.Floating, glfw.True)
+	}}, opts...)
+	w, err := NewWindow(title, span.Width, span.Height, opts...)
+	if err != nil {
+		return nil, err
+	}
+	w.GLFW().SetPos(span.X, span.Y)
+	return w, nil
+}
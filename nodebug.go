@@ -0,0 +1,5 @@
+//go:build !gt3debug
+
+package gt3
+
+const debugChecks = false
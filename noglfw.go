@@ -0,0 +1,26 @@
+//go:build android || ios
+
+package gt3
+
+import "time"
+
+// GLFW doesn't build for Android or iOS, so windows there come from other backends, such as go.spiff.io/gt3/mobile.
+
+func releaseNative(w *Window) {}
+
+func nativeState(w *Window) (WindowState, bool) { return WindowState{}, false }
+
+// monotonicClock is the default Clock where GLFW is unavailable, reading the system's monotonic clock.
+type monotonicClock struct {
+	start time.Time
+}
+
+func (c monotonicClock) Now() float64  { return time.Since(c.start).Seconds() }
+func (monotonicClock) Wall() time.Time { return time.Now() }
+
+func defaultClock() Clock { return monotonicClock{start: time.Now()} }
+
+func resetClock(c Clock) {}
+
+// Monitors returns nil, since monitors can't be enumerated without GLFW.
+func Monitors() []Monitor { return nil }
@@ -0,0 +1,113 @@
+package gt3
+
+import (
+	"strconv"
+	"time"
+)
+
+// Phase identifies the part of a Sim loop iteration that an op runs in.
+type Phase int
+
+// Phases.
+const (
+	PhasePreFrame Phase = iota
+	PhaseSched          // Ops scheduled with Sched or Sync
+	PhaseTick           // TickBus subscriptions
+	PhaseFrame
+	PhasePreRender
+	PhaseRender
+	PhasePostRender
+	PhaseStop // OnStop ops
+)
+
+var phaseNames = [...]string{
+	PhasePreFrame:   "preframe",
+	PhaseSched:      "sched",
+	PhaseTick:       "tick",
+	PhaseFrame:      "frame",
+	PhasePreRender:  "prerender",
+	PhaseRender:     "render",
+	PhasePostRender: "postrender",
+	PhaseStop:       "stop",
+}
+
+func (p Phase) String() string {
+	if p >= 0 && int(p) < len(phaseNames) {
+		return phaseNames[p]
+	}
+	return "Phase(" + strconv.Itoa(int(p)) + ")"
+}
+
+// FrameID identifies a single op invocation within a Sim: the sim tick it ran during and the phase it ran in. Logs and
+// traces emitted by different ops during the same frame share a Tick.
+type FrameID struct {
+	Tick  uint64
+	Phase Phase
+}
+
+func (id FrameID) String() string {
+	return strconv.FormatUint(id.Tick, 10) + ":" + id.Phase.String()
+}
+
+// OpContext describes an op invocation. Step, FrameTime, and When are the same values passed to Op.Do.
+type OpContext struct {
+	Frame     FrameID
+	Step      float64
+	FrameTime float64
+	When      time.Time
+
+	// Window is the window being rendered to when the Sim manages render contexts. It is nil otherwise.
+	Window *Window
+
+	// Alpha is how far render time is between the previous and current sim ticks, from 0 to 1, for interpolating
+	// between the states they left. It is set for PreRender, Render, and PostRender ops and is zero otherwise.
+	Alpha float64
+
+	// Substep is the index of the current substep when run by Substeps. It is zero otherwise.
+	Substep int
+
+	// Arena is the Sim's frame arena for transient allocations. It is nil when an op is called through Do.
+	Arena *Arena
+
+	sim *Sim
+}
+
+// ContextOp is implemented by ops that want to receive an OpContext. When an Op run by a Sim implements ContextOp,
+// DoContext is called instead of Do.
+type ContextOp interface {
+	DoContext(ctx OpContext)
+}
+
+// ContextOpFn is a function implementing both Op and ContextOp. When called through Do, the OpContext's Frame is zero.
+type ContextOpFn func(ctx OpContext)
+
+func (fn ContextOpFn) DoContext(ctx OpContext) { fn(ctx) }
+
+func (fn ContextOpFn) Do(step, frameTime float64, when time.Time) {
+	fn(OpContext{Step: step, FrameTime: frameTime, When: when})
+}
+
+// RunOp runs op with ctx, calling DoContext if op is a ContextOp and Do otherwise. It does nothing if op is nil. RunOp
+// is useful for ops that wrap other ops.
+func RunOp(op Op, ctx OpContext) {
+	if op == nil {
+		return
+	}
+	if cop, ok := op.(ContextOp); ok {
+		cop.DoContext(ctx)
+		return
+	}
+	op.Do(ctx.Step, ctx.FrameTime, ctx.When)
+}
+
+// opContext returns an OpContext for an op run in the given phase during the current tick.
+func (s *Sim) opContext(phase Phase, hz, ft float64, rt time.Time) OpContext {
+	return OpContext{
+		Frame:     FrameID{Tick: s.Tick(), Phase: phase},
+		Step:      hz,
+		FrameTime: ft,
+		When:      rt,
+		Arena:     &s.arena,
+		sim:       s,
+	}
+}
@@ -0,0 +1,61 @@
+package gt3
+
+import "time"
+
+// Pause reasons. A Sim is paused while any reason is set.
+const (
+	pauseUser = 1 << iota
+	pauseIconify
+)
+
+// Pause stops simulation time from advancing. While paused, no sim frames run, but PreFrame and render ops continue to
+// run. Pause must be called from the main goroutine.
+func (s *Sim) Pause() {
+	s.setPause(pauseUser, true)
+}
+
+// Resume resumes simulation time after Pause. Time spent paused is skipped rather than simulated, so resuming doesn't
+// cause a burst of catch-up frames. Resume must be called from the main goroutine.
+func (s *Sim) Resume() {
+	s.setPause(pauseUser, false)
+}
+
+// Paused reports whether simulation time is paused, either by Pause or by PauseOnIconify.
+func (s *Sim) Paused() bool {
+	return s.pause != 0
+}
+
+func (s *Sim) setPause(reason int, paused bool) {
+	was := s.pause
+	if paused {
+		s.pause |= reason
+	} else {
+		s.pause &^= reason
+	}
+
+	switch {
+	case was == 0 && s.pause != 0:
+		s.pausedAt = s.Now()
+	case was != 0 && s.pause == 0:
+		// Shift the timer base past the pause. Times measured against the timer shift with it.
+		skip := s.Now() - s.pausedAt
+		s.baseTime += skip
+		s.renderTime -= skip
+		s.nextDrift -= skip
+	}
+}
+
+// PauseOnIconify returns an EventHandler that pauses the Sim while a window is iconified and resumes it when the
+// window is restored, passing all events on to next. Pausing on iconify is independent of Pause and Resume: the Sim
+// stays paused until both allow it to run. Render FPS is not changed; throttle it separately if desired. The
+// returned handler must receive events on the main goroutine.
+func (s *Sim) PauseOnIconify(next EventHandler) EventHandler {
+	return EventHandlerFn(func(e Event, when time.Time) {
+		if ev, ok := e.(IconifyEvent); ok {
+			s.setPause(pauseIconify, ev.Iconified)
+		}
+		if next != nil {
+			next.Event(e, when)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package gt3
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPause(t *testing.T) {
+	const fps = 64
+
+	s, step := newTestSim(t, fps)
+	iconify := s.PauseOnIconify(nil)
+	setIconified := func(iconified bool) { iconify.Event(IconifyEvent{Iconified: iconified}, time.Time{}) }
+	s.Start()
+
+	tests := []struct {
+		name    string
+		before  func() // Called before advancing the clock
+		advance float64
+		paused  bool
+		ticks   uint64
+	}{
+		{"Run", nil, 2.0 / fps, false, 2},
+		{"Pause", s.Pause, 5.0 / fps, true, 2},
+		{"Iconify", func() { setIconified(true) }, 1.0 / fps, true, 2},
+		{"ResumeIconified", s.Resume, 1.0 / fps, true, 2},
+		{"PauseIconified", s.Pause, 1.0 / fps, true, 2},
+		{"Restore", func() { setIconified(false) }, 1.0 / fps, true, 2},
+		{"Resume", s.Resume, 0, false, 2}, // Time spent paused is skipped
+		{"RunAfterPause", nil, 1.0 / fps, false, 3},
+		{"IconifyOnly", func() { setIconified(true) }, 3.0 / fps, true, 3},
+		{"RestoreOnly", func() { setIconified(false) }, 1.0 / fps, false, 4},
+	}
+	for _, tt := range tests {
+		if tt.before != nil {
+			tt.before()
+		}
+		step(tt.advance)
+		if got := s.Paused(); got != tt.paused {
+			t.Errorf("%s: Paused() = %t; want %t", tt.name, got, tt.paused)
+		}
+		if got := s.Tick(); got != tt.ticks {
+			t.Errorf("%s: Tick() = %d; want %d", tt.name, got, tt.ticks)
+		}
+	}
+}
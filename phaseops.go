@@ -0,0 +1,83 @@
+package gt3
+
+import "sort"
+
+// OpID identifies an op added to one of a Sim's phase op lists.
+type OpID uint64
+
+type phaseOp struct {
+	id       OpID
+	priority int
+	op       Op
+}
+
+// AddOp adds op to the list of ops run in phase, so that subsystems such as input, physics, and audio can register
+// their ops independently. Phases with op lists are PhasePreFrame, PhaseFrame, PhasePreRender, PhaseRender, and
+// PhasePostRender; AddOp panics for other phases.
+//
+// Ops in a phase run in order of descending priority, and in the order they were added for equal priorities. The
+// phase's op field, such as Frame, runs as though it had priority 0 and was added before every other op. AddOp must be
+// called from the main goroutine; ops added while a phase is running first run the next time the phase runs.
+func (s *Sim) AddOp(phase Phase, priority int, op Op) OpID {
+	switch phase {
+	case PhasePreFrame, PhaseFrame, PhasePreRender, PhaseRender, PhasePostRender:
+	default:
+		panic("gt3: no op list for phase " + phase.String())
+	}
+
+	s.lastOpID++
+	ops := s.phaseOps[phase]
+	i := sort.Search(len(ops), func(i int) bool { return ops[i].priority < priority })
+	list := make([]phaseOp, 0, len(ops)+1)
+	list = append(list, ops[:i]...)
+	list = append(list, phaseOp{s.lastOpID, priority, op})
+	s.phaseOps[phase] = append(list, ops[i:]...)
+	return s.lastOpID
+}
+
+// AddPreFrameOp adds op to the PreFrame phase's op list, as by AddOp.
+func (s *Sim) AddPreFrameOp(priority int, op Op) OpID { return s.AddOp(PhasePreFrame, priority, op) }
+
+// AddFrameOp adds op to the Frame phase's op list, as by AddOp.
+func (s *Sim) AddFrameOp(priority int, op Op) OpID { return s.AddOp(PhaseFrame, priority, op) }
+
+// AddPreRenderOp adds op to the PreRender phase's op list, as by AddOp.
+func (s *Sim) AddPreRenderOp(priority int, op Op) OpID { return s.AddOp(PhasePreRender, priority, op) }
+
+// AddRenderOp adds op to the Render phase's op list, as by AddOp.
+func (s *Sim) AddRenderOp(priority int, op Op) OpID { return s.AddOp(PhaseRender, priority, op) }
+
+// AddPostRenderOp adds op to the PostRender phase's op list, as by AddOp.
+func (s *Sim) AddPostRenderOp(priority int, op Op) OpID {
+	return s.AddOp(PhasePostRender, priority, op)
+}
+
+// RemoveOp removes an op added by AddOp. It reports whether the op was found. RemoveOp must be called from the main
+// goroutine; an op removed while its phase is running may still run that time.
+func (s *Sim) RemoveOp(id OpID) bool {
+	for phase, ops := range s.phaseOps {
+		for i, op := range ops {
+			if op.id != id {
+				continue
+			}
+			list := make([]phaseOp, 0, len(ops)-1)
+			list = append(list, ops[:i]...)
+			s.phaseOps[phase] = append(list, ops[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// runPhase runs a phase's op field and op list with ctx.
+func (s *Sim) runPhase(field Op, ctx OpContext) {
+	ops := s.phaseOps[ctx.Frame.Phase]
+	i := 0
+	for ; i < len(ops) && ops[i].priority > 0; i++ {
+		s.runOp(ops[i].op, ctx)
+	}
+	s.runOp(field, ctx)
+	for ; i < len(ops); i++ {
+		s.runOp(ops[i].op, ctx)
+	}
+}
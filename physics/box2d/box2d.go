@@ -0,0 +1,60 @@
+// Package box2d adapts github.com/ByteArena/box2d worlds for use with physics.Op.
+package box2d
+
+import (
+	"github.com/ByteArena/box2d"
+
+	"go.spiff.io/gt3/physics"
+)
+
+// Default solver iterations per step.
+const (
+	DefaultVelocityIterations = 8
+	DefaultPositionIterations = 3
+)
+
+// World is a physics.World stepping a Box2D world. Bodies are *box2d.B2Body. World replaces the world's contact
+// listener; contacts are reported per fixture pair, so bodies with several fixtures may report more than one contact.
+type World struct {
+	World              *box2d.B2World
+	VelocityIterations int
+	PositionIterations int
+}
+
+var _ physics.World = (*World)(nil)
+
+// New returns a World for w using the default solver iterations.
+func New(w *box2d.B2World) *World {
+	return &World{
+		World:              w,
+		VelocityIterations: DefaultVelocityIterations,
+		PositionIterations: DefaultPositionIterations,
+	}
+}
+
+func (w *World) Step(dt float64) {
+	w.World.Step(dt, w.VelocityIterations, w.PositionIterations)
+}
+
+func (w *World) Bodies(fn func(body interface{}, t physics.Transform)) {
+	for b := w.World.GetBodyList(); b != nil; b = b.GetNext() {
+		p := b.GetPosition()
+		fn(b, physics.Transform{X: p.X, Y: p.Y, Angle: b.GetAngle()})
+	}
+}
+
+func (w *World) SetContactFunc(fn func(phase physics.ContactPhase, a, b interface{})) {
+	w.World.SetContactListener(listener(fn))
+}
+
+type listener func(phase physics.ContactPhase, a, b interface{})
+
+func (l listener) report(phase physics.ContactPhase, c box2d.B2ContactInterface) {
+	l(phase, c.GetFixtureA().GetBody(), c.GetFixtureB().GetBody())
+}
+
+func (l listener) BeginContact(c box2d.B2ContactInterface) { l.report(physics.ContactBegin, c) }
+func (l listener) EndContact(c box2d.B2ContactInterface)   { l.report(physics.ContactEnd, c) }
+
+func (listener) PreSolve(box2d.B2ContactInterface, box2d.B2Manifold)         {}
+func (listener) PostSolve(box2d.B2ContactInterface, *box2d.B2ContactImpulse) {}
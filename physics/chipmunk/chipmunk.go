@@ -0,0 +1,46 @@
+// Package chipmunk adapts github.com/jakecoffman/cp spaces for use with physics.Op.
+package chipmunk
+
+import (
+	"github.com/jakecoffman/cp"
+
+	"go.spiff.io/gt3/physics"
+)
+
+// Space is a physics.World stepping a Chipmunk space. Bodies are *cp.Body. Contacts are reported for shapes whose
+// collision type is Type by a wildcard collision handler, which Space replaces the begin and separate functions of.
+type Space struct {
+	Space *cp.Space
+	Type  cp.CollisionType
+}
+
+var _ physics.World = (*Space)(nil)
+
+// New returns a Space for s reporting contacts for shapes with collision type typ.
+func New(s *cp.Space, typ cp.CollisionType) *Space {
+	return &Space{Space: s, Type: typ}
+}
+
+func (s *Space) Step(dt float64) {
+	s.Space.Step(dt)
+}
+
+func (s *Space) Bodies(fn func(body interface{}, t physics.Transform)) {
+	s.Space.EachBody(func(b *cp.Body) {
+		p := b.Position()
+		fn(b, physics.Transform{X: p.X, Y: p.Y, Angle: b.Angle()})
+	})
+}
+
+func (s *Space) SetContactFunc(fn func(phase physics.ContactPhase, a, b interface{})) {
+	h := s.Space.NewWildcardCollisionHandler(s.Type)
+	h.BeginFunc = func(arb *cp.Arbiter, _ *cp.Space, _ interface{}) bool {
+		a, b := arb.Bodies()
+		fn(physics.ContactBegin, a, b)
+		return true
+	}
+	h.SeparateFunc = func(arb *cp.Arbiter, _ *cp.Space, _ interface{}) {
+		a, b := arb.Bodies()
+		fn(physics.ContactEnd, a, b)
+	}
+}
@@ -0,0 +1,170 @@
+// Package physics steps 2D physics engines as part of a Sim's Frame op. An Op steps a World by exactly the Sim's fixed
+// step, copies body transforms into a gt3.State for interpolated rendering, and delivers contacts reported during the
+// step as events once the step is done, when it's safe to modify the world.
+//
+// Adapters for specific engines are in the box2d and chipmunk subpackages.
+package physics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/g3m"
+)
+
+// Transform is a body's position and rotation in radians.
+type Transform struct {
+	X, Y  float64
+	Angle float64
+}
+
+// Lerp returns the transform alpha of the way from t to u. Angles are interpolated along the shorter arc.
+func (t Transform) Lerp(u Transform, alpha float64) Transform {
+	da := math.Remainder(u.Angle-t.Angle, 2*math.Pi)
+	return Transform{
+		X:     t.X + (u.X-t.X)*alpha,
+		Y:     t.Y + (u.Y-t.Y)*alpha,
+		Angle: t.Angle + da*alpha,
+	}
+}
+
+// Matrix returns t as a 2D affine transform, rotating and then translating.
+func (t Transform) Matrix() g3m.Mat3 {
+	return g3m.Translate2D(float32(t.X), float32(t.Y)).Mul(g3m.Rotate2D(float32(t.Angle)))
+}
+
+// Transforms maps an engine's native bodies, such as *box2d.B2Body, to their transforms.
+type Transforms map[interface{}]Transform
+
+// CopyTransforms copies src into dst. It is the copy function for a gt3.State[Transforms].
+func CopyTransforms(dst, src *Transforms) {
+	if *dst == nil {
+		*dst = make(Transforms, len(*src))
+	}
+	for b := range *dst {
+		if _, ok := (*src)[b]; !ok {
+			delete(*dst, b)
+		}
+	}
+	for b, t := range *src {
+		(*dst)[b] = t
+	}
+}
+
+// NewTransforms returns a gt3.State holding body transforms for s.
+func NewTransforms(s *gt3.Sim) *gt3.State[Transforms] {
+	return gt3.NewState(s, Transforms{}, CopyTransforms)
+}
+
+// Interp returns body's interpolated transform for a render op run with ctx. If body wasn't present in the previous
+// tick, its current transform is returned.
+func Interp(st *gt3.State[Transforms], ctx gt3.OpContext, body interface{}) (t Transform, ok bool) {
+	prev, cur, alpha := st.Interp(ctx)
+	u, ok := (*cur)[body]
+	if !ok {
+		return Transform{}, false
+	}
+	if t, ok := (*prev)[body]; ok {
+		return t.Lerp(u, alpha), true
+	}
+	return u, true
+}
+
+// ContactPhase is the stage of a contact between two bodies.
+type ContactPhase int
+
+// Contact phases.
+const (
+	ContactBegin ContactPhase = iota
+	ContactEnd
+)
+
+// ContactEvent is sent when two bodies start or stop touching. A and B are the engine's native bodies.
+type ContactEvent struct {
+	gt3.CustomEvent
+	Phase ContactPhase
+	A, B  interface{}
+	Tick  uint64
+}
+
+// World is implemented by engine adapters.
+type World interface {
+	// Step advances the world by dt seconds.
+	Step(dt float64)
+	// Bodies calls fn for every body in the world.
+	Bodies(fn func(body interface{}, t Transform))
+	// SetContactFunc sets the function called for contacts during Step. fn may be called from any goroutine the
+	// engine uses.
+	SetContactFunc(fn func(phase ContactPhase, a, b interface{}))
+}
+
+// Op is an op stepping World once per sim tick. It is typically run from, or as, the Frame op. An Op must not be copied
+// after first use.
+type Op struct {
+	World World
+
+	// Transforms, if not nil, receives every body's transform after each step.
+	Transforms *gt3.State[Transforms]
+
+	// Contacts receives a ContactEvent for every contact reported during a step, in order, after the step returns. If
+	// the op isn't run on the main goroutine, contacts are delivered through Sim's Sched instead. Contacts are dropped if
+	// Contacts is nil.
+	Contacts gt3.EventHandler
+	Sim      *gt3.Sim
+
+	once    sync.Once
+	mu      sync.Mutex
+	pending []ContactEvent
+	tick    uint64
+}
+
+func (o *Op) Do(step, frameTime float64, when time.Time) {
+	o.DoContext(gt3.OpContext{Step: step, FrameTime: frameTime, When: when})
+}
+
+func (o *Op) DoContext(ctx gt3.OpContext) {
+	o.once.Do(func() { o.World.SetContactFunc(o.contact) })
+
+	o.mu.Lock()
+	o.tick = ctx.Frame.Tick
+	o.mu.Unlock()
+
+	o.World.Step(ctx.Step)
+
+	if o.Transforms != nil {
+		ts := o.Transforms.Current()
+		for b := range *ts {
+			delete(*ts, b)
+		}
+		o.World.Bodies(func(body interface{}, t Transform) {
+			(*ts)[body] = t
+		})
+	}
+
+	o.mu.Lock()
+	contacts := o.pending
+	o.pending = nil
+	o.mu.Unlock()
+	if len(contacts) == 0 || o.Contacts == nil {
+		return
+	}
+
+	deliver := func() {
+		for _, c := range contacts {
+			o.Contacts.Event(c, ctx.When)
+		}
+	}
+	if o.Sim != nil && !o.Sim.IsMainThread() {
+		o.Sim.Sched(gt3.OpFn(func(float64, float64, time.Time) { deliver() }))
+		return
+	}
+	deliver()
+}
+
+func (o *Op) contact(phase ContactPhase, a, b interface{}) {
+	o.mu.Lock()
+	o.pending = append(o.pending, ContactEvent{Phase: phase, A: a, B: b, Tick: o.tick})
+	o.mu.Unlock()
+}
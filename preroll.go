@@ -0,0 +1,42 @@
+package gt3
+
+import "sync/atomic"
+
+// Preroll immediately runs n sim ticks, including scheduled ops and tick subscriptions, without rendering or waiting on
+// real time, so that caches, physics, and object pools are settled before the first visible frames. It may be called
+// before Run, or while running from a PreFrame or render op, such as during a loading screen. Simulation time advances
+// by n steps and the Sim's timer is advanced with it, so prerolled ticks aren't simulated again or caught up on. Sims
+// with a variable timestep preroll at DefaultFPS. Preroll must be called from the main goroutine and must not be called
+// during a sim frame, including from the Frame op and ops scheduled with Sched, which run as part of a frame.
+func (s *Sim) Preroll(n int) {
+	s.debugAssertMainThread()
+	if s.inFrame {
+		panic("gt3: Preroll called during a sim frame")
+	}
+
+	s.fpsrw.RLock()
+	hz := s.hz
+	s.fpsrw.RUnlock()
+	if hz == 0 {
+		hz = 1.0 / DefaultFPS
+	}
+
+	sim := s.simTime
+	for i := 0; i < n; i++ {
+		s.arena.Reset()
+		s.frame(hz, sim, s.realtime(sim))
+		sim += hz
+		s.simTime = sim
+		atomic.AddUint64(&s.ticks, 1)
+	}
+
+	// Shift the timer base so that the timer keeps pace with sim time. Times measured against the timer shift with it.
+	skip := float64(n) * hz
+	s.baseTime -= skip
+	s.renderTime += skip
+	s.nextDrift += skip
+	if s.pause != 0 {
+		// Keep the pause's start on the shifted timer, so that resuming doesn't undo the shift.
+		s.pausedAt += skip
+	}
+}
@@ -0,0 +1,66 @@
+package gt3
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreroll(t *testing.T) {
+	const fps = 64
+
+	s, step := newTestSim(t, fps)
+	frames := 0
+	s.Frame = OpFn(func(float64, float64, time.Time) { frames++ })
+	preroll := 0
+	s.PreFrame = OpFn(func(float64, float64, time.Time) {
+		s.Preroll(preroll)
+		preroll = 0
+	})
+
+	s.Preroll(3)
+	if frames != 3 || s.Tick() != 3 {
+		t.Fatalf("before Start: ran %d frames to tick %d; want 3 frames to tick 3", frames, s.Tick())
+	}
+	s.Start()
+
+	tests := []struct {
+		name    string
+		before  func() // Called before advancing the clock
+		advance float64
+		ticks   uint64
+	}{
+		{"Start", nil, 0, 3}, // The timer starts at the prerolled sim time
+		{"Run", nil, 1.0 / fps, 4},
+		{"PreFrame", func() { preroll = 2 }, 1.0 / fps, 7},
+		{"AfterPreFrame", nil, 1.0 / fps, 8},
+		{"Pause", func() { s.Pause(); s.Preroll(2) }, 1.0 / fps, 10},
+		{"Resume", s.Resume, 0, 10},
+		{"AfterResume", nil, 1.0 / fps, 11},
+	}
+	for _, tt := range tests {
+		if tt.before != nil {
+			tt.before()
+		}
+		step(tt.advance)
+		if got := s.Tick(); got != tt.ticks {
+			t.Errorf("%s: Tick() = %d; want %d", tt.name, got, tt.ticks)
+		}
+		if frames != int(tt.ticks) {
+			t.Errorf("%s: ran %d frames; want %d", tt.name, frames, tt.ticks)
+		}
+	}
+}
+
+func TestPrerollInFrame(t *testing.T) {
+	s, step := newTestSim(t, 64)
+	var v interface{}
+	s.Frame = OpFn(func(float64, float64, time.Time) {
+		defer func() { v = recover() }()
+		s.Preroll(1)
+	})
+	s.Start()
+	step(1.0 / 64)
+	if v == nil {
+		t.Error("Preroll from the Frame op didn't panic")
+	}
+}
@@ -0,0 +1,113 @@
+package gt3
+
+import (
+	"reflect"
+	"time"
+)
+
+// MergeFunc combines two events of the same type into one. It is used by RateLimiter to coalesce excess events.
+type MergeFunc func(prev, next Event) Event
+
+// KeepLatest is a MergeFunc that keeps the most recent event.
+func KeepLatest(prev, next Event) Event { return next }
+
+// RateLimiter is an EventHandler that caps the number of events of a given type delivered to Next per sim tick,
+// protecting Frame ops from event storms such as a stuck scroll wheel. Event types without a limit are passed through.
+// A RateLimiter must only be used from the main goroutine.
+type RateLimiter struct {
+	Next EventHandler
+
+	sim    *Sim
+	tick   uint64
+	limits map[reflect.Type]rateLimit
+	counts map[reflect.Type]int
+	held   []heldEvent
+}
+
+type rateLimit struct {
+	perTick int
+	merge   MergeFunc
+}
+
+type heldEvent struct {
+	typ  reflect.Type
+	e    Event
+	when time.Time
+}
+
+// NewRateLimiter returns a RateLimiter counting ticks of s and delivering events to next.
+func NewRateLimiter(s *Sim, next EventHandler) *RateLimiter {
+	return &RateLimiter{
+		Next:   next,
+		sim:    s,
+		tick:   s.Tick(),
+		limits: make(map[reflect.Type]rateLimit),
+		counts: make(map[reflect.Type]int),
+	}
+}
+
+// Limit caps events of the same type as sample to perTick events per sim tick. If merge is nil, excess events are
+// dropped. Otherwise, excess events are merged into a single held event that is delivered once the next tick begins.
+// A perTick <= 0 removes the limit.
+func (r *RateLimiter) Limit(sample Event, perTick int, merge MergeFunc) {
+	typ := reflect.TypeOf(sample)
+	if perTick <= 0 {
+		delete(r.limits, typ)
+		return
+	}
+	r.limits[typ] = rateLimit{perTick, merge}
+}
+
+// Event delivers e to Next unless its type has reached its limit for the current tick.
+func (r *RateLimiter) Event(e Event, when time.Time) {
+	r.advance()
+
+	typ := reflect.TypeOf(e)
+	lim, ok := r.limits[typ]
+	if !ok {
+		r.Next.Event(e, when)
+		return
+	}
+
+	if r.counts[typ] < lim.perTick {
+		r.counts[typ]++
+		r.Next.Event(e, when)
+		return
+	}
+
+	if lim.merge == nil {
+		return
+	}
+	for i := range r.held {
+		if h := &r.held[i]; h.typ == typ {
+			h.e, h.when = lim.merge(h.e, e), when
+			return
+		}
+	}
+	r.held = append(r.held, heldEvent{typ, e, when})
+}
+
+// Flush starts counting a new tick if the Sim's tick has changed since the last event, delivering any held events. It
+// is called by Event, but may also be called from the PreFrame op so that held events aren't delayed until the next
+// event arrives.
+func (r *RateLimiter) Flush() {
+	r.advance()
+}
+
+func (r *RateLimiter) advance() {
+	tick := r.sim.Tick()
+	if tick == r.tick {
+		return
+	}
+	r.tick = tick
+	for typ := range r.counts {
+		delete(r.counts, typ)
+	}
+
+	held := r.held
+	r.held = nil
+	for _, h := range held {
+		r.counts[h.typ]++
+		r.Next.Event(h.e, h.when)
+	}
+}
@@ -0,0 +1,46 @@
+package gt3
+
+// Raw input events are posted by raw input backends, such as go.spiff.io/gt3/rawinput, that read keyboards and mice
+// directly instead of through a window system. Each event carries the ID of the device it came from, so that several
+// keyboards or mice attached to one machine can be told apart. Raw events are not tied to a window and are delivered
+// regardless of focus. SetEventCallbacks also posts RawMotionEvents for GLFW cursor motion, with Device 0.
+type (
+	RawKeyEvent struct {
+		Device DeviceID
+		Key    Key
+		Code   int // Backend-specific scancode
+		Action Action
+	}
+
+	RawMouseEvent struct {
+		Device DeviceID
+		Button MouseButton
+		Action Action
+	}
+
+	RawMotionEvent struct {
+		Device DeviceID
+		DX     float64
+		DY     float64
+	}
+
+	RawScrollEvent struct {
+		Device DeviceID
+		XOff   float64
+		YOff   float64
+	}
+)
+
+func (RawKeyEvent) isEvent()    {}
+func (RawMouseEvent) isEvent()  {}
+func (RawMotionEvent) isEvent() {}
+func (RawScrollEvent) isEvent() {}
+
+// HotkeyEvent is posted by go.spiff.io/gt3/hotkey when a registered system-wide key combination is pressed, whether or
+// not any of the application's windows are focused.
+type HotkeyEvent struct {
+	Name   string
+	Device DeviceID // Device the combination was pressed on
+}
+
+func (HotkeyEvent) isEvent() {}
@@ -0,0 +1,22 @@
+// Package rawinput reads keyboards and mice directly from the operating system, posting gt3 raw input events that
+// identify the device each event came from. This allows, for example, two players to each use their own keyboard on
+// one machine, which GLFW cannot express since it merges all keyboards and mice into one.
+//
+// Backends exist for Linux, Windows, and macOS:
+//
+//   - On Linux, devices are read through evdev. This requires read access to /dev/input/event*, which usually means
+//     membership in the input group.
+//   - On Windows, devices are read through Raw Input, using a message-only window on a thread of its own. Input is
+//     received whether or not the application is focused.
+//   - On macOS, devices are read through an IOHIDManager, which requires cgo and the Input Monitoring permission.
+//
+// On other platforms, Open returns ErrUnsupported.
+package rawinput
+
+import "errors"
+
+// ErrUnsupported is returned by Open on platforms without a raw input backend.
+var ErrUnsupported = errors.New("rawinput: raw input is not supported on this platform")
+
+// ErrNoDevices is returned by Open if no keyboards or mice could be opened.
+var ErrNoDevices = errors.New("rawinput: no readable input devices")
@@ -0,0 +1,138 @@
+package rawinput
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"go.spiff.io/gt3"
+)
+
+// Linux input event types and codes, from linux/input-event-codes.h.
+const (
+	evSyn = 0x00
+	evKey = 0x01
+	evRel = 0x02
+
+	synReport = 0
+
+	relX      = 0x00
+	relY      = 0x01
+	relHWheel = 0x06
+	relWheel  = 0x08
+
+	btnMouse = 0x110 // BTN_LEFT; BTN_RIGHT, BTN_MIDDLE, etc. follow
+	btnTask  = 0x117
+)
+
+// inputEvent is struct input_event.
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+func (s *Source) open() error {
+	paths, err := filepath.Glob("/dev/input/event*")
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(path), "event"))
+		if err != nil || !isKeyboardOrMouse(n) {
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			// Most likely a permissions error; skip devices we can't read.
+			continue
+		}
+
+		sys := "/sys/class/input/event" + strconv.Itoa(n) + "/device/"
+		dev := s.Devices.Connect(n, sysfsString(sys+"name"), sysfsString(sys+"uniq"))
+		s.closers = append(s.closers, f)
+		s.wg.Add(1)
+		go s.read(f, dev.ID)
+	}
+
+	if len(s.closers) == 0 {
+		return ErrNoDevices
+	}
+	return nil
+}
+
+func sysfsString(path string) string {
+	b, _ := os.ReadFile(path)
+	return strings.TrimSpace(string(b))
+}
+
+// isKeyboardOrMouse reports whether the event device reports keys or relative motion.
+func isKeyboardOrMouse(n int) bool {
+	caps, err := strconv.ParseUint(sysfsString("/sys/class/input/event"+strconv.Itoa(n)+"/device/capabilities/ev"), 16, 64)
+	return err == nil && caps&(1<<evKey|1<<evRel) != 0
+}
+
+func (s *Source) read(r io.Reader, id gt3.DeviceID) {
+	defer s.wg.Done()
+
+	var (
+		ev     inputEvent
+		buf    = (*[unsafe.Sizeof(ev)]byte)(unsafe.Pointer(&ev))[:]
+		dx, dy float64
+		sx, sy float64
+	)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return
+		}
+		when := time.Unix(int64(ev.Time.Sec), int64(ev.Time.Usec)*int64(time.Microsecond))
+
+		switch ev.Type {
+		case evSyn:
+			if ev.Code != synReport {
+				break
+			}
+			if dx != 0 || dy != 0 {
+				s.post(gt3.RawMotionEvent{Device: id, DX: dx, DY: dy}, when)
+			}
+			if sx != 0 || sy != 0 {
+				s.post(gt3.RawScrollEvent{Device: id, XOff: sx, YOff: sy}, when)
+			}
+			dx, dy, sx, sy = 0, 0, 0, 0
+		case evRel:
+			switch ev.Code {
+			case relX:
+				dx += float64(ev.Value)
+			case relY:
+				dy += float64(ev.Value)
+			case relHWheel:
+				sx += float64(ev.Value)
+			case relWheel:
+				sy += float64(ev.Value)
+			}
+		case evKey:
+			action := gt3.Action(ev.Value) // 0, 1, and 2 are release, press, and repeat
+			if ev.Value < 0 || ev.Value > 2 {
+				break
+			}
+			if ev.Code >= btnMouse && ev.Code <= btnTask {
+				if action != gt3.Repeat {
+					s.post(gt3.RawMouseEvent{Device: id, Button: gt3.MouseButton(ev.Code - btnMouse), Action: action}, when)
+				}
+				break
+			}
+			key, ok := evdevKeys[ev.Code]
+			if !ok {
+				key = gt3.KeyUnknown
+			}
+			s.post(gt3.RawKeyEvent{Device: id, Key: key, Code: int(ev.Code), Action: action}, when)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+//go:build cgo
+
+package rawinput
+
+// #include <stdint.h>
+import "C"
+
+import (
+	"runtime/cgo"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// HID usage pages and usages, from the USB HID Usage Tables.
+const (
+	hidPageGenericDesktop = 0x01
+	hidPageKeyboard       = 0x07
+	hidPageButton         = 0x09
+	hidPageConsumer       = 0x0C
+
+	hidUsageX     = 0x30
+	hidUsageY     = 0x31
+	hidUsageWheel = 0x38
+	hidUsageACPan = 0x238
+
+	hidUsageKeyFirst = 0x04 // Keyboard a and A; lower usages are error codes
+	hidUsageKeyLast  = 0xE7 // Keyboard Right GUI
+)
+
+//export gt3RawInputMatched
+func gt3RawInputMatched(ctx, dev C.uintptr_t) {
+	cgo.Handle(ctx).Value().(*hidManager).matched(dev)
+}
+
+//export gt3RawInputRemoved
+func gt3RawInputRemoved(ctx, dev C.uintptr_t) {
+	cgo.Handle(ctx).Value().(*hidManager).removed(dev)
+}
+
+//export gt3RawInputValue
+func gt3RawInputValue(ctx, dev C.uintptr_t, page, usage C.uint32_t, value C.long) {
+	cgo.Handle(ctx).Value().(*hidManager).value(dev, uint32(page), uint32(usage), int64(value))
+}
+
+// value posts an event for a change in the value of one of a device's elements. HID reports each axis and key
+// separately, so motion on both axes is posted as two RawMotionEvents.
+func (m *hidManager) value(dev C.uintptr_t, page, usage uint32, value int64) {
+	d, ok := m.devices[dev]
+	if !ok {
+		m.matched(dev)
+		d = m.devices[dev]
+	}
+	when := time.Now()
+
+	action := gt3.Release
+	if value != 0 {
+		action = gt3.Press
+	}
+	switch page {
+	case hidPageKeyboard:
+		if usage < hidUsageKeyFirst || usage > hidUsageKeyLast {
+			return
+		}
+		key, ok := hidKeys[usage]
+		if !ok {
+			key = gt3.KeyUnknown
+		}
+		m.s.post(gt3.RawKeyEvent{Device: d.id, Key: key, Code: int(usage), Action: action}, when)
+	case hidPageButton:
+		if usage < 1 || usage > uint32(gt3.MouseButtonLast)+1 {
+			return
+		}
+		m.s.post(gt3.RawMouseEvent{Device: d.id, Button: gt3.MouseButton(usage - 1), Action: action}, when)
+	case hidPageGenericDesktop:
+		if value == 0 {
+			return
+		}
+		switch usage {
+		case hidUsageX:
+			m.s.post(gt3.RawMotionEvent{Device: d.id, DX: float64(value)}, when)
+		case hidUsageY:
+			m.s.post(gt3.RawMotionEvent{Device: d.id, DY: float64(value)}, when)
+		case hidUsageWheel:
+			m.s.post(gt3.RawScrollEvent{Device: d.id, YOff: float64(value)}, when)
+		}
+	case hidPageConsumer:
+		if usage == hidUsageACPan && value != 0 {
+			m.s.post(gt3.RawScrollEvent{Device: d.id, XOff: float64(value)}, when)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+//go:build cgo
+
+package rawinput
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/hid/IOHIDManager.h>
+#include <IOKit/hid/IOHIDKeys.h>
+#include <IOKit/hid/IOHIDUsageTables.h>
+
+// Defined in hid_darwin.go.
+extern void gt3RawInputMatched(uintptr_t ctx, uintptr_t dev);
+extern void gt3RawInputRemoved(uintptr_t ctx, uintptr_t dev);
+extern void gt3RawInputValue(uintptr_t ctx, uintptr_t dev, uint32_t page, uint32_t usage, long value);
+
+static void onMatched(void *ctx, IOReturn res, void *sender, IOHIDDeviceRef dev) {
+	gt3RawInputMatched((uintptr_t)ctx, (uintptr_t)dev);
+}
+
+static void onRemoved(void *ctx, IOReturn res, void *sender, IOHIDDeviceRef dev) {
+	gt3RawInputRemoved((uintptr_t)ctx, (uintptr_t)dev);
+}
+
+static void onValue(void *ctx, IOReturn res, void *sender, IOHIDValueRef v) {
+	IOHIDElementRef el = IOHIDValueGetElement(v);
+	gt3RawInputValue((uintptr_t)ctx, (uintptr_t)IOHIDElementGetDevice(el),
+		IOHIDElementGetUsagePage(el), IOHIDElementGetUsage(el), (long)IOHIDValueGetIntegerValue(v));
+}
+
+static CFDictionaryRef matchUsage(int32_t page, int32_t usage) {
+	CFMutableDictionaryRef d = CFDictionaryCreateMutable(kCFAllocatorDefault, 2,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	CFNumberRef p = CFNumberCreate(kCFAllocatorDefault, kCFNumberSInt32Type, &page);
+	CFNumberRef u = CFNumberCreate(kCFAllocatorDefault, kCFNumberSInt32Type, &usage);
+	CFDictionarySetValue(d, CFSTR(kIOHIDDeviceUsagePageKey), p);
+	CFDictionarySetValue(d, CFSTR(kIOHIDDeviceUsageKey), u);
+	CFRelease(p);
+	CFRelease(u);
+	return d;
+}
+
+// openManager opens a HID manager for all keyboards and mice, delivering their events to the current thread's run
+// loop. It returns 0 and sets *ret on failure, and sets *count to the number of devices found.
+static uintptr_t openManager(uintptr_t ctx, IOReturn *ret, long *count) {
+	IOHIDManagerRef m = IOHIDManagerCreate(kCFAllocatorDefault, kIOHIDOptionsTypeNone);
+	const void *matches[] = {
+		matchUsage(kHIDPage_GenericDesktop, kHIDUsage_GD_Keyboard),
+		matchUsage(kHIDPage_GenericDesktop, kHIDUsage_GD_Mouse),
+	};
+	CFArrayRef arr = CFArrayCreate(kCFAllocatorDefault, matches, 2, &kCFTypeArrayCallBacks);
+	IOHIDManagerSetDeviceMatchingMultiple(m, arr);
+	CFRelease(arr);
+	CFRelease(matches[0]);
+	CFRelease(matches[1]);
+
+	IOHIDManagerRegisterDeviceMatchingCallback(m, onMatched, (void *)ctx);
+	IOHIDManagerRegisterDeviceRemovalCallback(m, onRemoved, (void *)ctx);
+	IOHIDManagerRegisterInputValueCallback(m, onValue, (void *)ctx);
+	IOHIDManagerScheduleWithRunLoop(m, CFRunLoopGetCurrent(), kCFRunLoopDefaultMode);
+
+	*ret = IOHIDManagerOpen(m, kIOHIDOptionsTypeNone);
+	if (*ret != kIOReturnSuccess) {
+		IOHIDManagerUnscheduleFromRunLoop(m, CFRunLoopGetCurrent(), kCFRunLoopDefaultMode);
+		CFRelease(m);
+		return 0;
+	}
+	*count = 0;
+	CFSetRef devs = IOHIDManagerCopyDevices(m);
+	if (devs != NULL) {
+		*count = CFSetGetCount(devs);
+		CFRelease(devs);
+	}
+	return (uintptr_t)m;
+}
+
+static void closeManager(uintptr_t m) {
+	IOHIDManagerRef mgr = (IOHIDManagerRef)m;
+	IOHIDManagerClose(mgr, kIOHIDOptionsTypeNone);
+	IOHIDManagerUnscheduleFromRunLoop(mgr, CFRunLoopGetCurrent(), kCFRunLoopDefaultMode);
+	CFRelease(mgr);
+}
+
+static uintptr_t currentRunLoop(void) {
+	return (uintptr_t)CFRunLoopGetCurrent();
+}
+
+static void runFor(double seconds) {
+	CFRunLoopRunInMode(kCFRunLoopDefaultMode, seconds, false);
+}
+
+static void stopRunLoop(uintptr_t rl) {
+	CFRunLoopStop((CFRunLoopRef)rl);
+}
+
+// deviceString copies the device's string property key into buf and returns its length, or 0 if it has none.
+static int deviceString(uintptr_t dev, const char *key, char *buf, int n) {
+	CFStringRef k = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef v = IOHIDDeviceGetProperty((IOHIDDeviceRef)dev, k);
+	CFRelease(k);
+	if (v == NULL || CFGetTypeID(v) != CFStringGetTypeID() ||
+		!CFStringGetCString((CFStringRef)v, buf, n, kCFStringEncodingUTF8)) {
+		return 0;
+	}
+	return (int)strlen(buf);
+}
+
+// deviceNumber returns the device's integer property key, or 0 if it has none.
+static long long deviceNumber(uintptr_t dev, const char *key) {
+	CFStringRef k = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef v = IOHIDDeviceGetProperty((IOHIDDeviceRef)dev, k);
+	CFRelease(k);
+	long long n = 0;
+	if (v != NULL && CFGetTypeID(v) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)v, kCFNumberLongLongType, &n);
+	}
+	return n;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/cgo"
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+
+	"go.spiff.io/gt3"
+)
+
+// ioReturnNotPermitted is kIOReturnNotPermitted, returned by IOHIDManagerOpen when the process lacks Input Monitoring
+// permission.
+const ioReturnNotPermitted = 0xE00002E2
+
+var errNotPermitted = errors.New("rawinput: reading input devices requires Input Monitoring permission")
+
+// hidManager reads keyboards and mice through an IOHIDManager scheduled on its own locked thread's run loop. Its
+// callbacks run on that thread.
+type hidManager struct {
+	s        *Source
+	handle   cgo.Handle
+	runLoop  C.uintptr_t
+	stopped  int32
+	nextSlot int
+	devices  map[C.uintptr_t]hidDevice
+}
+
+type hidDevice struct {
+	slot int
+	id   gt3.DeviceID
+}
+
+func (s *Source) open() error {
+	m := &hidManager{s: s, devices: make(map[C.uintptr_t]hidDevice)}
+	m.handle = cgo.NewHandle(m)
+
+	errc := make(chan error, 1)
+	s.wg.Add(1)
+	go m.run(errc)
+	if err := <-errc; err != nil {
+		return err
+	}
+	s.closers = append(s.closers, m)
+	return nil
+}
+
+func (m *hidManager) run(errc chan<- error) {
+	defer m.s.wg.Done()
+	defer m.handle.Delete()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var (
+		ret   C.IOReturn
+		count C.long
+	)
+	m.runLoop = C.currentRunLoop()
+	mgr := C.openManager(C.uintptr_t(m.handle), &ret, &count)
+	switch {
+	case mgr == 0 && uint32(ret) == ioReturnNotPermitted:
+		errc <- errNotPermitted
+		return
+	case mgr == 0:
+		errc <- fmt.Errorf("rawinput: IOHIDManagerOpen failed: %#x", uint32(ret))
+		return
+	case count == 0:
+		C.closeManager(mgr)
+		errc <- ErrNoDevices
+		return
+	}
+	defer C.closeManager(mgr)
+	errc <- nil
+
+	// Run in short intervals so that a Close racing the start of the run loop isn't lost.
+	for atomic.LoadInt32(&m.stopped) == 0 {
+		C.runFor(0.25)
+	}
+}
+
+// Close stops the run loop.
+func (m *hidManager) Close() error {
+	if atomic.CompareAndSwapInt32(&m.stopped, 0, 1) {
+		C.stopRunLoop(m.runLoop)
+	}
+	return nil
+}
+
+func (m *hidManager) matched(dev C.uintptr_t) {
+	if _, ok := m.devices[dev]; ok {
+		return
+	}
+	name := deviceString(dev, "Product")
+	guid := deviceString(dev, "SerialNumber")
+	if guid == "" {
+		// Fall back to the device's port, which is stable for a device reconnected to the same port.
+		if loc := deviceNumber(dev, "LocationID"); loc != 0 {
+			guid = "location:" + strconv.FormatInt(loc, 16)
+		}
+	}
+	slot := m.nextSlot
+	m.nextSlot++
+	d := m.s.Devices.Connect(slot, name, guid)
+	m.devices[dev] = hidDevice{slot: slot, id: d.ID}
+}
+
+func (m *hidManager) removed(dev C.uintptr_t) {
+	if d, ok := m.devices[dev]; ok {
+		m.s.Devices.Disconnect(d.slot)
+		delete(m.devices, dev)
+	}
+}
+
+func deviceString(dev C.uintptr_t, key string) string {
+	var buf [256]C.char
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	n := C.deviceString(dev, ckey, &buf[0], C.int(len(buf)))
+	return C.GoStringN(&buf[0], n)
+}
+
+func deviceNumber(dev C.uintptr_t, key string) int64 {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	return int64(C.deviceNumber(dev, ckey))
+}
@@ -0,0 +1,128 @@
+//go:build cgo
+
+package rawinput
+
+import "go.spiff.io/gt3"
+
+// hidKeys maps HID keyboard page usages to gt3 Keys.
+var hidKeys = map[uint32]gt3.Key{
+	0x04: gt3.KeyA,
+	0x05: gt3.KeyB,
+	0x06: gt3.KeyC,
+	0x07: gt3.KeyD,
+	0x08: gt3.KeyE,
+	0x09: gt3.KeyF,
+	0x0A: gt3.KeyG,
+	0x0B: gt3.KeyH,
+	0x0C: gt3.KeyI,
+	0x0D: gt3.KeyJ,
+	0x0E: gt3.KeyK,
+	0x0F: gt3.KeyL,
+	0x10: gt3.KeyM,
+	0x11: gt3.KeyN,
+	0x12: gt3.KeyO,
+	0x13: gt3.KeyP,
+	0x14: gt3.KeyQ,
+	0x15: gt3.KeyR,
+	0x16: gt3.KeyS,
+	0x17: gt3.KeyT,
+	0x18: gt3.KeyU,
+	0x19: gt3.KeyV,
+	0x1A: gt3.KeyW,
+	0x1B: gt3.KeyX,
+	0x1C: gt3.KeyY,
+	0x1D: gt3.KeyZ,
+	0x1E: gt3.Key1,
+	0x1F: gt3.Key2,
+	0x20: gt3.Key3,
+	0x21: gt3.Key4,
+	0x22: gt3.Key5,
+	0x23: gt3.Key6,
+	0x24: gt3.Key7,
+	0x25: gt3.Key8,
+	0x26: gt3.Key9,
+	0x27: gt3.Key0,
+	0x28: gt3.KeyEnter,
+	0x29: gt3.KeyEscape,
+	0x2A: gt3.KeyBackspace,
+	0x2B: gt3.KeyTab,
+	0x2C: gt3.KeySpace,
+	0x2D: gt3.KeyMinus,
+	0x2E: gt3.KeyEqual,
+	0x2F: gt3.KeyLeftBracket,
+	0x30: gt3.KeyRightBracket,
+	0x31: gt3.KeyBackslash,
+	0x32: gt3.KeyWorld1,
+	0x33: gt3.KeySemicolon,
+	0x34: gt3.KeyApostrophe,
+	0x35: gt3.KeyGraveAccent,
+	0x36: gt3.KeyComma,
+	0x37: gt3.KeyPeriod,
+	0x38: gt3.KeySlash,
+	0x39: gt3.KeyCapsLock,
+	0x3A: gt3.KeyF1,
+	0x3B: gt3.KeyF2,
+	0x3C: gt3.KeyF3,
+	0x3D: gt3.KeyF4,
+	0x3E: gt3.KeyF5,
+	0x3F: gt3.KeyF6,
+	0x40: gt3.KeyF7,
+	0x41: gt3.KeyF8,
+	0x42: gt3.KeyF9,
+	0x43: gt3.KeyF10,
+	0x44: gt3.KeyF11,
+	0x45: gt3.KeyF12,
+	0x46: gt3.KeyPrintScreen,
+	0x47: gt3.KeyScrollLock,
+	0x48: gt3.KeyPause,
+	0x49: gt3.KeyInsert,
+	0x4A: gt3.KeyHome,
+	0x4B: gt3.KeyPageUp,
+	0x4C: gt3.KeyDelete,
+	0x4D: gt3.KeyEnd,
+	0x4E: gt3.KeyPageDown,
+	0x4F: gt3.KeyRight,
+	0x50: gt3.KeyLeft,
+	0x51: gt3.KeyDown,
+	0x52: gt3.KeyUp,
+	0x53: gt3.KeyNumLock,
+	0x54: gt3.KeyKPDivide,
+	0x55: gt3.KeyKPMultiply,
+	0x56: gt3.KeyKPSubtract,
+	0x57: gt3.KeyKPAdd,
+	0x58: gt3.KeyKPEnter,
+	0x59: gt3.KeyKP1,
+	0x5A: gt3.KeyKP2,
+	0x5B: gt3.KeyKP3,
+	0x5C: gt3.KeyKP4,
+	0x5D: gt3.KeyKP5,
+	0x5E: gt3.KeyKP6,
+	0x5F: gt3.KeyKP7,
+	0x60: gt3.KeyKP8,
+	0x61: gt3.KeyKP9,
+	0x62: gt3.KeyKP0,
+	0x63: gt3.KeyKPDecimal,
+	0x64: gt3.KeyWorld2,
+	0x65: gt3.KeyMenu,
+	0x67: gt3.KeyKPEqual,
+	0x68: gt3.KeyF13,
+	0x69: gt3.KeyF14,
+	0x6A: gt3.KeyF15,
+	0x6B: gt3.KeyF16,
+	0x6C: gt3.KeyF17,
+	0x6D: gt3.KeyF18,
+	0x6E: gt3.KeyF19,
+	0x6F: gt3.KeyF20,
+	0x70: gt3.KeyF21,
+	0x71: gt3.KeyF22,
+	0x72: gt3.KeyF23,
+	0x73: gt3.KeyF24,
+	0xE0: gt3.KeyLeftControl,
+	0xE1: gt3.KeyLeftShift,
+	0xE2: gt3.KeyLeftAlt,
+	0xE3: gt3.KeyLeftSuper,
+	0xE4: gt3.KeyRightControl,
+	0xE5: gt3.KeyRightShift,
+	0xE6: gt3.KeyRightAlt,
+	0xE7: gt3.KeyRightSuper,
+}
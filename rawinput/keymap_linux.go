@@ -0,0 +1,125 @@
+package rawinput
+
+import "go.spiff.io/gt3"
+
+// evdevKeys maps Linux key codes to gt3 Keys.
+var evdevKeys = map[uint16]gt3.Key{
+	1:   gt3.KeyEscape,
+	2:   gt3.Key1,
+	3:   gt3.Key2,
+	4:   gt3.Key3,
+	5:   gt3.Key4,
+	6:   gt3.Key5,
+	7:   gt3.Key6,
+	8:   gt3.Key7,
+	9:   gt3.Key8,
+	10:  gt3.Key9,
+	11:  gt3.Key0,
+	12:  gt3.KeyMinus,
+	13:  gt3.KeyEqual,
+	14:  gt3.KeyBackspace,
+	15:  gt3.KeyTab,
+	16:  gt3.KeyQ,
+	17:  gt3.KeyW,
+	18:  gt3.KeyE,
+	19:  gt3.KeyR,
+	20:  gt3.KeyT,
+	21:  gt3.KeyY,
+	22:  gt3.KeyU,
+	23:  gt3.KeyI,
+	24:  gt3.KeyO,
+	25:  gt3.KeyP,
+	26:  gt3.KeyLeftBracket,
+	27:  gt3.KeyRightBracket,
+	28:  gt3.KeyEnter,
+	29:  gt3.KeyLeftControl,
+	30:  gt3.KeyA,
+	31:  gt3.KeyS,
+	32:  gt3.KeyD,
+	33:  gt3.KeyF,
+	34:  gt3.KeyG,
+	35:  gt3.KeyH,
+	36:  gt3.KeyJ,
+	37:  gt3.KeyK,
+	38:  gt3.KeyL,
+	39:  gt3.KeySemicolon,
+	40:  gt3.KeyApostrophe,
+	41:  gt3.KeyGraveAccent,
+	42:  gt3.KeyLeftShift,
+	43:  gt3.KeyBackslash,
+	44:  gt3.KeyZ,
+	45:  gt3.KeyX,
+	46:  gt3.KeyC,
+	47:  gt3.KeyV,
+	48:  gt3.KeyB,
+	49:  gt3.KeyN,
+	50:  gt3.KeyM,
+	51:  gt3.KeyComma,
+	52:  gt3.KeyPeriod,
+	53:  gt3.KeySlash,
+	54:  gt3.KeyRightShift,
+	55:  gt3.KeyKPMultiply,
+	56:  gt3.KeyLeftAlt,
+	57:  gt3.KeySpace,
+	58:  gt3.KeyCapsLock,
+	59:  gt3.KeyF1,
+	60:  gt3.KeyF2,
+	61:  gt3.KeyF3,
+	62:  gt3.KeyF4,
+	63:  gt3.KeyF5,
+	64:  gt3.KeyF6,
+	65:  gt3.KeyF7,
+	66:  gt3.KeyF8,
+	67:  gt3.KeyF9,
+	68:  gt3.KeyF10,
+	69:  gt3.KeyNumLock,
+	70:  gt3.KeyScrollLock,
+	71:  gt3.KeyKP7,
+	72:  gt3.KeyKP8,
+	73:  gt3.KeyKP9,
+	74:  gt3.KeyKPSubtract,
+	75:  gt3.KeyKP4,
+	76:  gt3.KeyKP5,
+	77:  gt3.KeyKP6,
+	78:  gt3.KeyKPAdd,
+	79:  gt3.KeyKP1,
+	80:  gt3.KeyKP2,
+	81:  gt3.KeyKP3,
+	82:  gt3.KeyKP0,
+	83:  gt3.KeyKPDecimal,
+	86:  gt3.KeyWorld2,
+	87:  gt3.KeyF11,
+	88:  gt3.KeyF12,
+	96:  gt3.KeyKPEnter,
+	97:  gt3.KeyRightControl,
+	98:  gt3.KeyKPDivide,
+	99:  gt3.KeyPrintScreen,
+	100: gt3.KeyRightAlt,
+	102: gt3.KeyHome,
+	103: gt3.KeyUp,
+	104: gt3.KeyPageUp,
+	105: gt3.KeyLeft,
+	106: gt3.KeyRight,
+	107: gt3.KeyEnd,
+	108: gt3.KeyDown,
+	109: gt3.KeyPageDown,
+	110: gt3.KeyInsert,
+	111: gt3.KeyDelete,
+	117: gt3.KeyKPEqual,
+	119: gt3.KeyPause,
+	125: gt3.KeyLeftSuper,
+	126: gt3.KeyRightSuper,
+	127: gt3.KeyMenu,
+	183: gt3.KeyF13,
+	184: gt3.KeyF14,
+	185: gt3.KeyF15,
+	186: gt3.KeyF16,
+	187: gt3.KeyF17,
+	188: gt3.KeyF18,
+	189: gt3.KeyF19,
+	190: gt3.KeyF20,
+	191: gt3.KeyF21,
+	192: gt3.KeyF22,
+	193: gt3.KeyF23,
+	194: gt3.KeyF24,
+}
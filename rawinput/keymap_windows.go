@@ -0,0 +1,124 @@
+package rawinput
+
+import "go.spiff.io/gt3"
+
+// scancodeKeys maps PC set 1 scancodes to gt3 Keys. Scancodes with an E0 prefix have 0x100 added.
+var scancodeKeys = map[uint16]gt3.Key{
+	0x001: gt3.KeyEscape,
+	0x002: gt3.Key1,
+	0x003: gt3.Key2,
+	0x004: gt3.Key3,
+	0x005: gt3.Key4,
+	0x006: gt3.Key5,
+	0x007: gt3.Key6,
+	0x008: gt3.Key7,
+	0x009: gt3.Key8,
+	0x00A: gt3.Key9,
+	0x00B: gt3.Key0,
+	0x00C: gt3.KeyMinus,
+	0x00D: gt3.KeyEqual,
+	0x00E: gt3.KeyBackspace,
+	0x00F: gt3.KeyTab,
+	0x010: gt3.KeyQ,
+	0x011: gt3.KeyW,
+	0x012: gt3.KeyE,
+	0x013: gt3.KeyR,
+	0x014: gt3.KeyT,
+	0x015: gt3.KeyY,
+	0x016: gt3.KeyU,
+	0x017: gt3.KeyI,
+	0x018: gt3.KeyO,
+	0x019: gt3.KeyP,
+	0x01A: gt3.KeyLeftBracket,
+	0x01B: gt3.KeyRightBracket,
+	0x01C: gt3.KeyEnter,
+	0x01D: gt3.KeyLeftControl,
+	0x01E: gt3.KeyA,
+	0x01F: gt3.KeyS,
+	0x020: gt3.KeyD,
+	0x021: gt3.KeyF,
+	0x022: gt3.KeyG,
+	0x023: gt3.KeyH,
+	0x024: gt3.KeyJ,
+	0x025: gt3.KeyK,
+	0x026: gt3.KeyL,
+	0x027: gt3.KeySemicolon,
+	0x028: gt3.KeyApostrophe,
+	0x029: gt3.KeyGraveAccent,
+	0x02A: gt3.KeyLeftShift,
+	0x02B: gt3.KeyBackslash,
+	0x02C: gt3.KeyZ,
+	0x02D: gt3.KeyX,
+	0x02E: gt3.KeyC,
+	0x02F: gt3.KeyV,
+	0x030: gt3.KeyB,
+	0x031: gt3.KeyN,
+	0x032: gt3.KeyM,
+	0x033: gt3.KeyComma,
+	0x034: gt3.KeyPeriod,
+	0x035: gt3.KeySlash,
+	0x036: gt3.KeyRightShift,
+	0x037: gt3.KeyKPMultiply,
+	0x038: gt3.KeyLeftAlt,
+	0x039: gt3.KeySpace,
+	0x03A: gt3.KeyCapsLock,
+	0x03B: gt3.KeyF1,
+	0x03C: gt3.KeyF2,
+	0x03D: gt3.KeyF3,
+	0x03E: gt3.KeyF4,
+	0x03F: gt3.KeyF5,
+	0x040: gt3.KeyF6,
+	0x041: gt3.KeyF7,
+	0x042: gt3.KeyF8,
+	0x043: gt3.KeyF9,
+	0x044: gt3.KeyF10,
+	0x045: gt3.KeyNumLock,
+	0x046: gt3.KeyScrollLock,
+	0x047: gt3.KeyKP7,
+	0x048: gt3.KeyKP8,
+	0x049: gt3.KeyKP9,
+	0x04A: gt3.KeyKPSubtract,
+	0x04B: gt3.KeyKP4,
+	0x04C: gt3.KeyKP5,
+	0x04D: gt3.KeyKP6,
+	0x04E: gt3.KeyKPAdd,
+	0x04F: gt3.KeyKP1,
+	0x050: gt3.KeyKP2,
+	0x051: gt3.KeyKP3,
+	0x052: gt3.KeyKP0,
+	0x053: gt3.KeyKPDecimal,
+	0x056: gt3.KeyWorld2,
+	0x057: gt3.KeyF11,
+	0x058: gt3.KeyF12,
+	0x059: gt3.KeyKPEqual,
+	0x064: gt3.KeyF13,
+	0x065: gt3.KeyF14,
+	0x066: gt3.KeyF15,
+	0x067: gt3.KeyF16,
+	0x068: gt3.KeyF17,
+	0x069: gt3.KeyF18,
+	0x06A: gt3.KeyF19,
+	0x06B: gt3.KeyF20,
+	0x06C: gt3.KeyF21,
+	0x06D: gt3.KeyF22,
+	0x06E: gt3.KeyF23,
+	0x076: gt3.KeyF24,
+	0x11C: gt3.KeyKPEnter,
+	0x11D: gt3.KeyRightControl,
+	0x135: gt3.KeyKPDivide,
+	0x137: gt3.KeyPrintScreen,
+	0x138: gt3.KeyRightAlt,
+	0x147: gt3.KeyHome,
+	0x148: gt3.KeyUp,
+	0x149: gt3.KeyPageUp,
+	0x14B: gt3.KeyLeft,
+	0x14D: gt3.KeyRight,
+	0x14F: gt3.KeyEnd,
+	0x150: gt3.KeyDown,
+	0x151: gt3.KeyPageDown,
+	0x152: gt3.KeyInsert,
+	0x153: gt3.KeyDelete,
+	0x15B: gt3.KeyLeftSuper,
+	0x15C: gt3.KeyRightSuper,
+	0x15D: gt3.KeyMenu,
+}
@@ -0,0 +1,383 @@
+package rawinput
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"go.spiff.io/gt3"
+)
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procCreateWindowExW         = user32.NewProc("CreateWindowExW")
+	procDestroyWindow           = user32.NewProc("DestroyWindow")
+	procGetMessageW             = user32.NewProc("GetMessageW")
+	procDispatchMessageW        = user32.NewProc("DispatchMessageW")
+	procPostThreadMessageW      = user32.NewProc("PostThreadMessageW")
+	procRegisterRawInputDevices = user32.NewProc("RegisterRawInputDevices")
+	procGetRawInputData         = user32.NewProc("GetRawInputData")
+	procGetRawInputDeviceList   = user32.NewProc("GetRawInputDeviceList")
+	procGetRawInputDeviceInfoW  = user32.NewProc("GetRawInputDeviceInfoW")
+	procGetCurrentThreadID      = kernel32.NewProc("GetCurrentThreadId")
+)
+
+var (
+	errRegister      = errors.New("rawinput: could not register for raw input")
+	errMessageWindow = errors.New("rawinput: could not create message window")
+)
+
+// Raw Input constants, from winuser.h.
+const (
+	wmQuit              = 0x0012
+	wmInputDeviceChange = 0x00FE
+	wmInput             = 0x00FF
+
+	hwndMessage = ^uintptr(2) // HWND_MESSAGE, (HWND)-3
+
+	ridInput         = 0x10000003
+	ridiDeviceName   = 0x20000007
+	ridevInputSink   = 0x00000100
+	ridevDevNotify   = 0x00002000
+	ridevRemove      = 0x00000001
+	rimTypeMouse     = 0
+	rimTypeKeyboard  = 1
+	gidcArrival      = 1
+	gidcRemoval      = 2
+	hidPageGeneric   = 0x01
+	hidUsageMouse    = 0x02
+	hidUsageKeyboard = 0x06
+
+	riKeyBreak = 0x01
+	riKeyE0    = 0x02
+
+	mouseMoveAbsolute = 0x01
+	riMouseWheel      = 0x0400
+	riMouseHWheel     = 0x0800
+	wheelDelta        = 120
+
+	vkPause   = 0x13
+	vkNumLock = 0x90
+	vkFake    = 0xFF // Sent for the extra scancodes of Pause and fake shifts
+)
+
+type rawInputDevice struct {
+	UsagePage uint16
+	Usage     uint16
+	Flags     uint32
+	Target    uintptr
+}
+
+type rawInputDeviceList struct {
+	Device uintptr
+	Type   uint32
+}
+
+type rawInputHeader struct {
+	Type   uint32
+	Size   uint32
+	Device uintptr
+	WParam uintptr
+}
+
+type rawMouse struct {
+	Flags       uint16
+	_           uint16
+	ButtonFlags uint16
+	ButtonData  uint16
+	RawButtons  uint32
+	LastX       int32
+	LastY       int32
+	Extra       uint32
+}
+
+type rawKeyboard struct {
+	MakeCode uint16
+	Flags    uint16
+	Reserved uint16
+	VKey     uint16
+	Message  uint32
+	Extra    uint32
+}
+
+// rawInput is RAWINPUT, with its data union large enough for a RAWMOUSE or RAWKEYBOARD.
+type rawInput struct {
+	Header rawInputHeader
+	Data   [unsafe.Sizeof(rawMouse{}) / 4]uint32
+}
+
+type msg struct {
+	Hwnd    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	Pt      struct{ X, Y int32 }
+	Private uint32
+}
+
+// rawDevice tracks a device's slot and held keys. Raw Input doesn't report key repeats, so a press of a held key is
+// reported as a repeat.
+type rawDevice struct {
+	id   gt3.DeviceID
+	held map[uint16]bool
+}
+
+// messageLoop receives WM_INPUT messages for a message-only window on its own locked thread.
+type messageLoop struct {
+	s       *Source
+	thread  uintptr
+	slots   map[uintptr]int
+	devices map[uintptr]*rawDevice
+}
+
+func (s *Source) open() error {
+	loop := &messageLoop{
+		s:       s,
+		slots:   make(map[uintptr]int),
+		devices: make(map[uintptr]*rawDevice),
+	}
+	if err := loop.connectAll(); err != nil {
+		return err
+	}
+
+	errc := make(chan error, 1)
+	s.wg.Add(1)
+	go loop.run(errc)
+	if err := <-errc; err != nil {
+		return err
+	}
+	s.closers = append(s.closers, loop)
+	return nil
+}
+
+// connectAll registers the keyboards and mice already attached. Raw Input reports later arrivals and removals with
+// WM_INPUT_DEVICE_CHANGE.
+func (l *messageLoop) connectAll() error {
+	var n uint32
+	size := unsafe.Sizeof(rawInputDeviceList{})
+	if r, _, err := procGetRawInputDeviceList.Call(0, uintptr(unsafe.Pointer(&n)), size); int32(r) == -1 {
+		return err
+	}
+	if n == 0 {
+		return ErrNoDevices
+	}
+	list := make([]rawInputDeviceList, n)
+	r, _, err := procGetRawInputDeviceList.Call(uintptr(unsafe.Pointer(&list[0])), uintptr(unsafe.Pointer(&n)), size)
+	if int32(r) == -1 {
+		return err
+	}
+	found := false
+	for _, d := range list[:r] {
+		if d.Type == rimTypeKeyboard || d.Type == rimTypeMouse {
+			l.connect(d.Device)
+			found = true
+		}
+	}
+	if !found {
+		return ErrNoDevices
+	}
+	return nil
+}
+
+func (l *messageLoop) run(errc chan<- error) {
+	defer l.s.wg.Done()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	tid, _, _ := procGetCurrentThreadID.Call()
+	l.thread = tid
+
+	class, _ := syscall.UTF16PtrFromString("STATIC")
+	hwnd, _, _ := procCreateWindowExW.Call(0, uintptr(unsafe.Pointer(class)), 0, 0, 0, 0, 0, 0, hwndMessage, 0, 0, 0)
+	if hwnd == 0 {
+		errc <- errMessageWindow
+		return
+	}
+	defer procDestroyWindow.Call(hwnd)
+
+	// RIDEV_INPUTSINK delivers input even while none of the process's windows are focused.
+	devs := [...]rawInputDevice{
+		{hidPageGeneric, hidUsageKeyboard, ridevInputSink | ridevDevNotify, hwnd},
+		{hidPageGeneric, hidUsageMouse, ridevInputSink | ridevDevNotify, hwnd},
+	}
+	if !registerRawInput(devs[:]) {
+		errc <- errRegister
+		return
+	}
+	defer func() {
+		for i := range devs {
+			devs[i].Flags, devs[i].Target = ridevRemove, 0
+		}
+		registerRawInput(devs[:])
+	}()
+	errc <- nil
+
+	var m msg
+	for {
+		r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(r) <= 0 {
+			return // WM_QUIT or error
+		}
+		switch m.Message {
+		case wmInput:
+			l.input(m.LParam)
+		case wmInputDeviceChange:
+			switch m.WParam {
+			case gidcArrival:
+				l.connect(m.LParam)
+			case gidcRemoval:
+				l.disconnect(m.LParam)
+			}
+		}
+		// Dispatch WM_INPUT to DefWindowProc so that the system can clean up its data.
+		procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+	}
+}
+
+func registerRawInput(devs []rawInputDevice) bool {
+	r, _, _ := procRegisterRawInputDevices.Call(
+		uintptr(unsafe.Pointer(&devs[0])), uintptr(len(devs)), unsafe.Sizeof(devs[0]))
+	return r != 0
+}
+
+// Close stops the message loop.
+func (l *messageLoop) Close() error {
+	if r, _, err := procPostThreadMessageW.Call(l.thread, wmQuit, 0, 0); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// connect registers the device with the given handle. Devices are given slots in order of arrival, and their device
+// interface paths are used as GUIDs so that a reconnected device gets its old ID back.
+func (l *messageLoop) connect(handle uintptr) *rawDevice {
+	if d, ok := l.devices[handle]; ok {
+		return d
+	}
+	slot, ok := l.slots[handle]
+	if !ok {
+		slot = len(l.slots)
+		l.slots[handle] = slot
+	}
+	path := deviceName(handle)
+	name := "Raw Input device"
+	if handle == 0 {
+		name = "Injected input" // Input from SendInput has no device
+	}
+	dev := l.s.Devices.Connect(slot, name, path)
+	d := &rawDevice{id: dev.ID, held: make(map[uint16]bool)}
+	l.devices[handle] = d
+	return d
+}
+
+func (l *messageLoop) disconnect(handle uintptr) {
+	if slot, ok := l.slots[handle]; ok {
+		l.s.Devices.Disconnect(slot)
+		delete(l.devices, handle)
+	}
+}
+
+// deviceName returns the device interface path of the device with the given handle.
+func deviceName(handle uintptr) string {
+	if handle == 0 {
+		return ""
+	}
+	var n uint32
+	procGetRawInputDeviceInfoW.Call(handle, ridiDeviceName, 0, uintptr(unsafe.Pointer(&n)))
+	if n == 0 {
+		return ""
+	}
+	buf := make([]uint16, n)
+	r, _, _ := procGetRawInputDeviceInfoW.Call(handle, ridiDeviceName, uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(unsafe.Pointer(&n)))
+	if int32(r) <= 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
+
+func (l *messageLoop) input(hraw uintptr) {
+	var ri rawInput
+	size := uint32(unsafe.Sizeof(ri))
+	r, _, _ := procGetRawInputData.Call(hraw, ridInput, uintptr(unsafe.Pointer(&ri)), uintptr(unsafe.Pointer(&size)),
+		unsafe.Sizeof(ri.Header))
+	if int32(r) <= 0 {
+		return
+	}
+	when := time.Now()
+	d := l.connect(ri.Header.Device)
+
+	switch ri.Header.Type {
+	case rimTypeKeyboard:
+		l.key(d, (*rawKeyboard)(unsafe.Pointer(&ri.Data)), when)
+	case rimTypeMouse:
+		l.mouse(d, (*rawMouse)(unsafe.Pointer(&ri.Data)), when)
+	}
+}
+
+func (l *messageLoop) key(d *rawDevice, kb *rawKeyboard, when time.Time) {
+	if kb.VKey == vkFake {
+		return
+	}
+	code := kb.MakeCode
+	if kb.Flags&riKeyE0 != 0 {
+		code |= 0x100
+	}
+	key, ok := scancodeKeys[code]
+	switch kb.VKey {
+	case vkPause:
+		key, ok = gt3.KeyPause, true
+	case vkNumLock:
+		key, ok = gt3.KeyNumLock, true
+	}
+	if !ok {
+		key = gt3.KeyUnknown
+	}
+
+	action := gt3.Press
+	switch {
+	case kb.Flags&riKeyBreak != 0:
+		action = gt3.Release
+		delete(d.held, code)
+	case d.held[code]:
+		action = gt3.Repeat
+	default:
+		d.held[code] = true
+	}
+	l.s.post(gt3.RawKeyEvent{Device: d.id, Key: key, Code: int(code), Action: action}, when)
+}
+
+// mouseButtons maps RAWMOUSE button flags to buttons. Each button's down flag is followed by its up flag.
+var mouseButtons = [...]gt3.MouseButton{
+	gt3.MouseButtonLeft,
+	gt3.MouseButtonRight,
+	gt3.MouseButtonMiddle,
+	gt3.MouseButton4,
+	gt3.MouseButton5,
+}
+
+func (l *messageLoop) mouse(d *rawDevice, m *rawMouse, when time.Time) {
+	if m.Flags&mouseMoveAbsolute == 0 && (m.LastX != 0 || m.LastY != 0) {
+		l.s.post(gt3.RawMotionEvent{Device: d.id, DX: float64(m.LastX), DY: float64(m.LastY)}, when)
+	}
+	for i, button := range mouseButtons {
+		if m.ButtonFlags&(1<<(2*i)) != 0 {
+			l.s.post(gt3.RawMouseEvent{Device: d.id, Button: button, Action: gt3.Press}, when)
+		}
+		if m.ButtonFlags&(2<<(2*i)) != 0 {
+			l.s.post(gt3.RawMouseEvent{Device: d.id, Button: button, Action: gt3.Release}, when)
+		}
+	}
+	delta := float64(int16(m.ButtonData)) / wheelDelta
+	if m.ButtonFlags&riMouseWheel != 0 {
+		l.s.post(gt3.RawScrollEvent{Device: d.id, YOff: delta}, when)
+	}
+	if m.ButtonFlags&riMouseHWheel != 0 {
+		l.s.post(gt3.RawScrollEvent{Device: d.id, XOff: delta}, when)
+	}
+}
@@ -0,0 +1,79 @@
+package rawinput
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// eventBuffer is the capacity of a Source's event queue. Events read while the queue is full are dropped.
+const eventBuffer = 256
+
+type timedEvent struct {
+	e    gt3.Event
+	when time.Time
+}
+
+// Source reads raw input from all keyboards and mice it can open. Devices are read on background goroutines and their
+// events queued until the next call to Poll.
+type Source struct {
+	// Devices holds the devices opened by the Source. Device slots are backend-specific device numbers.
+	Devices *gt3.DeviceRegistry
+
+	events  chan timedEvent
+	closers []io.Closer
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// Open opens all readable keyboards and mice, registering them in reg. If reg is nil, a new DeviceRegistry is used.
+func Open(reg *gt3.DeviceRegistry) (*Source, error) {
+	if reg == nil {
+		reg = new(gt3.DeviceRegistry)
+	}
+	s := &Source{
+		Devices: reg,
+		events:  make(chan timedEvent, eventBuffer),
+	}
+	if err := s.open(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Source) post(e gt3.Event, when time.Time) {
+	select {
+	case s.events <- timedEvent{e, when}:
+	default:
+	}
+}
+
+// Poll posts all queued events to handler. It should be called from the main goroutine, typically in the Sim's
+// PreFrame op alongside glfw.PollEvents.
+func (s *Source) Poll(handler gt3.EventHandler) {
+	for {
+		select {
+		case te := <-s.events:
+			handler.Event(te.e, te.when)
+		default:
+			return
+		}
+	}
+}
+
+// Close closes all devices and waits for their readers to stop.
+func (s *Source) Close() error {
+	var err error
+	s.once.Do(func() {
+		for _, c := range s.closers {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			}
+		}
+		s.wg.Wait()
+	})
+	return err
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !windows && !(darwin && cgo)
+
+package rawinput
+
+func (s *Source) open() error {
+	return ErrUnsupported
+}
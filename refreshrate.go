@@ -0,0 +1,68 @@
+package gt3
+
+import "time"
+
+// RefreshRateEvent is posted by MatchRefreshRate when the refresh rate of the monitor a window is on changes, such as
+// when the window is moved to another monitor.
+type RefreshRateEvent struct {
+	Window      *Window
+	Monitor     string
+	RefreshRate int
+}
+
+func (RefreshRateEvent) isEvent() {}
+
+// MonitorOf returns the monitor containing the center of w. If w is fullscreen, its fullscreen monitor is returned.
+// It must be called from the main goroutine.
+func MonitorOf(w *Window) (Monitor, bool) {
+	state := w.State()
+	cx, cy := state.X+state.Width/2, state.Y+state.Height/2
+	for _, m := range Monitors() {
+		if state.Monitor != "" {
+			if m.Name == state.Monitor {
+				return m, true
+			}
+			continue
+		}
+		if cx >= m.X && cx < m.X+m.Width && cy >= m.Y && cy < m.Y+m.Height {
+			return m, true
+		}
+	}
+	return Monitor{}, false
+}
+
+// MatchRefreshRate sets the Sim's render FPS to the refresh rate of the monitor w is on and returns an EventHandler
+// that keeps it matched. The monitor is queried again whenever w moves or is resized, and if its refresh rate changed,
+// the render FPS is updated and a RefreshRateEvent is posted to next before the triggering event. All events are
+// passed on to next. MatchRefreshRate and the returned handler must be used from the main goroutine.
+func (s *Sim) MatchRefreshRate(w *Window, next EventHandler) EventHandler {
+	rate := 0
+	check := func(when time.Time) {
+		m, ok := MonitorOf(w)
+		if !ok || m.RefreshRate <= 0 || m.RefreshRate == rate {
+			return
+		}
+		rate = m.RefreshRate
+		s.SetRenderFPS(rate)
+		if next != nil {
+			next.Event(RefreshRateEvent{w, m.Name, rate}, when)
+		}
+	}
+	check(time.Now())
+
+	return EventHandlerFn(func(e Event, when time.Time) {
+		switch ev := e.(type) {
+		case PositionEvent:
+			if ev.Window == w {
+				check(when)
+			}
+		case ResizeEvent:
+			if ev.Window == w {
+				check(when)
+			}
+		}
+		if next != nil {
+			next.Event(e, when)
+		}
+	})
+}
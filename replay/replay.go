@@ -0,0 +1,505 @@
+// Package replay records the events delivered to an application and replays them in lockstep with a Sim, so that a
+// session can be reproduced for regression testing or played back as a demo.
+//
+// Events are recorded with the sim tick whose Frame op first sees them, relative to the tick recording started at, in
+// a compact binary stream. Replayed events are delivered before the Frame op of the same relative tick, so a
+// deterministic simulation fed only by recorded events reproduces the session exactly. Windows are recorded by the
+// order they were first seen in and mapped back to EventReplayer.Windows on replay.
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+var (
+	// ErrBadStream is returned when a stream isn't a recording or is corrupt.
+	ErrBadStream = errors.New("replay: invalid event stream")
+	// ErrVersion is returned when a recording was made with an unsupported format version.
+	ErrVersion = errors.New("replay: unsupported stream version")
+)
+
+const (
+	magic     = "gt3e"
+	version   = 1
+	maxString = 1 << 24 // Longest string accepted when decoding
+)
+
+// Event kinds, in stream order. Values must not change.
+const (
+	kindKey byte = iota + 1
+	kindChar
+	kindCharMods
+	kindMouse
+	kindCursorPos
+	kindCursorEnter
+	kindScroll
+	kindFocus
+	kindIconify
+	kindMaximize
+	kindResize
+	kindFramebufferSize
+	kindPosition
+	kindContentScale
+	kindClose
+	kindRefresh
+	kindDrop
+	kindPaste
+	kindGamepadButton
+	kindGamepadAxis
+	kindRawKey
+	kindRawMouse
+	kindRawMotion
+	kindRawScroll
+	kindTouch
+)
+
+// EventRecorder is an EventHandler that writes every event it receives to a stream before passing it to Next. Events
+// of types it can't record, such as custom events, are passed on and counted in Skipped. An EventRecorder must only
+// be used from the main goroutine.
+type EventRecorder struct {
+	Next    gt3.EventHandler
+	Skipped int // Events that couldn't be recorded
+
+	sim     *gt3.Sim
+	base    uint64 // Tick recording started at
+	last    uint64 // Relative tick of the last record
+	w       *bufio.Writer
+	buf     []byte
+	windows map[*gt3.Window]uint64
+	err     error
+}
+
+// NewEventRecorder returns an EventRecorder writing to w, recording ticks of s relative to the current tick, and
+// passing events to next. It writes the stream header immediately.
+func NewEventRecorder(w io.Writer, s *gt3.Sim, next gt3.EventHandler) (*EventRecorder, error) {
+	r := &EventRecorder{
+		Next:    next,
+		sim:     s,
+		base:    s.Tick(),
+		w:       bufio.NewWriter(w),
+		windows: make(map[*gt3.Window]uint64),
+	}
+	if _, err := r.w.WriteString(magic); err != nil {
+		return nil, err
+	}
+	if err := r.w.WriteByte(version); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Err returns the first error encountered writing the stream. Once an error occurs, events are no longer recorded.
+func (r *EventRecorder) Err() error {
+	return r.err
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (r *EventRecorder) Flush() error {
+	if r.err != nil {
+		return r.err
+	}
+	r.err = r.w.Flush()
+	return r.err
+}
+
+func (r *EventRecorder) Event(e gt3.Event, when time.Time) {
+	if r.err == nil {
+		r.record(e)
+	}
+	if r.Next != nil {
+		r.Next.Event(e, when)
+	}
+}
+
+func (r *EventRecorder) record(e gt3.Event) {
+	b := r.buf[:0]
+	tick := r.sim.Tick() - r.base
+	b = binary.AppendUvarint(b, tick-r.last)
+
+	switch ev := e.(type) {
+	case gt3.KeyEvent:
+		b = r.window(append(b, kindKey), ev.Window)
+		b = appendInts(b, int64(ev.Key), int64(ev.Code), int64(ev.Action), int64(ev.Mods))
+	case gt3.CharEvent:
+		b = r.window(append(b, kindChar), ev.Window)
+		b = appendInts(b, int64(ev.Char))
+	case gt3.CharModsEvent:
+		b = r.window(append(b, kindCharMods), ev.Window)
+		b = appendInts(b, int64(ev.Char), int64(ev.Mods))
+	case gt3.MouseEvent:
+		b = r.window(append(b, kindMouse), ev.Window)
+		b = appendInts(b, int64(ev.Button), int64(ev.Action), int64(ev.Mods))
+	case gt3.CursorPosEvent:
+		b = r.window(append(b, kindCursorPos), ev.Window)
+		b = appendFloats(b, ev.X, ev.Y)
+	case gt3.CursorEnterEvent:
+		b = r.window(append(b, kindCursorEnter), ev.Window)
+		b = appendBool(b, ev.Entered)
+	case gt3.ScrollEvent:
+		b = r.window(append(b, kindScroll), ev.Window)
+		b = appendFloats(b, ev.XOff, ev.YOff)
+	case gt3.FocusEvent:
+		b = r.window(append(b, kindFocus), ev.Window)
+		b = appendBool(b, ev.Focused)
+	case gt3.IconifyEvent:
+		b = r.window(append(b, kindIconify), ev.Window)
+		b = appendBool(b, ev.Iconified)
+	case gt3.MaximizeEvent:
+		b = r.window(append(b, kindMaximize), ev.Window)
+		b = appendBool(b, ev.Maximized)
+	case gt3.ResizeEvent:
+		b = r.window(append(b, kindResize), ev.Window)
+		b = appendInts(b, int64(ev.Width), int64(ev.Height))
+	case gt3.FramebufferSizeEvent:
+		b = r.window(append(b, kindFramebufferSize), ev.Window)
+		b = appendInts(b, int64(ev.Width), int64(ev.Height))
+	case gt3.PositionEvent:
+		b = r.window(append(b, kindPosition), ev.Window)
+		b = appendInts(b, int64(ev.X), int64(ev.Y))
+	case gt3.ContentScaleEvent:
+		b = r.window(append(b, kindContentScale), ev.Window)
+		b = appendFloats(b, ev.X, ev.Y)
+	case gt3.CloseEvent:
+		b = r.window(append(b, kindClose), ev.Window)
+	case gt3.RefreshEvent:
+		b = r.window(append(b, kindRefresh), ev.Window)
+	case gt3.DropEvent:
+		b = r.window(append(b, kindDrop), ev.Window)
+		b = binary.AppendUvarint(b, uint64(len(ev.Names)))
+		for _, name := range ev.Names {
+			b = appendString(b, name)
+		}
+	case gt3.PasteEvent:
+		b = r.window(append(b, kindPaste), ev.Window)
+		b = appendString(b, ev.Text)
+	case gt3.GamepadButtonEvent:
+		b = append(b, kindGamepadButton)
+		b = appendInts(b, int64(ev.Device), int64(ev.Button), int64(ev.Action))
+	case gt3.GamepadAxisEvent:
+		b = append(b, kindGamepadAxis)
+		b = appendInts(b, int64(ev.Device), int64(ev.Axis))
+		b = appendFloats(b, ev.Value)
+	case gt3.RawKeyEvent:
+		b = append(b, kindRawKey)
+		b = appendInts(b, int64(ev.Device), int64(ev.Key), int64(ev.Code), int64(ev.Action))
+	case gt3.RawMouseEvent:
+		b = append(b, kindRawMouse)
+		b = appendInts(b, int64(ev.Device), int64(ev.Button), int64(ev.Action))
+	case gt3.RawMotionEvent:
+		b = append(b, kindRawMotion)
+		b = appendInts(b, int64(ev.Device))
+		b = appendFloats(b, ev.DX, ev.DY)
+	case gt3.RawScrollEvent:
+		b = append(b, kindRawScroll)
+		b = appendInts(b, int64(ev.Device))
+		b = appendFloats(b, ev.XOff, ev.YOff)
+	case gt3.TouchEvent:
+		b = r.window(append(b, kindTouch), ev.Window)
+		b = appendInts(b, ev.ID, int64(ev.Phase))
+		b = appendFloats(b, ev.X, ev.Y)
+	default:
+		r.Skipped++
+		return
+	}
+
+	r.buf = b
+	r.last = tick
+	_, r.err = r.w.Write(b)
+}
+
+// window appends w's index, assigning it one if it's new. Index 0 is a nil window.
+func (r *EventRecorder) window(b []byte, w *gt3.Window) []byte {
+	if w == nil {
+		return binary.AppendUvarint(b, 0)
+	}
+	id, ok := r.windows[w]
+	if !ok {
+		id = uint64(len(r.windows)) + 1
+		r.windows[w] = id
+	}
+	return binary.AppendUvarint(b, id)
+}
+
+func appendInts(b []byte, vs ...int64) []byte {
+	for _, v := range vs {
+		b = binary.AppendVarint(b, v)
+	}
+	return b
+}
+
+func appendFloats(b []byte, vs ...float64) []byte {
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	return b
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// EventReplayer reads a stream written by an EventRecorder and delivers its events to a handler in lockstep with a
+// Sim. EventReplayer is an op; attach it to a Sim with Attach, or run it from a TickBus subscription. An EventReplayer
+// must only be used from the main goroutine.
+type EventReplayer struct {
+	// Windows maps recorded windows, in the order they were first seen, to windows in the replaying process. Events
+	// for windows without a mapping are delivered with a nil Window.
+	Windows []*gt3.Window
+
+	handler gt3.EventHandler
+	r       *bufio.Reader
+	base    uint64 // Tick replay started at
+	tick    uint64 // Relative tick of the pending event
+	pending gt3.Event
+	done    bool
+	err     error
+}
+
+// NewEventReplayer returns an EventReplayer reading from r and delivering events to handler, replaying ticks relative
+// to the current tick of s. It reads the stream header immediately.
+func NewEventReplayer(r io.Reader, s *gt3.Sim, handler gt3.EventHandler) (*EventReplayer, error) {
+	p := &EventReplayer{
+		handler: handler,
+		r:       bufio.NewReader(r),
+		base:    s.Tick(),
+	}
+	var hdr [len(magic) + 1]byte
+	if _, err := io.ReadFull(p.r, hdr[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrBadStream
+		}
+		return nil, err
+	}
+	if string(hdr[:len(magic)]) != magic {
+		return nil, ErrBadStream
+	}
+	if hdr[len(magic)] != version {
+		return nil, ErrVersion
+	}
+	p.next()
+	return p, nil
+}
+
+// Attach runs the replayer on every tick of s, before its Frame op. Calling cancel detaches it.
+func (p *EventReplayer) Attach(s *gt3.Sim) (cancel func()) {
+	return s.Ticks().EveryTick(p)
+}
+
+// Done reports whether every event in the stream has been delivered or reading it failed.
+func (p *EventReplayer) Done() bool {
+	return p.done
+}
+
+// Err returns the error that stopped replay, if any. Reaching the end of the stream isn't an error.
+func (p *EventReplayer) Err() error {
+	return p.err
+}
+
+func (p *EventReplayer) Do(step, frameTime float64, when time.Time) {
+	p.DoContext(gt3.OpContext{Step: step, FrameTime: frameTime, When: when})
+}
+
+// DoContext delivers the events recorded for the tick in ctx.
+func (p *EventReplayer) DoContext(ctx gt3.OpContext) {
+	tick := ctx.Frame.Tick - p.base
+	for !p.done && p.tick <= tick {
+		p.handler.Event(p.pending, ctx.When)
+		p.next()
+	}
+}
+
+// next reads the next event into pending.
+func (p *EventReplayer) next() {
+	e, tick, err := p.read()
+	switch {
+	case err == io.EOF:
+		p.done, p.pending = true, nil
+	case err != nil:
+		p.done, p.pending, p.err = true, nil, err
+	default:
+		p.pending, p.tick = e, tick
+	}
+}
+
+func (p *EventReplayer) read() (e gt3.Event, tick uint64, err error) {
+	d := decoder{r: p.r}
+	delta, err := binary.ReadUvarint(p.r)
+	if err != nil {
+		return nil, 0, err // io.EOF at a record boundary ends the stream
+	}
+	tick = p.tick + delta
+	kind := d.byte()
+
+	switch kind {
+	case kindKey:
+		w := d.window(p.Windows)
+		key, code := gt3.Key(d.int()), int(d.int())
+		e = gt3.KeyEvent{Window: w, Key: key, Code: code, Action: gt3.Action(d.int()), Mods: gt3.ModifierKey(d.int())}
+	case kindChar:
+		e = gt3.CharEvent{Window: d.window(p.Windows), Char: rune(d.int())}
+	case kindCharMods:
+		w := d.window(p.Windows)
+		e = gt3.CharModsEvent{Window: w, Char: rune(d.int()), Mods: gt3.ModifierKey(d.int())}
+	case kindMouse:
+		w := d.window(p.Windows)
+		button := gt3.MouseButton(d.int())
+		e = gt3.MouseEvent{Window: w, Button: button, Action: gt3.Action(d.int()), Mods: gt3.ModifierKey(d.int())}
+	case kindCursorPos:
+		w := d.window(p.Windows)
+		e = gt3.CursorPosEvent{Window: w, X: d.float(), Y: d.float()}
+	case kindCursorEnter:
+		e = gt3.CursorEnterEvent{Window: d.window(p.Windows), Entered: d.bool()}
+	case kindScroll:
+		w := d.window(p.Windows)
+		e = gt3.ScrollEvent{Window: w, XOff: d.float(), YOff: d.float()}
+	case kindFocus:
+		e = gt3.FocusEvent{Window: d.window(p.Windows), Focused: d.bool()}
+	case kindIconify:
+		e = gt3.IconifyEvent{Window: d.window(p.Windows), Iconified: d.bool()}
+	case kindMaximize:
+		e = gt3.MaximizeEvent{Window: d.window(p.Windows), Maximized: d.bool()}
+	case kindResize:
+		w := d.window(p.Windows)
+		e = gt3.ResizeEvent{Window: w, Width: int(d.int()), Height: int(d.int())}
+	case kindFramebufferSize:
+		w := d.window(p.Windows)
+		e = gt3.FramebufferSizeEvent{Window: w, Width: int(d.int()), Height: int(d.int())}
+	case kindPosition:
+		w := d.window(p.Windows)
+		e = gt3.PositionEvent{Window: w, X: int(d.int()), Y: int(d.int())}
+	case kindContentScale:
+		w := d.window(p.Windows)
+		e = gt3.ContentScaleEvent{Window: w, X: d.float(), Y: d.float()}
+	case kindClose:
+		e = gt3.CloseEvent{Window: d.window(p.Windows)}
+	case kindRefresh:
+		e = gt3.RefreshEvent{Window: d.window(p.Windows)}
+	case kindDrop:
+		ev := gt3.DropEvent{Window: d.window(p.Windows)}
+		n := d.uint()
+		for i := uint64(0); i < n && d.err == nil; i++ {
+			ev.Names = append(ev.Names, d.string())
+		}
+		e = ev
+	case kindPaste:
+		e = gt3.PasteEvent{Window: d.window(p.Windows), Text: d.string()}
+	case kindGamepadButton:
+		dev := gt3.DeviceID(d.int())
+		e = gt3.GamepadButtonEvent{Device: dev, Button: int(d.int()), Action: gt3.Action(d.int())}
+	case kindGamepadAxis:
+		dev := gt3.DeviceID(d.int())
+		e = gt3.GamepadAxisEvent{Device: dev, Axis: int(d.int()), Value: d.float()}
+	case kindRawKey:
+		dev := gt3.DeviceID(d.int())
+		e = gt3.RawKeyEvent{Device: dev, Key: gt3.Key(d.int()), Code: int(d.int()), Action: gt3.Action(d.int())}
+	case kindRawMouse:
+		dev := gt3.DeviceID(d.int())
+		e = gt3.RawMouseEvent{Device: dev, Button: gt3.MouseButton(d.int()), Action: gt3.Action(d.int())}
+	case kindRawMotion:
+		dev := gt3.DeviceID(d.int())
+		e = gt3.RawMotionEvent{Device: dev, DX: d.float(), DY: d.float()}
+	case kindRawScroll:
+		dev := gt3.DeviceID(d.int())
+		e = gt3.RawScrollEvent{Device: dev, XOff: d.float(), YOff: d.float()}
+	case kindTouch:
+		w := d.window(p.Windows)
+		id, phase := d.int(), gt3.TouchPhase(d.int())
+		e = gt3.TouchEvent{Window: w, ID: id, Phase: phase, X: d.float(), Y: d.float()}
+	default:
+		if d.err == nil {
+			d.err = ErrBadStream
+		}
+	}
+
+	if d.err != nil {
+		if d.err == io.EOF {
+			d.err = io.ErrUnexpectedEOF
+		}
+		return nil, 0, d.err
+	}
+	return e, tick, nil
+}
+
+// decoder reads record fields, keeping the first error. Fields are evaluated in order, so decoding into struct literals
+// reads fields in the order they're listed.
+type decoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	b, err := d.r.ReadByte()
+	d.err = err
+	return b
+}
+
+func (d *decoder) uint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *decoder) int() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *decoder) float() float64 {
+	if d.err != nil {
+		return 0
+	}
+	var b [8]byte
+	_, d.err = io.ReadFull(d.r, b[:])
+	return math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+}
+
+func (d *decoder) bool() bool {
+	return d.byte() != 0
+}
+
+func (d *decoder) string() string {
+	n := d.uint()
+	if d.err != nil {
+		return ""
+	}
+	if n > maxString {
+		d.err = ErrBadStream
+		return ""
+	}
+	b := make([]byte, n)
+	_, d.err = io.ReadFull(d.r, b)
+	return string(b)
+}
+
+func (d *decoder) window(windows []*gt3.Window) *gt3.Window {
+	id := d.uint()
+	if id == 0 || id > uint64(len(windows)) {
+		return nil
+	}
+	return windows[id-1]
+}
@@ -0,0 +1,134 @@
+// Package rollback provides the loop-side primitives for rollback netcode: a ring of per-tick state snapshots taken
+// through user hooks, a record of the input used for each tick, correction of past inputs, and resimulation of ticks
+// without rendering.
+package rollback
+
+import (
+	"errors"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// ErrTooOld is returned when correcting or resimulating from a tick that is no longer in the snapshot ring.
+var ErrTooOld = errors.New("rollback: tick is older than the snapshot history")
+
+// ErrFuture is returned when correcting a tick that hasn't been simulated yet.
+var ErrFuture = errors.New("rollback: tick has not been simulated")
+
+// StepFunc simulates a single tick with the given input.
+type StepFunc[I any] func(ctx gt3.OpContext, input I)
+
+type entry[S, I any] struct {
+	tick  uint64
+	valid bool
+	state S // State before the tick was simulated
+	input I
+}
+
+// Rollback records state and input for the last Depth ticks of a simulation, S being the type of a state snapshot and
+// I the type of a tick's input. It must only be used from the main goroutine, and its methods are meant to be called
+// from the Frame op.
+type Rollback[S, I any] struct {
+	save func() S
+	load func(S)
+	step StepFunc[I]
+
+	ring    []entry[S, I]
+	next    uint64 // Next tick to simulate
+	started bool
+	dirty   bool
+	from    uint64 // Earliest corrected tick, if dirty
+}
+
+// New returns a Rollback keeping depth ticks of history. save returns a snapshot of the simulation's state and load
+// restores one; snapshots must not share mutable memory with the live state. step simulates a tick.
+func New[S, I any](depth int, save func() S, load func(S), step StepFunc[I]) *Rollback[S, I] {
+	if depth < 1 {
+		depth = 1
+	}
+	return &Rollback[S, I]{save: save, load: load, step: step, ring: make([]entry[S, I], depth)}
+}
+
+func (r *Rollback[S, I]) entry(tick uint64) *entry[S, I] {
+	e := &r.ring[tick%uint64(len(r.ring))]
+	if !e.valid || e.tick != tick {
+		return nil
+	}
+	return e
+}
+
+// Advance simulates the tick in ctx with input, first resimulating from the earliest corrected tick if any inputs
+// were corrected. The state before the tick and its input are recorded.
+func (r *Rollback[S, I]) Advance(ctx gt3.OpContext, input I) error {
+	if r.dirty {
+		if err := r.Resimulate(ctx, r.from); err != nil {
+			return err
+		}
+	}
+	tick := ctx.Frame.Tick
+	r.simulate(ctx, tick, input)
+	return nil
+}
+
+func (r *Rollback[S, I]) simulate(ctx gt3.OpContext, tick uint64, input I) {
+	e := &r.ring[tick%uint64(len(r.ring))]
+	*e = entry[S, I]{tick: tick, valid: true, state: r.save(), input: input}
+	r.step(ctx, input)
+	r.next, r.started = tick+1, true
+}
+
+// Input returns the input recorded for tick.
+func (r *Rollback[S, I]) Input(tick uint64) (input I, ok bool) {
+	if e := r.entry(tick); e != nil {
+		return e.input, true
+	}
+	return input, false
+}
+
+// Correct replaces the input recorded for a past tick, such as when a remote player's actual input arrives after a
+// prediction was simulated. The simulation is resimulated from the earliest corrected tick on the next Advance.
+func (r *Rollback[S, I]) Correct(tick uint64, input I) error {
+	if !r.started || tick >= r.next {
+		return ErrFuture
+	}
+	e := r.entry(tick)
+	if e == nil {
+		return ErrTooOld
+	}
+	e.input = input
+	if !r.dirty || tick < r.from {
+		r.dirty, r.from = true, tick
+	}
+	return nil
+}
+
+// Resimulate restores the state recorded before tick from and replays every tick from it up to, but not including,
+// the tick in ctx with their recorded inputs. Replayed ticks are passed an OpContext derived from ctx with their own
+// tick, frame time, and wall time. Nothing is rendered while resimulating.
+func (r *Rollback[S, I]) Resimulate(ctx gt3.OpContext, from uint64) error {
+	r.dirty = false
+	if !r.started || from >= r.next {
+		return nil
+	}
+	e := r.entry(from)
+	if e == nil {
+		return ErrTooOld
+	}
+
+	r.load(e.state)
+	end := r.next
+	for tick := from; tick < end; tick++ {
+		e := r.entry(tick)
+		if e == nil {
+			return ErrTooOld
+		}
+		back := float64(ctx.Frame.Tick-tick) * ctx.Step
+		sub := ctx
+		sub.Frame.Tick = tick
+		sub.FrameTime = ctx.FrameTime - back
+		sub.When = ctx.When.Add(-time.Duration(back * float64(time.Second)))
+		r.simulate(sub, tick, e.input)
+	}
+	return nil
+}
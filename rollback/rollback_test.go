@@ -0,0 +1,90 @@
+package rollback
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+func TestRollback(t *testing.T) {
+	const hz = 1.0 / 64
+
+	var (
+		sum   int      // The simulation's state
+		ticks []uint64 // Ticks simulated, in order
+		times []float64
+	)
+	r := New(4, func() int { return sum }, func(s int) { sum = s }, func(ctx gt3.OpContext, input int) {
+		sum += input
+		ticks = append(ticks, ctx.Frame.Tick)
+		times = append(times, ctx.FrameTime)
+	})
+	epoch := time.Unix(0, 0)
+	ctx := func(tick uint64) gt3.OpContext {
+		ft := float64(tick) * hz
+		return gt3.OpContext{
+			Frame:     gt3.FrameID{Tick: tick, Phase: gt3.PhaseFrame},
+			Step:      hz,
+			FrameTime: ft,
+			When:      epoch.Add(time.Duration(ft * float64(time.Second))),
+		}
+	}
+
+	if err := r.Correct(0, 1); !errors.Is(err, ErrFuture) {
+		t.Errorf("Correct(0) before Advance = %v; want %v", err, ErrFuture)
+	}
+	for tick := uint64(0); tick < 5; tick++ {
+		if err := r.Advance(ctx(tick), 1); err != nil {
+			t.Fatalf("Advance(%d) = %v", tick, err)
+		}
+	}
+	if sum != 5 {
+		t.Fatalf("state after 5 ticks = %d; want 5", sum)
+	}
+
+	// Only ticks 1 through 4 are in the ring.
+	if err := r.Correct(0, 10); !errors.Is(err, ErrTooOld) {
+		t.Errorf("Correct(0) = %v; want %v", err, ErrTooOld)
+	}
+	if err := r.Correct(5, 10); !errors.Is(err, ErrFuture) {
+		t.Errorf("Correct(5) = %v; want %v", err, ErrFuture)
+	}
+	if err := r.Correct(3, 100); err != nil {
+		t.Fatalf("Correct(3) = %v", err)
+	}
+	if err := r.Correct(2, 10); err != nil {
+		t.Fatalf("Correct(2) = %v", err)
+	}
+	if sum != 5 {
+		t.Errorf("state after Correct = %d; want 5 until the next Advance", sum)
+	}
+	if in, ok := r.Input(2); !ok || in != 10 {
+		t.Errorf("Input(2) = %d, %t; want 10, true", in, ok)
+	}
+
+	// Advancing resimulates from the earliest corrected tick with the corrected inputs before simulating tick 5.
+	ticks, times = nil, nil
+	if err := r.Advance(ctx(5), 1); err != nil {
+		t.Fatalf("Advance(5) = %v", err)
+	}
+	if want := 2 + 10 + 100 + 1 + 1; sum != want {
+		t.Errorf("state after resimulating = %d; want %d", sum, want)
+	}
+	if want := []uint64{2, 3, 4, 5}; !reflect.DeepEqual(ticks, want) {
+		t.Errorf("simulated ticks %v; want %v", ticks, want)
+	}
+	if want := []float64{2 * hz, 3 * hz, 4 * hz, 5 * hz}; !reflect.DeepEqual(times, want) {
+		t.Errorf("simulated frame times %v; want %v", times, want)
+	}
+
+	// Resimulating from a tick that has left the ring fails without changing state.
+	if err := r.Resimulate(ctx(6), 1); !errors.Is(err, ErrTooOld) {
+		t.Errorf("Resimulate(1) = %v; want %v", err, ErrTooOld)
+	}
+	if err := r.Resimulate(ctx(6), 6); err != nil {
+		t.Errorf("Resimulate(6) = %v; want nil", err)
+	}
+}
@@ -0,0 +1,223 @@
+// Package scene manages a stack of game states, such as a title screen, gameplay, and a pause menu, running the top
+// scene's update, render, and event handling from a Sim's phases.
+//
+// A typical program creates a Stack, attaches it to its Sim, subscribes it to its windows' events, and pushes its
+// first scene:
+//
+//	stack := scene.NewStack()
+//	stack.Attach(sim)
+//	gt3.SetEventMask(wnd, stack, gt3.AllEvents)
+//	stack.Push(title)
+package scene
+
+import (
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// Scene is a game state managed by a Stack. A Stack calls a Scene's methods only from the main goroutine.
+type Scene interface {
+	// Enter is called when the scene is pushed onto a Stack.
+	Enter()
+	// Exit is called when the scene is popped or replaced.
+	Exit()
+	// Update advances the scene by one sim tick of step seconds. Only the top scene is updated.
+	Update(step float64)
+	// Render draws the scene, with alpha being how far render time is between the last two sim ticks.
+	Render(alpha float64)
+	// Event handles an event. Only the top scene receives events.
+	Event(e gt3.Event, when time.Time)
+}
+
+// Pauser is implemented by scenes that want to know when another scene covers them. Pause is called when a scene is
+// pushed on top of the scene and Resume when the scene becomes the top scene again.
+type Pauser interface {
+	Pause()
+	Resume()
+}
+
+// Overlay is implemented by scenes that don't cover the whole screen, such as pause menus and dialogs. If a scene's
+// Overlay method returns true, the scene beneath it is rendered first. The covered scene is still paused.
+type Overlay interface {
+	Overlay() bool
+}
+
+// Stack is a stack of scenes. Changes made to a Stack while it's calling a scene are deferred until the call returns,
+// so scenes may push, pop, or replace themselves from any of their methods. A Stack must only be used from the main
+// goroutine.
+type Stack struct {
+	scenes  []Scene
+	busy    int      // Depth of calls into scenes
+	pending []func() // Changes deferred while busy
+
+	sim           *gt3.Sim
+	frame, render gt3.OpID
+}
+
+// NewStack returns an empty Stack.
+func NewStack() *Stack {
+	return &Stack{}
+}
+
+// Attach adds the Stack's Update and Render ops to s's Frame and Render op lists with priority 0, so that they run
+// after the Sim's Frame and Render ops. A Stack may only be attached to one Sim at a time.
+func (st *Stack) Attach(s *gt3.Sim) {
+	st.Detach()
+	st.sim = s
+	st.frame = s.AddFrameOp(0, gt3.ContextOpFn(st.update))
+	st.render = s.AddRenderOp(0, gt3.ContextOpFn(st.draw))
+}
+
+// Detach removes the Stack's ops from the Sim it's attached to, if any. Its scenes are left as they are.
+func (st *Stack) Detach() {
+	if st.sim == nil {
+		return
+	}
+	st.sim.RemoveOp(st.frame)
+	st.sim.RemoveOp(st.render)
+	st.sim = nil
+}
+
+// Len returns the number of scenes on the stack.
+func (st *Stack) Len() int {
+	return len(st.scenes)
+}
+
+// Top returns the top scene, or nil if the stack is empty.
+func (st *Stack) Top() Scene {
+	if len(st.scenes) == 0 {
+		return nil
+	}
+	return st.scenes[len(st.scenes)-1]
+}
+
+// Push pauses the top scene, if any, and pushes sc onto the stack.
+func (st *Stack) Push(sc Scene) {
+	st.change(func() {
+		if p, ok := st.Top().(Pauser); ok {
+			p.Pause()
+		}
+		st.scenes = append(st.scenes, sc)
+		sc.Enter()
+	})
+}
+
+// Pop pops the top scene, if any, and resumes the scene beneath it.
+func (st *Stack) Pop() {
+	st.change(func() {
+		if st.pop() {
+			st.resume()
+		}
+	})
+}
+
+// Replace pops the top scene, if any, and pushes sc in its place. The scene beneath is neither resumed nor paused.
+func (st *Stack) Replace(sc Scene) {
+	st.change(func() {
+		st.pop()
+		st.scenes = append(st.scenes, sc)
+		sc.Enter()
+	})
+}
+
+// Clear pops every scene, from the top down.
+func (st *Stack) Clear() {
+	st.change(func() {
+		for st.pop() {
+		}
+	})
+}
+
+func (st *Stack) pop() bool {
+	n := len(st.scenes)
+	if n == 0 {
+		return false
+	}
+	sc := st.scenes[n-1]
+	st.scenes[n-1] = nil
+	st.scenes = st.scenes[:n-1]
+	sc.Exit()
+	return true
+}
+
+func (st *Stack) resume() {
+	if p, ok := st.Top().(Pauser); ok {
+		p.Resume()
+	}
+}
+
+// change applies fn now, or once the current call into a scene returns.
+func (st *Stack) change(fn func()) {
+	if st.busy > 0 {
+		st.pending = append(st.pending, fn)
+		return
+	}
+	st.busy++
+	defer st.done()
+	fn()
+}
+
+// enter marks the start of a call into scenes.
+func (st *Stack) enter() {
+	st.busy++
+}
+
+// done marks the end of a call into scenes, applying deferred changes once the outermost call returns.
+func (st *Stack) done() {
+	if st.busy--; st.busy > 0 {
+		return
+	}
+	for len(st.pending) > 0 {
+		fn := st.pending[0]
+		st.pending = st.pending[1:]
+		st.busy++
+		fn()
+		st.busy--
+	}
+	st.pending = nil
+}
+
+// Update updates the top scene.
+func (st *Stack) Update(step float64) {
+	st.enter()
+	defer st.done()
+	if sc := st.Top(); sc != nil {
+		sc.Update(step)
+	}
+}
+
+// Render renders the top scene and, while they're overlays, the scenes beneath it, from the bottom up.
+func (st *Stack) Render(alpha float64) {
+	st.enter()
+	defer st.done()
+	i := len(st.scenes) - 1
+	if i < 0 {
+		return
+	}
+	for ; i > 0; i-- {
+		if o, ok := st.scenes[i].(Overlay); !ok || !o.Overlay() {
+			break
+		}
+	}
+	for _, sc := range st.scenes[i:] {
+		sc.Render(alpha)
+	}
+}
+
+// Event passes e to the top scene. Stack implements gt3.EventHandler so it can be subscribed to a window's events.
+func (st *Stack) Event(e gt3.Event, when time.Time) {
+	st.enter()
+	defer st.done()
+	if sc := st.Top(); sc != nil {
+		sc.Event(e, when)
+	}
+}
+
+func (st *Stack) update(ctx gt3.OpContext) {
+	st.Update(ctx.Step)
+}
+
+func (st *Stack) draw(ctx gt3.OpContext) {
+	st.Render(ctx.Alpha)
+}
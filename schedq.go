@@ -0,0 +1,86 @@
+package gt3
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// schedQueueSize is the capacity of a Sim's sched queue. It must be a power of two.
+const schedQueueSize = 1024
+
+// opQueue is a bounded, lock-free, multi-producer single-consumer queue of ops, based on Dmitry Vyukov's bounded MPMC
+// queue. Each slot's sequence number tells producers and the consumer whether the slot is free or holds a published
+// op.
+type opQueue struct {
+	tail uint64 // Next position to claim, shared by producers
+	_    [56]byte
+	head uint64 // Next position to consume, written only by the consumer
+	_    [56]byte
+
+	slots [schedQueueSize]opSlot
+}
+
+type opSlot struct {
+	seq uint64
+	op  Op
+}
+
+func newOpQueue() *opQueue {
+	q := new(opQueue)
+	for i := range q.slots {
+		q.slots[i].seq = uint64(i)
+	}
+	return q
+}
+
+// push adds op to the queue and reports whether there was room for it. It may be called from any goroutine.
+func (q *opQueue) push(op Op) bool {
+	for {
+		pos := atomic.LoadUint64(&q.tail)
+		slot := &q.slots[pos%schedQueueSize]
+		switch diff := int64(atomic.LoadUint64(&slot.seq) - pos); {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&q.tail, pos, pos+1) {
+				slot.op = op
+				atomic.StoreUint64(&slot.seq, pos+1)
+				return true
+			}
+		case diff < 0:
+			return false // Full
+		}
+		// Another producer claimed the slot first
+	}
+}
+
+// pop removes the op at the head of the queue. It must only be called by the consumer. It returns false if the queue
+// is empty or the op at its head hasn't been published yet.
+func (q *opQueue) pop() (Op, bool) {
+	pos := q.head
+	slot := &q.slots[pos%schedQueueSize]
+	if atomic.LoadUint64(&slot.seq) != pos+1 {
+		return nil, false
+	}
+	op := slot.op
+	slot.op = nil
+	atomic.StoreUint64(&slot.seq, pos+schedQueueSize)
+	atomic.StoreUint64(&q.head, pos+1)
+	return op, true
+}
+
+// SetSchedBudget limits the number of ops scheduled with Sched that run before each sim frame to maxOps, and the time
+// spent running them to maxTime, so that a flood of scheduled ops can't starve the frame. Ops over budget are carried
+// over to the next frame, in order. At least one op runs per frame if any are waiting. A limit <= 0 disables it; both
+// are disabled by default. Ops scheduled with SchedThisFrame are not limited. SetSchedBudget may be called from any
+// goroutine.
+func (s *Sim) SetSchedBudget(maxOps int, maxTime time.Duration) {
+	atomic.StoreInt64(&s.schedMaxOps, int64(maxOps))
+	atomic.StoreInt64(&s.schedMaxTime, int64(maxTime))
+}
+
+// SchedBacklog returns the approximate number of ops scheduled with Sched that have not yet run. It may be called from
+// any goroutine.
+func (s *Sim) SchedBacklog() int {
+	q := s.schedq
+	queued := int64(atomic.LoadUint64(&q.tail) - atomic.LoadUint64(&q.head))
+	return int(queued + atomic.LoadInt64(&s.schedWaiting))
+}
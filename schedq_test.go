@@ -0,0 +1,97 @@
+package gt3
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testOp is an Op identified by its producer and sequence number.
+type testOp struct{ producer, n int }
+
+func (testOp) Do(step, frameTime float64, when time.Time) {}
+
+func TestOpQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		rounds int // Number of times to fill and drain the queue
+		fill   int // Ops pushed per round
+	}{
+		{"Empty", 1, 0},
+		{"One", 1, 1},
+		{"Partial", 3, schedQueueSize / 3},
+		{"Full", 1, schedQueueSize},
+		{"Wraparound", 4, schedQueueSize - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newOpQueue()
+			n := 0
+			for round := 0; round < tt.rounds; round++ {
+				for i := 0; i < tt.fill; i++ {
+					if !q.push(testOp{0, n + i}) {
+						t.Fatalf("round %d: push %d failed", round, i)
+					}
+				}
+				if tt.fill == schedQueueSize && q.push(testOp{0, -1}) {
+					t.Fatalf("round %d: push to a full queue succeeded", round)
+				}
+				for i := 0; i < tt.fill; i++ {
+					op, ok := q.pop()
+					if !ok {
+						t.Fatalf("round %d: pop %d failed", round, i)
+					}
+					if want := (testOp{0, n + i}); op != want {
+						t.Fatalf("round %d: pop %d = %v; want %v", round, i, op, want)
+					}
+				}
+				if op, ok := q.pop(); ok {
+					t.Fatalf("round %d: pop from an empty queue = %v", round, op)
+				}
+				n += tt.fill
+			}
+		})
+	}
+}
+
+func TestOpQueueConcurrent(t *testing.T) {
+	const (
+		producers = 8
+		perProd   = 5000
+	)
+	q := newOpQueue()
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProd; {
+				if q.push(testOp{p, i}) {
+					i++
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	next := make([]int, producers)
+	for got := 0; got < producers*perProd; {
+		op, ok := q.pop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		to := op.(testOp)
+		if to.n != next[to.producer] {
+			t.Fatalf("producer %d: got op %d; want %d", to.producer, to.n, next[to.producer])
+		}
+		next[to.producer]++
+		got++
+	}
+	wg.Wait()
+	if op, ok := q.pop(); ok {
+		t.Fatalf("pop after draining = %v", op)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build sdl2
+
+package sdl2
+
+import (
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"go.spiff.io/gt3"
+)
+
+// Clock is a gt3.Clock reading SDL's high-resolution performance counter, so that a Sim can run without GLFW.
+type Clock struct {
+	start uint64
+	freq  float64
+}
+
+var _ gt3.Clock = (*Clock)(nil)
+
+// NewClock returns a Clock whose time starts at zero. sdl.Init must have been called beforehand.
+func NewClock() *Clock {
+	return &Clock{start: sdl.GetPerformanceCounter(), freq: float64(sdl.GetPerformanceFrequency())}
+}
+
+// Now returns the seconds since the Clock was created.
+func (c *Clock) Now() float64 {
+	return float64(sdl.GetPerformanceCounter()-c.start) / c.freq
+}
+
+// Wall returns the system's wall clock time.
+func (c *Clock) Wall() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,15 @@
+// Package sdl2 is an SDL2-backed alternative to gt3's GLFW event provider and window helpers. It translates SDL events
+// into gt3 Events so that EventHandlers and Sim ops written against gt3 work unchanged.
+//
+// The package is only built with the sdl2 build tag, since it requires the SDL2 development libraries:
+//
+//	go build -tags sdl2
+//
+// Sims read time from GLFW's timer by default, so a Sim using this backend should be given a Clock reading SDL's timer
+// with SetClock instead:
+//
+//	sim.SetClock(sdl2.NewClock())
+//
+// Window fields of translated events are the Handle of the Window the event was sent to, or nil for windows not
+// created with CreateWindow.
+package sdl2
@@ -0,0 +1,125 @@
+//go:build sdl2
+
+package sdl2
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"go.spiff.io/gt3"
+)
+
+// EventSource polls SDL for events and posts them to an EventHandler as gt3 Events.
+type EventSource struct {
+	handler gt3.EventHandler
+	types   map[reflect.Type]bool // nil if all event types are posted
+}
+
+// NewEventSource returns an EventSource that posts events to handler. As with gt3.SetEventCallbacks, eventTypes are
+// zero values of the event types to post. If no eventTypes are given, all events are posted.
+func NewEventSource(handler gt3.EventHandler, eventTypes ...gt3.Event) *EventSource {
+	s := &EventSource{handler: handler}
+	if len(eventTypes) > 0 {
+		s.types = make(map[reflect.Type]bool, len(eventTypes))
+		for _, e := range eventTypes {
+			s.types[reflect.TypeOf(e)] = true
+		}
+	}
+	return s
+}
+
+// Poll processes all pending SDL events. It is the equivalent of glfw.PollEvents and must be called from the main
+// thread, typically in the Sim's PreFrame op.
+func (s *EventSource) Poll() {
+	for ev := sdl.PollEvent(); ev != nil; ev = sdl.PollEvent() {
+		s.translate(ev)
+	}
+}
+
+func (s *EventSource) post(e gt3.Event) {
+	if s.types != nil && !s.types[reflect.TypeOf(e)] {
+		return
+	}
+	s.handler.Event(e, time.Now())
+}
+
+func (s *EventSource) translate(ev sdl.Event) {
+	switch ev := ev.(type) {
+	case *sdl.QuitEvent:
+		s.post(gt3.CloseEvent{})
+	case *sdl.WindowEvent:
+		s.translateWindow(ev)
+	case *sdl.KeyboardEvent:
+		action := gt3.Release
+		if ev.State == sdl.PRESSED {
+			action = gt3.Press
+			if ev.Repeat != 0 {
+				action = gt3.Repeat
+			}
+		}
+		s.post(gt3.KeyEvent{
+			Window: windowFor(ev.WindowID),
+			Key:    Key(ev.Keysym.Scancode),
+			Code:   int(ev.Keysym.Scancode),
+			Action: action,
+			Mods:   Mods(sdl.Keymod(ev.Keysym.Mod)),
+		})
+	case *sdl.TextInputEvent:
+		w, mods := windowFor(ev.WindowID), Mods(sdl.GetModState())
+		for _, r := range ev.GetText() {
+			s.post(gt3.CharEvent{Window: w, Char: r})
+			s.post(gt3.CharModsEvent{Window: w, Char: r, Mods: mods})
+		}
+	case *sdl.MouseMotionEvent:
+		s.post(gt3.CursorPosEvent{Window: windowFor(ev.WindowID), X: float64(ev.X), Y: float64(ev.Y)})
+	case *sdl.MouseButtonEvent:
+		action := gt3.Release
+		if ev.State == sdl.PRESSED {
+			action = gt3.Press
+		}
+		s.post(gt3.MouseEvent{
+			Window: windowFor(ev.WindowID),
+			Button: MouseButton(ev.Button),
+			Action: action,
+			Mods:   Mods(sdl.GetModState()),
+		})
+	case *sdl.MouseWheelEvent:
+		s.post(gt3.ScrollEvent{Window: windowFor(ev.WindowID), XOff: float64(ev.X), YOff: float64(ev.Y)})
+	case *sdl.DropEvent:
+		if ev.Type == sdl.DROPFILE {
+			s.post(gt3.DropEvent{Window: windowFor(ev.WindowID), Names: []string{ev.File}})
+		}
+	}
+}
+
+func (s *EventSource) translateWindow(ev *sdl.WindowEvent) {
+	w := windowFor(ev.WindowID)
+	switch ev.Event {
+	case sdl.WINDOWEVENT_EXPOSED:
+		s.post(gt3.RefreshEvent{Window: w})
+	case sdl.WINDOWEVENT_MOVED:
+		s.post(gt3.PositionEvent{Window: w, X: int(ev.Data1), Y: int(ev.Data2)})
+	case sdl.WINDOWEVENT_RESIZED:
+		s.post(gt3.ResizeEvent{Window: w, Width: int(ev.Data1), Height: int(ev.Data2)})
+	case sdl.WINDOWEVENT_SIZE_CHANGED:
+		if sw, err := sdl.GetWindowFromID(ev.WindowID); err == nil {
+			width, height := sw.GLGetDrawableSize()
+			s.post(gt3.FramebufferSizeEvent{Window: w, Width: int(width), Height: int(height)})
+		}
+	case sdl.WINDOWEVENT_MINIMIZED:
+		s.post(gt3.IconifyEvent{Window: w, Iconified: true})
+	case sdl.WINDOWEVENT_RESTORED:
+		s.post(gt3.IconifyEvent{Window: w, Iconified: false})
+	case sdl.WINDOWEVENT_ENTER:
+		s.post(gt3.CursorEnterEvent{Window: w, Entered: true})
+	case sdl.WINDOWEVENT_LEAVE:
+		s.post(gt3.CursorEnterEvent{Window: w, Entered: false})
+	case sdl.WINDOWEVENT_FOCUS_GAINED:
+		s.post(gt3.FocusEvent{Window: w, Focused: true})
+	case sdl.WINDOWEVENT_FOCUS_LOST:
+		s.post(gt3.FocusEvent{Window: w, Focused: false})
+	case sdl.WINDOWEVENT_CLOSE:
+		s.post(gt3.CloseEvent{Window: w})
+	}
+}
@@ -0,0 +1,114 @@
+//go:build sdl2
+
+package sdl2
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+	"go.spiff.io/gt3"
+)
+
+// Mods converts SDL modifier state to gt3 modifier keys.
+func Mods(mod sdl.Keymod) (mods gt3.ModifierKey) {
+	if mod&sdl.KMOD_SHIFT != 0 {
+		mods |= gt3.ModShift
+	}
+	if mod&sdl.KMOD_CTRL != 0 {
+		mods |= gt3.ModControl
+	}
+	if mod&sdl.KMOD_ALT != 0 {
+		mods |= gt3.ModAlt
+	}
+	if mod&sdl.KMOD_GUI != 0 {
+		mods |= gt3.ModSuper
+	}
+	return mods
+}
+
+// MouseButton converts an SDL mouse button index to a gt3 mouse button.
+func MouseButton(button uint8) gt3.MouseButton {
+	switch button {
+	case sdl.BUTTON_LEFT:
+		return gt3.MouseButtonLeft
+	case sdl.BUTTON_MIDDLE:
+		return gt3.MouseButtonMiddle
+	case sdl.BUTTON_RIGHT:
+		return gt3.MouseButtonRight
+	}
+	// X1, X2, and any further buttons follow on from the fourth button.
+	return gt3.MouseButton4 + gt3.MouseButton(button-sdl.BUTTON_X1)
+}
+
+// Key converts an SDL scancode to a gt3 key. Scancodes are used rather than keycodes since, like gt3 keys, they
+// name physical key positions on a US keyboard layout. Unmapped scancodes return gt3.KeyUnknown.
+func Key(code sdl.Scancode) gt3.Key {
+	switch {
+	case code >= sdl.SCANCODE_A && code <= sdl.SCANCODE_Z:
+		return gt3.KeyA + gt3.Key(code-sdl.SCANCODE_A)
+	case code >= sdl.SCANCODE_1 && code <= sdl.SCANCODE_9:
+		return gt3.Key1 + gt3.Key(code-sdl.SCANCODE_1)
+	case code >= sdl.SCANCODE_F1 && code <= sdl.SCANCODE_F12:
+		return gt3.KeyF1 + gt3.Key(code-sdl.SCANCODE_F1)
+	case code >= sdl.SCANCODE_F13 && code <= sdl.SCANCODE_F24:
+		return gt3.KeyF13 + gt3.Key(code-sdl.SCANCODE_F13)
+	case code >= sdl.SCANCODE_KP_1 && code <= sdl.SCANCODE_KP_9:
+		return gt3.KeyKP1 + gt3.Key(code-sdl.SCANCODE_KP_1)
+	}
+
+	if key, ok := scancodeKeys[code]; ok {
+		return key
+	}
+	return gt3.KeyUnknown
+}
+
+var scancodeKeys = map[sdl.Scancode]gt3.Key{
+	sdl.SCANCODE_0:              gt3.Key0,
+	sdl.SCANCODE_RETURN:         gt3.KeyEnter,
+	sdl.SCANCODE_ESCAPE:         gt3.KeyEscape,
+	sdl.SCANCODE_BACKSPACE:      gt3.KeyBackspace,
+	sdl.SCANCODE_TAB:            gt3.KeyTab,
+	sdl.SCANCODE_SPACE:          gt3.KeySpace,
+	sdl.SCANCODE_MINUS:          gt3.KeyMinus,
+	sdl.SCANCODE_EQUALS:         gt3.KeyEqual,
+	sdl.SCANCODE_LEFTBRACKET:    gt3.KeyLeftBracket,
+	sdl.SCANCODE_RIGHTBRACKET:   gt3.KeyRightBracket,
+	sdl.SCANCODE_BACKSLASH:      gt3.KeyBackslash,
+	sdl.SCANCODE_SEMICOLON:      gt3.KeySemicolon,
+	sdl.SCANCODE_APOSTROPHE:     gt3.KeyApostrophe,
+	sdl.SCANCODE_GRAVE:          gt3.KeyGraveAccent,
+	sdl.SCANCODE_COMMA:          gt3.KeyComma,
+	sdl.SCANCODE_PERIOD:         gt3.KeyPeriod,
+	sdl.SCANCODE_SLASH:          gt3.KeySlash,
+	sdl.SCANCODE_CAPSLOCK:       gt3.KeyCapsLock,
+	sdl.SCANCODE_PRINTSCREEN:    gt3.KeyPrintScreen,
+	sdl.SCANCODE_SCROLLLOCK:     gt3.KeyScrollLock,
+	sdl.SCANCODE_PAUSE:          gt3.KeyPause,
+	sdl.SCANCODE_INSERT:         gt3.KeyInsert,
+	sdl.SCANCODE_HOME:           gt3.KeyHome,
+	sdl.SCANCODE_PAGEUP:         gt3.KeyPageUp,
+	sdl.SCANCODE_DELETE:         gt3.KeyDelete,
+	sdl.SCANCODE_END:            gt3.KeyEnd,
+	sdl.SCANCODE_PAGEDOWN:       gt3.KeyPageDown,
+	sdl.SCANCODE_RIGHT:          gt3.KeyRight,
+	sdl.SCANCODE_LEFT:           gt3.KeyLeft,
+	sdl.SCANCODE_DOWN:           gt3.KeyDown,
+	sdl.SCANCODE_UP:             gt3.KeyUp,
+	sdl.SCANCODE_NUMLOCKCLEAR:   gt3.KeyNumLock,
+	sdl.SCANCODE_KP_DIVIDE:      gt3.KeyKPDivide,
+	sdl.SCANCODE_KP_MULTIPLY:    gt3.KeyKPMultiply,
+	sdl.SCANCODE_KP_MINUS:       gt3.KeyKPSubtract,
+	sdl.SCANCODE_KP_PLUS:        gt3.KeyKPAdd,
+	sdl.SCANCODE_KP_ENTER:       gt3.KeyKPEnter,
+	sdl.SCANCODE_KP_0:           gt3.KeyKP0,
+	sdl.SCANCODE_KP_PERIOD:      gt3.KeyKPDecimal,
+	sdl.SCANCODE_KP_EQUALS:      gt3.KeyKPEqual,
+	sdl.SCANCODE_NONUSBACKSLASH: gt3.KeyWorld1,
+	sdl.SCANCODE_APPLICATION:    gt3.KeyMenu,
+	sdl.SCANCODE_LCTRL:          gt3.KeyLeftControl,
+	sdl.SCANCODE_LSHIFT:         gt3.KeyLeftShift,
+	sdl.SCANCODE_LALT:           gt3.KeyLeftAlt,
+	sdl.SCANCODE_LGUI:           gt3.KeyLeftSuper,
+	sdl.SCANCODE_RCTRL:          gt3.KeyRightControl,
+	sdl.SCANCODE_RSHIFT:         gt3.KeyRightShift,
+	sdl.SCANCODE_RALT:           gt3.KeyRightAlt,
+	sdl.SCANCODE_RGUI:           gt3.KeyRightSuper,
+}
@@ -0,0 +1,108 @@
+//go:build sdl2
+
+package sdl2
+
+import (
+	"sync"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"go.spiff.io/gt3"
+)
+
+// Window is an SDL window with an OpenGL context.
+type Window struct {
+	*sdl.Window
+	Context sdl.GLContext
+
+	id     uint32
+	handle *gt3.Window
+}
+
+var _ gt3.RenderContext = (*Window)(nil)
+
+var (
+	windowsMu sync.Mutex
+	windows   = map[uint32]*gt3.Window{}
+)
+
+// windowFor returns the gt3 Window for an SDL window ID, or nil if the window wasn't created by CreateWindow.
+func windowFor(id uint32) *gt3.Window {
+	windowsMu.Lock()
+	defer windowsMu.Unlock()
+	return windows[id]
+}
+
+// CreateWindow creates a resizable, high-DPI SDL window with an OpenGL context and makes the context current. Additional
+// SDL window flags may be passed in flags. sdl.Init must have been called with sdl.INIT_VIDEO beforehand.
+func CreateWindow(title string, width, height int, flags uint32) (*Window, error) {
+	flags |= sdl.WINDOW_OPENGL | sdl.WINDOW_RESIZABLE | sdl.WINDOW_ALLOW_HIGHDPI
+	w, err := sdl.CreateWindow(title, sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, int32(width), int32(height), flags)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := w.GLCreateContext()
+	if err != nil {
+		w.Destroy()
+		return nil, err
+	}
+
+	id, err := w.GetID()
+	if err != nil {
+		sdl.GLDeleteContext(ctx)
+		w.Destroy()
+		return nil, err
+	}
+
+	wnd := &Window{Window: w, Context: ctx, id: id}
+	wnd.handle = gt3.WrapWindow(wnd)
+
+	windowsMu.Lock()
+	windows[id] = wnd.handle
+	windowsMu.Unlock()
+
+	return wnd, nil
+}
+
+// Handle returns the gt3 Window for w. Events sent to w carry this Window.
+func (w *Window) Handle() *gt3.Window {
+	return w.handle
+}
+
+// MakeCurrent makes the window's OpenGL context current on the calling thread.
+func (w *Window) MakeCurrent() error {
+	return w.GLMakeCurrent(w.Context)
+}
+
+// MakeContextCurrent is MakeCurrent without its error, so that Window implements gt3.RenderContext and the Sim switches
+// to its context when rendering it. Use MakeCurrent to check for failure.
+func (w *Window) MakeContextCurrent() {
+	w.MakeCurrent()
+}
+
+// SwapBuffers swaps the window's front and back buffers.
+func (w *Window) SwapBuffers() {
+	w.GLSwap()
+}
+
+// GetSize returns the size of the window in screen coordinates.
+func (w *Window) GetSize() (width, height int) {
+	wd, ht := w.Window.GetSize()
+	return int(wd), int(ht)
+}
+
+// GetFramebufferSize returns the size of the window's drawable in pixels.
+func (w *Window) GetFramebufferSize() (width, height int) {
+	wd, ht := w.GLGetDrawableSize()
+	return int(wd), int(ht)
+}
+
+// Destroy deletes the window's OpenGL context and destroys the window.
+func (w *Window) Destroy() {
+	windowsMu.Lock()
+	delete(windows, w.id)
+	windowsMu.Unlock()
+
+	sdl.GLDeleteContext(w.Context)
+	w.Window.Destroy()
+}
@@ -0,0 +1,158 @@
+// Package shortcut manages application shortcuts, such as Ctrl+S to save, for editor-style applications. Shortcuts are
+// registered per context, conflicting assignments are rejected at registration, and matched shortcuts are dispatched
+// as ShortcutEvents. Shortcuts are kept apart from gameplay action bindings.
+package shortcut
+
+import (
+	"fmt"
+	"time"
+
+	"go.spiff.io/gt3"
+	"go.spiff.io/gt3/input"
+)
+
+// Global is the context whose shortcuts are active regardless of the active contexts.
+const Global = ""
+
+// ShortcutEvent is posted when a registered shortcut is pressed.
+type ShortcutEvent struct {
+	gt3.CustomEvent
+	Name    string
+	Context string
+	Binding input.Binding
+}
+
+// ConflictError is returned when registering a shortcut whose binding is already used in the same context, or in the
+// global context, or whose name is already registered.
+type ConflictError struct {
+	Name     string
+	Existing string // Name of the shortcut already registered
+	Context  string
+	Binding  input.Binding
+}
+
+func (e *ConflictError) Error() string {
+	if e.Name == e.Existing {
+		return fmt.Sprintf("shortcut: %q is already registered", e.Name)
+	}
+	return fmt.Sprintf("shortcut: %v for %q conflicts with %q in context %q", e.Binding, e.Name, e.Existing, e.Context)
+}
+
+type shortcut struct {
+	name    string
+	context string
+	binding input.Binding
+}
+
+// Manager matches key and mouse events against registered shortcuts. Shortcuts in active contexts take precedence
+// over global shortcuts, and later-activated contexts take precedence over earlier ones. A Manager must only be used
+// from the main goroutine.
+type Manager struct {
+	// Next receives ShortcutEvents and all events that don't match a shortcut.
+	Next gt3.EventHandler
+
+	shortcuts []shortcut
+	active    []string
+}
+
+// New returns a Manager posting to next.
+func New(next gt3.EventHandler) *Manager {
+	return &Manager{Next: next}
+}
+
+// Register adds a shortcut to a context. It returns a *ConflictError if the name is taken or the binding conflicts
+// with another shortcut that could be active at the same time: one in the same context, or any shortcut if either is
+// global.
+func (m *Manager) Register(context, name string, b input.Binding) error {
+	for _, sc := range m.shortcuts {
+		if sc.name == name {
+			return &ConflictError{Name: name, Existing: name, Context: sc.context, Binding: sc.binding}
+		}
+		if sc.binding != b {
+			continue
+		}
+		if sc.context == context || sc.context == Global || context == Global {
+			return &ConflictError{Name: name, Existing: sc.name, Context: sc.context, Binding: b}
+		}
+	}
+	m.shortcuts = append(m.shortcuts, shortcut{name, context, b})
+	return nil
+}
+
+// RegisterString is the same as Register, but parses the binding with input.ParseBinding.
+func (m *Manager) RegisterString(context, name, binding string) error {
+	b, err := input.ParseBinding(binding)
+	if err != nil {
+		return err
+	}
+	return m.Register(context, name, b)
+}
+
+// Unregister removes the shortcut with the given name.
+func (m *Manager) Unregister(name string) {
+	for i, sc := range m.shortcuts {
+		if sc.name == name {
+			m.shortcuts = append(m.shortcuts[:i], m.shortcuts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Activate activates a context's shortcuts. Activating an active context moves it to the top.
+func (m *Manager) Activate(context string) {
+	m.Deactivate(context)
+	m.active = append(m.active, context)
+}
+
+// Deactivate deactivates a context's shortcuts.
+func (m *Manager) Deactivate(context string) {
+	for i, c := range m.active {
+		if c == context {
+			m.active = append(m.active[:i], m.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// Lookup returns the shortcut bound to b in the active contexts.
+func (m *Manager) Lookup(b input.Binding) (name, context string, ok bool) {
+	for i := len(m.active) - 1; i >= -1; i-- {
+		ctx := Global
+		if i >= 0 {
+			ctx = m.active[i]
+		}
+		for _, sc := range m.shortcuts {
+			if sc.context == ctx && sc.binding == b {
+				return sc.name, sc.context, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Event posts a ShortcutEvent for key and mouse button presses matching a shortcut, and passes all other events to
+// Next.
+func (m *Manager) Event(e gt3.Event, when time.Time) {
+	var b input.Binding
+	switch ev := e.(type) {
+	case gt3.KeyEvent:
+		if ev.Action != gt3.Press {
+			break
+		}
+		b = input.Binding{Device: input.DeviceKeyboard, Key: ev.Key, Mods: ev.Mods}
+		if name, ctx, ok := m.Lookup(b); ok {
+			m.Next.Event(ShortcutEvent{Name: name, Context: ctx, Binding: b}, when)
+			return
+		}
+	case gt3.MouseEvent:
+		if ev.Action != gt3.Press {
+			break
+		}
+		b = input.Binding{Device: input.DeviceMouse, Button: ev.Button, Mods: ev.Mods}
+		if name, ctx, ok := m.Lookup(b); ok {
+			m.Next.Event(ShortcutEvent{Name: name, Context: ctx, Binding: b}, when)
+			return
+		}
+	}
+	m.Next.Event(e, when)
+}
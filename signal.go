@@ -0,0 +1,43 @@
+package gt3
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// StopOnSignal stops the Sim when the process receives one of the given signals. If no signals are given, SIGINT and
+// SIGTERM are used. Stopping the Sim causes Run to run its OnStop ops on the main goroutine and return, allowing the
+// program to exit cleanly instead of dying mid-frame. If a second signal is received before cancel is called, the
+// process exits immediately with status 1.
+//
+// The returned cancel function stops listening for signals.
+func StopOnSignal(s *Sim, sigs ...os.Signal) (cancel func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case <-ch:
+			s.Stop()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-ch:
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,158 @@
+package gt3
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpikeReport describes a loop iteration that took longer than a Sim's spike threshold.
+type SpikeReport struct {
+	Tick     uint64
+	Duration time.Duration
+
+	// Phases holds the total time spent in each phase during the iteration.
+	Phases map[Phase]time.Duration
+
+	// SlowestPhase, SlowestOp, and SlowestTime describe the single slowest op invocation. SlowestOp is the op's type.
+	SlowestPhase Phase
+	SlowestOp    string
+	SlowestTime  time.Duration
+
+	// Stack is a stack sample of the main goroutine taken once the iteration passed the threshold. It is nil if the
+	// iteration finished before a sample could be taken.
+	Stack []byte
+}
+
+func (r *SpikeReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "spike: tick %d, %.2f ms\n", r.Tick, float64(r.Duration)/float64(time.Millisecond))
+	fmt.Fprintf(&b, "slowest op %s in %v took %v", r.SlowestOp, r.SlowestPhase, r.SlowestTime)
+	for p := PhasePreFrame; p <= PhaseStop; p++ {
+		if d, ok := r.Phases[p]; ok {
+			fmt.Fprintf(&b, "; %v=%v", p, d)
+		}
+	}
+	if r.Stack != nil {
+		b.WriteByte('\n')
+		b.Write(r.Stack)
+	}
+	return b.String()
+}
+
+// SpikeFunc receives reports of slow loop iterations. It is called on the main goroutine at the end of the slow
+// iteration.
+type SpikeFunc func(*SpikeReport)
+
+// LogSpike is a SpikeFunc that writes reports to the standard logger.
+func LogSpike(r *SpikeReport) {
+	log.Print(r)
+}
+
+// SetSpikeLogger configures the Sim to time each phase and op of every loop iteration and report iterations taking
+// longer than threshold to fn. While an iteration runs past the threshold, a stack sample of the main goroutine is
+// taken from a separate goroutine. A threshold <= 0 or nil fn disables spike logging. SetSpikeLogger must be called
+// before Run.
+func (s *Sim) SetSpikeLogger(threshold time.Duration, fn SpikeFunc) {
+	if threshold <= 0 || fn == nil {
+		s.spike = nil
+		return
+	}
+	s.spike = &spikeLogger{
+		watchdog: watchdog{threshold: threshold},
+		report:   fn,
+	}
+	s.spike.fn = s.spike.sample
+}
+
+type spikeLogger struct {
+	watchdog // Samples the stack once the threshold is passed
+
+	report SpikeFunc
+	start  time.Time
+	cur    SpikeReport
+
+	mu    sync.Mutex
+	stack []byte
+}
+
+func (l *spikeLogger) sample(_ time.Duration, stack []byte) {
+	l.mu.Lock()
+	l.stack = stack
+	l.mu.Unlock()
+}
+
+func (l *spikeLogger) begin(tick uint64) {
+	l.mu.Lock()
+	l.stack = nil
+	l.mu.Unlock()
+
+	l.cur = SpikeReport{Tick: tick, Phases: l.cur.Phases}
+	for p := range l.cur.Phases {
+		delete(l.cur.Phases, p)
+	}
+	l.start = time.Now()
+	l.watchdog.begin()
+}
+
+func (l *spikeLogger) op(phase Phase, op Op, d time.Duration) {
+	if l.cur.Phases == nil {
+		l.cur.Phases = make(map[Phase]time.Duration)
+	}
+	l.cur.Phases[phase] += d
+	if d > l.cur.SlowestTime {
+		l.cur.SlowestPhase, l.cur.SlowestTime = phase, d
+		l.cur.SlowestOp = fmt.Sprintf("%T", op)
+	}
+}
+
+func (l *spikeLogger) end() {
+	l.watchdog.end()
+	l.cur.Duration = time.Since(l.start)
+	if l.cur.Duration < l.threshold {
+		return
+	}
+
+	l.mu.Lock()
+	l.cur.Stack = l.stack
+	l.mu.Unlock()
+
+	report := l.cur
+	report.Phases = make(map[Phase]time.Duration, len(l.cur.Phases))
+	for p, d := range l.cur.Phases {
+		report.Phases[p] = d
+	}
+	l.report(&report)
+}
+
+// runOp runs op, timing it if spike logging is enabled, measuring its allocations if allocation tracking is enabled,
+// and tracing it if the Sim has a Tracer.
+func (s *Sim) runOp(op Op, ctx OpContext) {
+	if op == nil || (s.spike == nil && s.allocs == nil && s.tracer == nil) {
+		RunOp(op, ctx)
+		return
+	}
+	if s.tracer != nil {
+		defer s.opSpan(op, ctx)()
+	}
+
+	var (
+		start  time.Time
+		before uint64
+	)
+	if s.allocs != nil {
+		before = s.allocs.read()
+	}
+	if s.spike != nil {
+		start = time.Now()
+	}
+	RunOp(op, ctx)
+	if s.spike != nil {
+		s.spike.op(ctx.Frame.Phase, op, time.Since(start))
+	}
+	if s.allocs != nil {
+		s.allocs.op(ctx.Frame.Phase, s.allocs.read()-before)
+	}
+}
@@ -0,0 +1,46 @@
+package gt3
+
+// State holds a value of type T as it was at the end of the previous and current sim ticks, codifying the pattern of
+// interpolating between the last two simulated states when rendering. Frame ops modify the current value through
+// Current, and render ops read both values through Interp. A State must only be used from the main goroutine.
+type State[T any] struct {
+	sim      *Sim
+	prev     T
+	cur      T
+	copy     func(dst, src *T)
+	modified uint64 // Tick+1 during which cur was last obtained through Current
+}
+
+// NewState returns a State for s whose previous and current values are initial. copy copies one value into another;
+// it must deep-copy any memory the values share, such as slices or maps. If copy is nil, values are copied by
+// assignment.
+func NewState[T any](s *Sim, initial T, copy func(dst, src *T)) *State[T] {
+	if copy == nil {
+		copy = func(dst, src *T) { *dst = *src }
+	}
+	st := &State[T]{sim: s, copy: copy}
+	copy(&st.prev, &initial)
+	copy(&st.cur, &initial)
+	return st
+}
+
+// Current returns the current tick's value for modification. The first call during a tick saves the value left by
+// the previous tick as the previous value.
+func (st *State[T]) Current() *T {
+	tick := st.sim.Tick() + 1
+	if st.modified != tick {
+		st.copy(&st.prev, &st.cur)
+		st.modified = tick
+	}
+	return &st.cur
+}
+
+// Interp returns the previous and current values and how far between them rendering is, from 0 to 1, for a render op
+// run with ctx. If the value wasn't modified during the last sim tick, both values are the current value. The values
+// must not be modified.
+func (st *State[T]) Interp(ctx OpContext) (prev, cur *T, alpha float64) {
+	if st.modified != st.sim.Tick() {
+		return &st.cur, &st.cur, 1
+	}
+	return &st.prev, &st.cur, st.sim.alpha(ctx.Step, ctx.FrameTime)
+}
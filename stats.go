@@ -0,0 +1,110 @@
+package gt3
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsWindow is the number of renders FrameStats are computed over.
+const statsWindow = 128
+
+// FrameStats are rolling statistics over a Sim's recent renders, as returned by Sim.Stats. Frame times are the
+// intervals between consecutive renders.
+type FrameStats struct {
+	Frames int // Number of frame times the statistics cover
+
+	Avg time.Duration
+	Min time.Duration
+	Max time.Duration
+	P99 time.Duration
+
+	RenderFPS      float64 // Renders per second
+	TicksPerSecond float64 // Sim ticks per second
+
+	// Dropped is the number of frame times in the window longer than 1.5 times the target frame time: the render
+	// interval if render FPS is limited, or the sim step otherwise.
+	Dropped int
+	// SkippedTicks is the total number of sim ticks skipped because of SetMaxCatchUp.
+	SkippedTicks uint64
+}
+
+type frameStats struct {
+	mu      sync.Mutex
+	samples [statsWindow]frameSample
+	n       int // Samples recorded, up to statsWindow
+	next    int // Ring index of the next sample
+	skipped uint64
+}
+
+type frameSample struct {
+	at   time.Time
+	tick uint64
+}
+
+func (st *frameStats) render(at time.Time, tick uint64) {
+	st.mu.Lock()
+	st.samples[st.next] = frameSample{at, tick}
+	st.next = (st.next + 1) % statsWindow
+	if st.n < statsWindow {
+		st.n++
+	}
+	st.mu.Unlock()
+}
+
+func (st *frameStats) skip(ticks int) {
+	st.mu.Lock()
+	st.skipped += uint64(ticks)
+	st.mu.Unlock()
+}
+
+// Stats returns frame time statistics over the Sim's last 128 renders. It may be called from any goroutine, such as
+// by a render op drawing an FPS counter.
+func (s *Sim) Stats() FrameStats {
+	s.fpsrw.RLock()
+	target := s.hz
+	if s.rfps > 0 {
+		target = s.rhz
+	}
+	s.fpsrw.RUnlock()
+
+	st := &s.stats
+	st.mu.Lock()
+	n := st.n
+	samples := make([]frameSample, n)
+	for i := range samples {
+		samples[i] = st.samples[(st.next-n+i+statsWindow)%statsWindow]
+	}
+	stats := FrameStats{SkippedTicks: st.skipped}
+	st.mu.Unlock()
+
+	if n < 2 {
+		return stats
+	}
+
+	times := make([]time.Duration, n-1)
+	for i := range times {
+		times[i] = samples[i+1].at.Sub(samples[i].at)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	limit := time.Duration(1.5 * target * float64(time.Second))
+	var total time.Duration
+	for _, d := range times {
+		total += d
+		if limit > 0 && d > limit {
+			stats.Dropped++
+		}
+	}
+
+	stats.Frames = len(times)
+	stats.Avg = total / time.Duration(len(times))
+	stats.Min = times[0]
+	stats.Max = times[len(times)-1]
+	stats.P99 = times[(len(times)*99-1)/100]
+	if secs := total.Seconds(); secs > 0 {
+		stats.RenderFPS = float64(len(times)) / secs
+		stats.TicksPerSecond = float64(samples[n-1].tick-samples[0].tick) / secs
+	}
+	return stats
+}
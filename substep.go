@@ -0,0 +1,36 @@
+package gt3
+
+import "time"
+
+// Substeps is an op that runs a physics op several times per sim tick with a proportionally smaller step, so that stiff
+// simulations can run at a higher internal rate than the Sim. It is typically run from the Frame op.
+//
+// Each substep runs Step and then, if set, Collide with an OpContext whose Step is the tick's step divided by N, whose
+// FrameTime and When are advanced to the start of the substep, and whose Substep is the substep's index.
+type Substeps struct {
+	N       int // Substeps per tick; values less than 1 are treated as 1
+	Step    Op
+	Collide Op
+}
+
+func (s Substeps) Do(step, frameTime float64, when time.Time) {
+	s.DoContext(OpContext{Step: step, FrameTime: frameTime, When: when})
+}
+
+func (s Substeps) DoContext(ctx OpContext) {
+	n := s.N
+	if n < 1 {
+		n = 1
+	}
+
+	sub := ctx
+	sub.Step = ctx.Step / float64(n)
+	for i := 0; i < n; i++ {
+		offset := float64(i) * sub.Step
+		sub.Substep = i
+		sub.FrameTime = ctx.FrameTime + offset
+		sub.When = ctx.When.Add(time.Duration(offset * float64(time.Second)))
+		RunOp(s.Step, sub)
+		RunOp(s.Collide, sub)
+	}
+}
@@ -0,0 +1,60 @@
+package gt3
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSyncOnMain(t *testing.T) {
+	const fps = 64
+	nop := OpFn(func(float64, float64, time.Time) {})
+
+	clock := NewManualClock(time.Unix(0, 0))
+	a, b := NewSim(fps, 0, nil), NewSim(fps, 0, nil)
+	a.SetClock(clock)
+	b.SetClock(clock)
+
+	syncErr := errors.New("Frame op not run")
+	a.Frame = OpFn(func(float64, float64, time.Time) {
+		syncErr = a.Sync(nop)
+	})
+	a.Start()
+
+	// Starting another Sim on another goroutine must not change which goroutine is a's main goroutine.
+	type result struct {
+		onA, onB     bool // Whether the goroutine is a's and b's main thread
+		syncB, syncA error
+	}
+	done := make(chan result)
+	go func() {
+		b.Start()
+		done <- result{
+			onA:   a.IsMainThread(),
+			onB:   b.IsMainThread(),
+			syncB: b.Sync(nop),
+			syncA: a.SyncTimeout(nop, 10*time.Millisecond),
+		}
+	}()
+	r := <-done
+	if r.onA || !r.onB {
+		t.Errorf("on b's goroutine: a.IsMainThread() = %t, b.IsMainThread() = %t; want false, true", r.onA, r.onB)
+	}
+	if !errors.Is(r.syncB, ErrSyncOnMain) {
+		t.Errorf("b.Sync() from b's goroutine = %v; want %v", r.syncB, ErrSyncOnMain)
+	}
+	if !errors.Is(r.syncA, ErrSyncTimeout) {
+		t.Errorf("a.SyncTimeout() from b's goroutine = %v; want %v", r.syncA, ErrSyncTimeout)
+	}
+	if b.IsMainThread() {
+		t.Error("b.IsMainThread() = true on a's goroutine; want false")
+	}
+
+	clock.AdvanceSeconds(1.0 / fps)
+	if err := a.Step(); err != nil {
+		t.Fatalf("Step() = %v; want nil", err)
+	}
+	if !errors.Is(syncErr, ErrSyncOnMain) {
+		t.Errorf("a.Sync() from a's Frame op = %v; want %v", syncErr, ErrSyncOnMain)
+	}
+}
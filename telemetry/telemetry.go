@@ -0,0 +1,82 @@
+// Package telemetry exports a Sim's loop iterations, frames, ops, and dispatched events as OpenTelemetry spans. Spans
+// carry the sim tick and timing as attributes and can be sent to any OTLP backend by configuring the TracerProvider
+// with an OTLP exporter:
+//
+//	exp, err := otlptracegrpc.New(ctx)
+//	// ...
+//	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
+//	sim.SetTracer(telemetry.New(tp))
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.spiff.io/gt3"
+)
+
+// InstrumentationName is the name of the tracer requested from a TracerProvider.
+const InstrumentationName = "go.spiff.io/gt3"
+
+// Tracer is a gt3.Tracer creating OpenTelemetry spans. Spans started on the main goroutine are nested under the
+// innermost open span, so ops appear under their frame and iteration. Spans started on other goroutines, such as
+// events dispatched off the main goroutine, are children of the Tracer's root context.
+type Tracer struct {
+	tracer trace.Tracer
+	root   context.Context
+	stack  []context.Context // Contexts of open main goroutine spans
+}
+
+var _ gt3.Tracer = (*Tracer)(nil)
+
+// New returns a Tracer creating spans with tp.
+func New(tp trace.TracerProvider) *Tracer {
+	return NewContext(context.Background(), tp)
+}
+
+// NewContext returns a Tracer creating spans with tp whose root spans are children of the span in ctx, if any.
+func NewContext(ctx context.Context, tp trace.TracerProvider) *Tracer {
+	return &Tracer{
+		tracer: tp.Tracer(InstrumentationName),
+		root:   ctx,
+	}
+}
+
+// StartSpan implements gt3.Tracer.
+func (t *Tracer) StartSpan(info gt3.SpanInfo) (end func()) {
+	attrs := []attribute.KeyValue{
+		attribute.Int64("gt3.tick", int64(info.Tick)),
+		attribute.Float64("gt3.step", info.Step),
+		attribute.Float64("gt3.frame_time", info.FrameTime),
+	}
+	if info.Op != "" {
+		attrs = append(attrs,
+			attribute.String("gt3.phase", info.Phase.String()),
+			attribute.String("gt3.op", info.Op))
+	}
+	if info.Event != "" {
+		attrs = append(attrs, attribute.String("gt3.event", info.Event))
+	}
+	if !info.When.IsZero() {
+		attrs = append(attrs, attribute.Int64("gt3.when_unix_nano", info.When.UnixNano()))
+	}
+	opt := trace.WithAttributes(attrs...)
+
+	if !info.Main {
+		_, span := t.tracer.Start(t.root, info.Name, opt)
+		return func() { span.End() }
+	}
+
+	parent := t.root
+	if n := len(t.stack); n > 0 {
+		parent = t.stack[n-1]
+	}
+	ctx, span := t.tracer.Start(parent, info.Name, opt)
+	t.stack = append(t.stack, ctx)
+	return func() {
+		t.stack = t.stack[:len(t.stack)-1]
+		span.End()
+	}
+}
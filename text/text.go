@@ -0,0 +1,321 @@
+// Package text draws text through a gfx.SpriteBatch using glyphs rasterized from a font.Face into a texture atlas.
+// Faces may be TrueType or OpenType fonts loaded with Parse or LoadFile, or bitmap faces such as basicfont.Face7x13
+// passed to NewFont.
+package text
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"strings"
+
+	"go.spiff.io/gt3/gfx"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// DefaultRunes are the runes rasterized by NewFont when none are given: printable ASCII and Latin-1.
+var DefaultRunes = runeRange(' ', '~') + runeRange('¡', 'ÿ')
+
+func runeRange(lo, hi rune) string {
+	var b strings.Builder
+	for r := lo; r <= hi; r++ {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Align is the horizontal alignment of lines of text.
+type Align int
+
+// Alignments.
+const (
+	AlignLeft   Align = iota // Lines start at x
+	AlignCenter              // Lines are centered on x
+	AlignRight               // Lines end at x
+)
+
+// Layout controls how Draw and Measure lay out text.
+type Layout struct {
+	// MaxWidth is the width at which lines are wrapped, between words. Words wider than MaxWidth are not broken. If
+	// MaxWidth is <= 0, lines are only broken at newlines.
+	MaxWidth float64
+	Align    Align
+	// LineSpacing scales the distance between lines. Zero is treated as 1.
+	LineSpacing float64
+}
+
+// Font is a texture atlas of a face's glyphs. A Font must only be used from the main goroutine.
+type Font struct {
+	face    font.Face
+	tex     *gfx.Texture
+	glyphs  map[rune]glyph
+	ascent  float64
+	descent float64
+	height  float64 // Line height
+}
+
+type glyph struct {
+	x, y, w, h     float64 // Bounds relative to the pen position on the baseline
+	u0, v0, u1, v1 float32
+	advance        float64
+}
+
+// atlasWidth is the width of glyph atlases. Their height is the smallest power of two that fits the glyphs.
+const atlasWidth = 512
+
+// Parse parses a TrueType or OpenType font and rasterizes its glyphs for runes at size pixels per em, as by NewFont.
+func Parse(ttf []byte, size float64, runes string) (*Font, error) {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	return NewFont(face, runes)
+}
+
+// LoadFile is the same as Parse, except that the font is read from a file.
+func LoadFile(path string, size float64, runes string) (*Font, error) {
+	ttf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(ttf, size, runes)
+}
+
+// NewFont rasterizes face's glyphs for runes, or DefaultRunes if runes is empty, into a texture atlas. Runes the face
+// has no glyph for are skipped, and are drawn as '?' if it has one. The Font keeps face for kerning. NewFont must be
+// called with a current GL context.
+func NewFont(face font.Face, runes string) (*Font, error) {
+	if runes == "" {
+		runes = DefaultRunes
+	}
+	m := face.Metrics()
+	f := &Font{
+		face:    face,
+		glyphs:  make(map[rune]glyph),
+		ascent:  fix(m.Ascent),
+		descent: fix(m.Descent),
+		height:  fix(m.Height),
+	}
+	if f.height == 0 {
+		f.height = f.ascent + f.descent
+	}
+
+	// Rasterize glyphs and pack them into shelves, with a pixel of padding between them to avoid bleeding.
+	type placed struct {
+		r        rune
+		dr       image.Rectangle
+		mask     *image.Alpha
+		atX, atY int
+		advance  fixed.Int26_6
+	}
+	var (
+		glyphs       []placed
+		x, y, shelfH = 1, 1, 0
+	)
+	for _, r := range runes {
+		if _, ok := f.glyphs[r]; ok {
+			continue
+		}
+		dr, mask, maskp, advance, ok := face.Glyph(fixed.Point26_6{}, r)
+		if !ok {
+			continue
+		}
+		f.glyphs[r] = glyph{}
+		w, h := dr.Dx(), dr.Dy()
+		// Faces may reuse their mask between calls to Glyph, so copy it.
+		copied := image.NewAlpha(image.Rect(0, 0, w, h))
+		draw.Draw(copied, copied.Rect, mask, maskp, draw.Src)
+		if x+w+1 > atlasWidth {
+			x, y, shelfH = 1, y+shelfH+1, 0
+		}
+		glyphs = append(glyphs, placed{r, dr, copied, x, y, advance})
+		x += w + 1
+		if h > shelfH {
+			shelfH = h
+		}
+	}
+	height := 1
+	for height < y+shelfH+1 {
+		height *= 2
+	}
+
+	atlas := image.NewNRGBA(image.Rect(0, 0, atlasWidth, height))
+	white := image.NewUniform(color.White)
+	aw, ah := float32(atlasWidth), float32(height)
+	for _, g := range glyphs {
+		w, h := g.dr.Dx(), g.dr.Dy()
+		if w > 0 && h > 0 {
+			dst := image.Rect(g.atX, g.atY, g.atX+w, g.atY+h)
+			draw.DrawMask(atlas, dst, white, image.Point{}, g.mask, image.Point{}, draw.Src)
+		}
+		f.glyphs[g.r] = glyph{
+			x:       float64(g.dr.Min.X),
+			y:       float64(g.dr.Min.Y),
+			w:       float64(w),
+			h:       float64(h),
+			u0:      float32(g.atX) / aw,
+			v0:      float32(g.atY) / ah,
+			u1:      float32(g.atX+w) / aw,
+			v1:      float32(g.atY+h) / ah,
+			advance: fix(g.advance),
+		}
+	}
+
+	tex, err := gfx.LoadTexture(atlas)
+	if err != nil {
+		return nil, err
+	}
+	f.tex = tex
+	return f, nil
+}
+
+func fix(x fixed.Int26_6) float64 {
+	return float64(x) / 64
+}
+
+// Texture returns the Font's glyph atlas.
+func (f *Font) Texture() *gfx.Texture {
+	return f.tex
+}
+
+// LineHeight returns the distance between baselines of consecutive lines, in pixels, for a LineSpacing of 1.
+func (f *Font) LineHeight() float64 {
+	return f.height
+}
+
+// Ascent returns the distance from the top of a line to its baseline, in pixels.
+func (f *Font) Ascent() float64 {
+	return f.ascent
+}
+
+func (f *Font) glyph(r rune) (glyph, bool) {
+	g, ok := f.glyphs[r]
+	if !ok {
+		g, ok = f.glyphs['?']
+	}
+	return g, ok
+}
+
+// kern returns the kerning adjustment between prev and r, in pixels.
+func (f *Font) kern(prev, r rune) float64 {
+	if prev < 0 {
+		return 0
+	}
+	return fix(f.face.Kern(prev, r))
+}
+
+// Width returns the width of a single line of text, in pixels.
+func (f *Font) Width(s string) float64 {
+	var w float64
+	prev := rune(-1)
+	for _, r := range s {
+		if g, ok := f.glyph(r); ok {
+			w += f.kern(prev, r) + g.advance
+		}
+		prev = r
+	}
+	return w
+}
+
+// Lines splits s into lines at newlines and, if layout.MaxWidth > 0, between words so that lines fit in it.
+func (f *Font) Lines(s string, layout Layout) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		if layout.MaxWidth <= 0 {
+			lines = append(lines, para)
+			continue
+		}
+		line := ""
+		for _, word := range strings.Fields(para) {
+			next := word
+			if line != "" {
+				next = line + " " + word
+			}
+			if line != "" && f.Width(next) > layout.MaxWidth {
+				lines = append(lines, line)
+				next = word
+			}
+			line = next
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (f *Font) lineHeight(layout Layout) float64 {
+	if layout.LineSpacing == 0 {
+		return f.height
+	}
+	return f.height * layout.LineSpacing
+}
+
+// Measure returns the size of s when drawn with layout.
+func (f *Font) Measure(s string, layout Layout) (w, h float64) {
+	lines := f.Lines(s, layout)
+	for _, line := range lines {
+		if lw := f.Width(line); lw > w {
+			w = lw
+		}
+	}
+	if n := len(lines); n > 0 {
+		h = float64(n-1)*f.lineHeight(layout) + f.ascent + f.descent
+	}
+	return w, h
+}
+
+// Draw queues s in b with the top of its first line at y and lines aligned to x as given by layout. Color is
+// non-premultiplied, and the zero value is treated as opaque white, as for gfx.Sprite. Glyphs are placed on whole
+// pixels to keep them sharp.
+func (f *Font) Draw(b *gfx.SpriteBatch, s string, x, y float64, c color.RGBA, layout Layout) {
+	baseline := y + f.ascent
+	for _, line := range f.Lines(s, layout) {
+		pen := x
+		switch layout.Align {
+		case AlignCenter:
+			pen -= f.Width(line) / 2
+		case AlignRight:
+			pen -= f.Width(line)
+		}
+		pen = float64(int(pen + 0.5))
+
+		prev := rune(-1)
+		for _, r := range line {
+			g, ok := f.glyph(r)
+			if !ok {
+				prev = r
+				continue
+			}
+			pen += f.kern(prev, r)
+			if g.w > 0 && g.h > 0 {
+				b.Draw(gfx.Sprite{
+					Texture: f.tex,
+					X:       float64(int(pen+0.5)) + g.x,
+					Y:       baseline + g.y,
+					Width:   g.w,
+					Height:  g.h,
+					U0:      g.u0,
+					V0:      g.v0,
+					U1:      g.u1,
+					V1:      g.v1,
+					Color:   c,
+				})
+			}
+			pen += g.advance
+			prev = r
+		}
+		baseline += f.lineHeight(layout)
+	}
+}
+
+// Delete deletes the Font's atlas texture and closes its face.
+func (f *Font) Delete() error {
+	f.tex.Delete()
+	return f.face.Close()
+}
@@ -0,0 +1,75 @@
+package gt3
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// mainGoroutine is the ID of the goroutine that initialized gt3, normally the program's main goroutine, which runs on
+// the main OS thread while package initialization is underway.
+var mainGoroutine uint64
+
+func init() {
+	mainGoroutine = goroutineID()
+}
+
+// IsMainThread reports whether the caller is running on the goroutine that initialized gt3 and, where the platform
+// allows checking, the main OS thread. Code run by a Sim should use Sim.IsMainThread instead, since a Sim's main
+// goroutine is the one that runs it.
+func IsMainThread() bool {
+	return goroutineID() == mainGoroutine && isMainOSThread()
+}
+
+// AssertMainThread panics if the caller is not running on the main thread, as reported by IsMainThread. It's meant to
+// guard code that calls GL or GLFW, which fail in obscure ways when called from other threads. Builds with the gt3debug
+// tag also assert automatically in NewWindow and SetEventCallbacks.
+func AssertMainThread() {
+	if !IsMainThread() {
+		panic(fmt.Sprintf("gt3: called from goroutine %d, not the main thread (goroutine %d)",
+			goroutineID(), mainGoroutine))
+	}
+}
+
+// IsMainThread reports whether the caller is running on the Sim's main goroutine, the one that called Run or Start,
+// and, if that goroutine started on the main OS thread and the platform allows checking, whether it still is. Before
+// the Sim is started, it's the same as the IsMainThread function. Each Sim has its own main goroutine, so Sims run in
+// lockstep or in parallel tests don't affect each other's checks.
+func (s *Sim) IsMainThread() bool {
+	if atomic.LoadUint64(&s.goid) == 0 {
+		return IsMainThread()
+	}
+	return s.onMainGoroutine() && (!s.osThread || isMainOSThread())
+}
+
+// AssertMainThread panics if the caller is not running on the Sim's main thread, as reported by Sim.IsMainThread.
+// Builds with the gt3debug tag also assert automatically before render phases and in Preroll.
+func (s *Sim) AssertMainThread() {
+	if !s.IsMainThread() {
+		panic(fmt.Sprintf("gt3: called from goroutine %d, not the Sim's main thread (goroutine %d)",
+			goroutineID(), atomic.LoadUint64(&s.goid)))
+	}
+}
+
+// onMainGoroutine reports whether the caller is running on the Sim's main goroutine, regardless of OS thread. Before
+// the Sim is started, that's the goroutine that initialized gt3.
+func (s *Sim) onMainGoroutine() bool {
+	goid := atomic.LoadUint64(&s.goid)
+	if goid == 0 {
+		goid = mainGoroutine
+	}
+	return goroutineID() == goid
+}
+
+// debugAssertMainThread calls AssertMainThread in builds with the gt3debug tag.
+func debugAssertMainThread() {
+	if debugChecks {
+		AssertMainThread()
+	}
+}
+
+// debugAssertMainThread calls s.AssertMainThread in builds with the gt3debug tag.
+func (s *Sim) debugAssertMainThread() {
+	if debugChecks {
+		s.AssertMainThread()
+	}
+}
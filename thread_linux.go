@@ -0,0 +1,8 @@
+package gt3
+
+import "syscall"
+
+// isMainOSThread reports whether the calling thread is the process's main thread, whose thread ID is the process ID.
+func isMainOSThread() bool {
+	return syscall.Gettid() == syscall.Getpid()
+}
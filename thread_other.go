@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gt3
+
+// isMainOSThread reports true, since the calling thread can't be cheaply checked on this platform.
+func isMainOSThread() bool {
+	return true
+}
@@ -0,0 +1,143 @@
+package gt3
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TickBus notifies subscribers of sim ticks. Subscriptions run on the main goroutine before the Frame op of the ticks
+// they're due on, in the order they were made, with the same OpContext as the Frame op except for its phase, which is
+// PhaseTick. Decoupling periodic systems from the Frame op this way lets each run at its own rate. A TickBus may be
+// subscribed to from any goroutine.
+type TickBus struct {
+	mu     sync.Mutex
+	subs   []*tickSub // Copy-on-write
+	nextID uint64
+}
+
+type tickSub struct {
+	id     uint64
+	every  uint64  // Run every N ticks, if > 0
+	offset uint64  // Tick the subscription was made at, for every
+	at     float64 // Run once at or after this sim time, if every == 0
+	period float64 // If > 0, run again every period seconds after at
+	delay  bool    // at is relative to the first tick seen since subscribing
+	op     Op
+	done   bool // One-shot subscription has run; accessed only on the main goroutine
+}
+
+// Ticks returns the Sim's tick bus.
+func (s *Sim) Ticks() *TickBus {
+	return &s.tickBus
+}
+
+func (b *TickBus) add(sub *tickSub) (cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	sub.id = b.nextID
+	subs := make([]*tickSub, len(b.subs), len(b.subs)+1)
+	copy(subs, b.subs)
+	b.subs = append(subs, sub)
+	return func() { b.remove(sub) }
+}
+
+func (b *TickBus) remove(sub *tickSub) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i := sort.Search(len(b.subs), func(i int) bool { return b.subs[i].id >= sub.id })
+	if i == len(b.subs) || b.subs[i] != sub {
+		return
+	}
+	subs := make([]*tickSub, 0, len(b.subs)-1)
+	subs = append(subs, b.subs[:i]...)
+	b.subs = append(subs, b.subs[i+1:]...)
+}
+
+// EveryTick runs op every tick until cancelled.
+func (b *TickBus) EveryTick(op Op) (cancel func()) {
+	return b.Every(1, op)
+}
+
+// Every runs op every n ticks, starting with the first tick after subscribing, until cancelled. Zero is treated as 1.
+func (b *TickBus) Every(n uint64, op Op) (cancel func()) {
+	if n == 0 {
+		n = 1
+	}
+	return b.add(&tickSub{every: n, op: op, offset: ^uint64(0)})
+}
+
+// At runs op once, before the first tick whose sim time is at or after seconds. Cancelling after it has run has no
+// effect.
+func (b *TickBus) At(seconds float64, op Op) (cancel func()) {
+	return b.add(&tickSub{at: seconds, op: op})
+}
+
+// After runs op once, on the first tick at least d of sim time after the tick following the call. Cancelling after it
+// has run has no effect.
+func (b *TickBus) After(d time.Duration, op Op) (cancel func()) {
+	return b.add(&tickSub{at: d.Seconds(), delay: true, op: op})
+}
+
+// Interval runs op every d of sim time, starting d after the tick following the call, until cancelled. If d is shorter
+// than the sim's step, op runs at most once per tick; if it's <= 0, op runs every tick.
+func (b *TickBus) Interval(d time.Duration, op Op) (cancel func()) {
+	p := d.Seconds()
+	if p <= 0 {
+		return b.EveryTick(op)
+	}
+	return b.add(&tickSub{at: p, period: p, delay: true, op: op})
+}
+
+// After runs op once during the first sim frame at least d of sim time from now, as by TickBus.After.
+func (s *Sim) After(d time.Duration, op Op) (cancel func()) {
+	return s.tickBus.After(d, op)
+}
+
+// Every runs op every d of sim time until cancelled, as by TickBus.Interval. Since timers run on sim time, they fire
+// on the same ticks every run and don't advance while the Sim is paused.
+func (s *Sim) Every(d time.Duration, op Op) (cancel func()) {
+	return s.tickBus.Interval(d, op)
+}
+
+// run runs the subscriptions due on the tick in ctx.
+func (b *TickBus) run(s *Sim, ctx OpContext) {
+	b.mu.Lock()
+	subs := b.subs
+	b.mu.Unlock()
+	if len(subs) == 0 {
+		return
+	}
+
+	// Sim time accumulates a step at a time, so allow for rounding when comparing it to due times.
+	now := ctx.FrameTime + ctx.Step*1e-3
+
+	tick := ctx.Frame.Tick
+	for _, sub := range subs {
+		if sub.delay {
+			sub.at += ctx.FrameTime
+			sub.delay = false
+		}
+		switch {
+		case sub.every > 0:
+			if sub.offset == ^uint64(0) {
+				// First tick seen since subscribing
+				sub.offset = tick
+			}
+			if (tick-sub.offset)%sub.every != 0 {
+				continue
+			}
+		case sub.done || now < sub.at:
+			continue
+		case sub.period > 0:
+			for sub.at <= now {
+				sub.at += sub.period
+			}
+		default:
+			sub.done = true
+			b.remove(sub)
+		}
+		s.runOp(sub.op, ctx)
+	}
+}
@@ -0,0 +1,66 @@
+package gt3
+
+import (
+	"fmt"
+	"time"
+)
+
+// Span names used by a Sim's Tracer.
+const (
+	SpanIteration = "gt3.iteration" // A loop iteration: PreFrame, every sim frame it runs, and rendering
+	SpanFrame     = "gt3.frame"     // A single sim tick: scheduled ops, tick subscriptions, and the Frame op
+	SpanEvent     = "gt3.event"     // An event dispatched by a Dispatcher with a Sim
+)
+
+// SpanInfo describes a span started by a Sim. Op spans are named "gt3." followed by their phase, such as "gt3.sched"
+// or "gt3.render".
+type SpanInfo struct {
+	Name  string
+	Tick  uint64
+	Phase Phase  // Set for op spans
+	Op    string // The op's type, set for op spans
+	Event string // The event's type, set for event spans
+	Main  bool   // Whether the span was started on the Sim's main goroutine; false only for some event spans
+
+	Step      float64
+	FrameTime float64
+	When      time.Time
+}
+
+// Tracer receives spans for parts of a Sim's loop. StartSpan is called at the start of a span and returns a function
+// ending it. Spans started on the main goroutine are strictly nested, so a Tracer may treat the most recently started
+// open span as the parent of a new one. Event spans may be started on other goroutines if events are dispatched from
+// them, as reported by SpanInfo.Main.
+type Tracer interface {
+	StartSpan(info SpanInfo) (end func())
+}
+
+// SetTracer sets the Tracer receiving spans for each loop iteration, sim frame, op, and event dispatched by a
+// Dispatcher whose Sim is s. A nil Tracer disables tracing. SetTracer must be called before Run.
+func (s *Sim) SetTracer(t Tracer) {
+	s.tracer = t
+}
+
+func (s *Sim) opSpan(op Op, ctx OpContext) func() {
+	return s.tracer.StartSpan(SpanInfo{
+		Name:      "gt3." + ctx.Frame.Phase.String(),
+		Tick:      ctx.Frame.Tick,
+		Phase:     ctx.Frame.Phase,
+		Op:        fmt.Sprintf("%T", op),
+		Main:      true,
+		Step:      ctx.Step,
+		FrameTime: ctx.FrameTime,
+		When:      ctx.When,
+	})
+}
+
+func (s *Sim) span(name string, hz, ft float64, rt time.Time) func() {
+	return s.tracer.StartSpan(SpanInfo{
+		Name:      name,
+		Tick:      s.Tick(),
+		Main:      true,
+		Step:      hz,
+		FrameTime: ft,
+		When:      rt,
+	})
+}
@@ -0,0 +1,49 @@
+package tray
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ErrNoNotifier is returned by Notify if the platform has no known command-line notifier.
+var ErrNoNotifier = errors.New("tray: no notification command available")
+
+// Notify shows a desktop notification using the platform's command-line notifier: notify-send on Linux and BSDs,
+// osascript on macOS, and PowerShell on Windows. It waits for the command to exit.
+func Notify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := "display notification " + strconv.Quote(message) + " with title " + strconv.Quote(title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", windowsToast+" "+psQuote(title)+" "+psQuote(message))
+	default:
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return ErrNoNotifier
+		}
+		cmd = exec.Command(path, "--", title, message)
+	}
+	return cmd.Run()
+}
+
+// psQuote quotes s as a PowerShell string literal.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// windowsToast shows a balloon tip with the title and message passed as arguments.
+const windowsToast = `& {
+param($title, $message)
+Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, $title, $message, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()
+}`
@@ -0,0 +1,161 @@
+// Package systray adapts fyne.io/systray as a tray.Backend, showing a tray icon and menu through StatusNotifierItem
+// over D-Bus on Linux and BSDs, NSStatusItem on macOS, and Shell_NotifyIcon on Windows.
+package systray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/png"
+	"runtime"
+	"sync"
+
+	"fyne.io/systray"
+
+	"go.spiff.io/gt3/tray"
+)
+
+// ErrClosed is returned by a Backend's methods after it's closed.
+var ErrClosed = errors.New("systray: backend closed")
+
+// Backend is a tray.Backend for the process's tray icon. There is one tray icon per process, so only one Backend may
+// be open at a time.
+type Backend struct {
+	end func()
+
+	mu     sync.Mutex
+	stop   chan struct{} // Closed to stop the current menu's click listeners
+	closed bool
+}
+
+var _ tray.Backend = (*Backend)(nil)
+
+// New shows a tray icon and returns a Backend for it. New must be called from the main goroutine after the first
+// window is created. On macOS and Windows, the icon's events are handled by the platform's event loop, which the Sim
+// runs while polling window events; on Linux and BSDs, the icon is served over D-Bus from its own goroutines.
+func New() *Backend {
+	start, end := systray.RunWithExternalLoop(nil, nil)
+	start()
+	return &Backend{end: end}
+}
+
+func (b *Backend) check() error {
+	if b.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// SetIcon sets the tray icon's image. The image is encoded as a PNG, wrapped in an ICO file on Windows.
+func (b *Backend) SetIcon(icon image.Image) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, icon); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	if runtime.GOOS == "windows" {
+		data = pngICO(data, icon.Bounds().Size())
+	}
+	systray.SetIcon(data)
+	return nil
+}
+
+// pngICO returns an ICO file holding a single PNG-compressed image, as supported since Windows Vista.
+func pngICO(data []byte, size image.Point) []byte {
+	dim := func(n int) uint8 {
+		if n >= 256 {
+			return 0 // 0 means 256 pixels
+		}
+		return uint8(n)
+	}
+	var buf bytes.Buffer
+	header := struct {
+		Reserved, Type, Count uint16
+		Width, Height         uint8
+		Colors, Reserved2     uint8
+		Planes, BitCount      uint16
+		Size, Offset          uint32
+	}{
+		Type:     1,
+		Count:    1,
+		Width:    dim(size.X),
+		Height:   dim(size.Y),
+		Planes:   1,
+		BitCount: 32,
+		Size:     uint32(len(data)),
+		Offset:   22, // Size of the header and one directory entry
+	}
+	binary.Write(&buf, binary.LittleEndian, header)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// SetTooltip sets the tray icon's tooltip.
+func (b *Backend) SetTooltip(tooltip string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(); err != nil {
+		return err
+	}
+	systray.SetTooltip(tooltip)
+	return nil
+}
+
+// SetMenu replaces the tray icon's menu. clicked is called from a listener goroutine.
+func (b *Backend) SetMenu(items []tray.MenuItem, clicked func(index int)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(); err != nil {
+		return err
+	}
+	if b.stop != nil {
+		close(b.stop)
+	}
+	systray.ResetMenu()
+	stop := make(chan struct{})
+	b.stop = stop
+	for i, item := range items {
+		mi := systray.AddMenuItem(item.Label, "")
+		if item.Disabled {
+			mi.Disable()
+		}
+		go func(i int, ch <-chan struct{}) {
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ch:
+					clicked(i)
+				}
+			}
+		}(i, mi.ClickedCh)
+	}
+	return nil
+}
+
+// Notify shows a desktop notification with tray.Notify, since the tray icon can't show notifications itself.
+func (b *Backend) Notify(title, message string) error {
+	return tray.Notify(title, message)
+}
+
+// Close removes the tray icon.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(); err != nil {
+		return err
+	}
+	b.closed = true
+	if b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+	b.end()
+	return nil
+}
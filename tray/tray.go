@@ -0,0 +1,138 @@
+// Package tray lets gt3 applications minimize to the system tray and post desktop notifications, keeping their Sim
+// running in the background at a reduced render rate.
+//
+// Tray icons and menus are provided by a Backend, since they require platform libraries that the tray package doesn't
+// depend on and that usually want to own the main thread themselves. The systray subpackage provides a Backend using
+// fyne.io/systray. Notifications fall back to the platform's command-line notifier if no Backend is set.
+package tray
+
+import (
+	"image"
+	"time"
+
+	"go.spiff.io/gt3"
+)
+
+// MenuItem is an item in the tray icon's menu. OnClick runs on the main goroutine via Sim.Sched.
+type MenuItem struct {
+	Label    string
+	Disabled bool
+	OnClick  gt3.Op
+}
+
+// Backend displays a tray icon and its menu. Its methods may be called from any goroutine.
+type Backend interface {
+	SetIcon(icon image.Image) error
+	SetTooltip(tooltip string) error
+	// SetMenu replaces the icon's menu. The backend calls clicked with the index of an item when it's chosen.
+	SetMenu(items []MenuItem, clicked func(index int)) error
+	Notify(title, message string) error
+	Close() error
+}
+
+// Tray manages a tray icon for a Sim.
+type Tray struct {
+	// BackgroundFPS is the render FPS used while minimized to the tray. Defaults to 1.
+	BackgroundFPS int
+
+	sim     *gt3.Sim
+	backend Backend
+	items   []MenuItem
+
+	minimized bool
+	hidden    []*gt3.Window
+	prevFPS   int
+}
+
+// New returns a Tray for sim. backend may be nil, in which case only notifications are supported.
+func New(sim *gt3.Sim, backend Backend) *Tray {
+	return &Tray{BackgroundFPS: 1, sim: sim, backend: backend}
+}
+
+// SetIcon sets the tray icon's image and tooltip.
+func (t *Tray) SetIcon(icon image.Image, tooltip string) error {
+	if t.backend == nil {
+		return nil
+	}
+	if err := t.backend.SetIcon(icon); err != nil {
+		return err
+	}
+	return t.backend.SetTooltip(tooltip)
+}
+
+// SetMenu replaces the tray icon's menu.
+func (t *Tray) SetMenu(items ...MenuItem) error {
+	if t.backend == nil {
+		return nil
+	}
+	items = append(items[:0:0], items...)
+	return t.backend.SetMenu(items, func(i int) {
+		if i < 0 || i >= len(items) || items[i].Disabled || items[i].OnClick == nil {
+			return
+		}
+		t.sim.Sched(items[i].OnClick)
+	})
+}
+
+// Notify shows a desktop notification.
+func (t *Tray) Notify(title, message string) error {
+	if t.backend == nil {
+		return Notify(title, message)
+	}
+	return t.backend.Notify(title, message)
+}
+
+// hider is implemented by native windows that can be hidden. *glfw.Window implements hider.
+type hider interface {
+	Hide()
+	Show()
+}
+
+// Minimize hides the given windows and lowers the Sim's render FPS to BackgroundFPS. Windows whose native window can't
+// be hidden are left as they are. Minimize does nothing if already minimized. It must be called from the main
+// goroutine.
+func (t *Tray) Minimize(windows ...*gt3.Window) {
+	if t.minimized {
+		return
+	}
+	t.minimized = true
+	for _, w := range windows {
+		if h, ok := w.Native().(hider); ok {
+			h.Hide()
+			t.hidden = append(t.hidden, w)
+		}
+	}
+	t.prevFPS = t.sim.SetRenderFPS(t.BackgroundFPS)
+}
+
+// Restore shows the windows hidden by Minimize and restores the Sim's render FPS. It must be called from the main
+// goroutine.
+func (t *Tray) Restore() {
+	if !t.minimized {
+		return
+	}
+	t.minimized = false
+	for _, w := range t.hidden {
+		w.Native().(hider).Show()
+	}
+	t.hidden = nil
+	t.sim.SetRenderFPS(t.prevFPS)
+}
+
+// Minimized reports whether the windows are minimized to the tray.
+func (t *Tray) Minimized() bool {
+	return t.minimized
+}
+
+// RestoreItem returns a menu item that restores minimized windows.
+func (t *Tray) RestoreItem(label string) MenuItem {
+	return MenuItem{Label: label, OnClick: gt3.OpFn(func(float64, float64, time.Time) { t.Restore() })}
+}
+
+// Close removes the tray icon.
+func (t *Tray) Close() error {
+	if t.backend == nil {
+		return nil
+	}
+	return t.backend.Close()
+}
@@ -0,0 +1,47 @@
+package gt3
+
+// WindowKey identifies a value of type T associated with windows. Keys are compared by identity, so two keys created
+// with the same name are distinct.
+type WindowKey[T any] struct {
+	name string
+}
+
+// NewWindowKey returns a new key for window data of type T. The name is only used for debugging.
+func NewWindowKey[T any](name string) *WindowKey[T] {
+	return &WindowKey[T]{name: name}
+}
+
+func (k *WindowKey[T]) String() string { return k.name }
+
+// SetWindowData associates v with w under key k, replacing any previous value. Window data is dropped when the window
+// is destroyed with Destroy or released with ReleaseGLFWWindow. SetWindowData may be called from any goroutine.
+func SetWindowData[T any](w *Window, k *WindowKey[T], v T) {
+	w.dataMu.Lock()
+	defer w.dataMu.Unlock()
+	if w.data == nil {
+		w.data = make(map[interface{}]interface{})
+	}
+	w.data[k] = v
+}
+
+// WindowData returns the value associated with w under key k and whether one was set.
+func WindowData[T any](w *Window, k *WindowKey[T]) (v T, ok bool) {
+	w.dataMu.Lock()
+	defer w.dataMu.Unlock()
+	v, ok = w.data[k].(T)
+	return v, ok
+}
+
+// DeleteWindowData removes the value associated with w under key k.
+func DeleteWindowData[T any](w *Window, k *WindowKey[T]) {
+	w.dataMu.Lock()
+	defer w.dataMu.Unlock()
+	delete(w.data, k)
+}
+
+// clearData drops all of w's window data.
+func (w *Window) clearData() {
+	w.dataMu.Lock()
+	w.data = nil
+	w.dataMu.Unlock()
+}
@@ -0,0 +1,113 @@
+package gt3
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// WatchdogFunc is called by a Sim's watchdog when a single loop iteration has been running for longer than the
+// watchdog threshold. The stack is the trace of the goroutine running the Sim at the time the stall was detected.
+// WatchdogFunc is called from the watchdog goroutine, not the main goroutine.
+type WatchdogFunc func(stalled time.Duration, stack []byte)
+
+// watchdog tracks the wall time at which the current loop iteration began.
+type watchdog struct {
+	start int64  // UnixNano of the current iteration's start, or 0 if idle
+	iter  uint64 // Incremented on every iteration start
+
+	threshold time.Duration
+	fn        WatchdogFunc
+	goid      uint64
+}
+
+// SetWatchdog configures a watchdog goroutine to run alongside the Sim. If a single loop iteration takes longer than
+// threshold, fn is called once for that iteration with a stack dump of the main goroutine. A threshold <= 0 or nil fn
+// disables the watchdog. SetWatchdog must be called before Run.
+func (s *Sim) SetWatchdog(threshold time.Duration, fn WatchdogFunc) {
+	if threshold <= 0 || fn == nil {
+		s.wd = nil
+		return
+	}
+	s.wd = &watchdog{threshold: threshold, fn: fn}
+}
+
+func (w *watchdog) begin() {
+	atomic.AddUint64(&w.iter, 1)
+	atomic.StoreInt64(&w.start, time.Now().UnixNano())
+}
+
+func (w *watchdog) end() {
+	atomic.StoreInt64(&w.start, 0)
+}
+
+func (w *watchdog) run(done <-chan struct{}) {
+	interval := w.threshold / 4
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var reported uint64
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			start := atomic.LoadInt64(&w.start)
+			iter := atomic.LoadUint64(&w.iter)
+			if start == 0 || iter == reported {
+				continue
+			}
+
+			stalled := now.Sub(time.Unix(0, start))
+			if stalled < w.threshold {
+				continue
+			}
+
+			reported = iter
+			w.fn(stalled, goroutineStack(w.goid))
+		}
+	}
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns the ID of the calling goroutine, as it appears in stack traces.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, goroutinePrefix)
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// goroutineStack returns the stack trace of the goroutine with the given ID. If the goroutine cannot be found, the
+// stacks of all goroutines are returned.
+func goroutineStack(id uint64) []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	header := strconv.AppendUint(append([]byte(nil), goroutinePrefix...), id, 10)
+	header = append(header, ' ')
+	for _, trace := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(trace, header) {
+			return trace
+		}
+	}
+	return buf
+}
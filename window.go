@@ -0,0 +1,125 @@
+package gt3
+
+import "sync"
+
+// Window is a backend-independent handle to a window. Events carry a *Window identifying the window they were sent to.
+type Window struct {
+	native interface{}
+
+	framebuffer FramebufferConfig // Requested framebuffer configuration
+	events      Dispatcher
+
+	dataMu sync.Mutex
+	data   map[interface{}]interface{} // Keyed by *WindowKey[T]
+}
+
+// WrapWindow returns a new Window for a backend's native window handle. Backends should return the same *Window for
+// a given native window so that Windows can be compared and used as map keys.
+func WrapWindow(native interface{}) *Window {
+	return &Window{native: native}
+}
+
+// Native returns the backend's native window handle for w.
+func (w *Window) Native() interface{} {
+	if w == nil {
+		return nil
+	}
+	return w.native
+}
+
+// RequestedFramebuffer returns the framebuffer configuration requested when the window was created by NewWindow. For
+// other windows, it returns the zero FramebufferConfig.
+func (w *Window) RequestedFramebuffer() FramebufferConfig {
+	return w.framebuffer
+}
+
+// Events returns w's Dispatcher. NewWindow's Events option routes window events to it; for other windows, pass it to
+// SetEventCallbacks or an equivalent event source.
+func (w *Window) Events() *Dispatcher {
+	return &w.events
+}
+
+// Destroyer is implemented by native windows that can be destroyed. *glfw.Window implements Destroyer.
+type Destroyer interface {
+	Destroy()
+}
+
+// Destroy destroys w's native window, if it implements Destroyer, and drops w's window data. GLFW windows are also
+// released as by ReleaseGLFWWindow. Destroy must be called from the main goroutine.
+func (w *Window) Destroy() {
+	debugAssertMainThread()
+	releaseNative(w)
+	if d, ok := w.Native().(Destroyer); ok {
+		d.Destroy()
+	}
+	w.clearData()
+}
+
+// AttentionRequester is implemented by native windows that can request the user's attention. *glfw.Window implements
+// AttentionRequester.
+type AttentionRequester interface {
+	RequestAttention()
+}
+
+// RequestAttention requests the user's attention to w, such as by flashing its taskbar entry, without focusing it. It
+// does nothing if w's native window does not implement AttentionRequester. It must be called from the main goroutine.
+func (w *Window) RequestAttention() {
+	if ar, ok := w.Native().(AttentionRequester); ok {
+		ar.RequestAttention()
+	}
+}
+
+// RenderContext is implemented by native windows that own a rendering context. *glfw.Window implements
+// RenderContext.
+type RenderContext interface {
+	MakeContextCurrent()
+	SwapBuffers()
+}
+
+// MakeContextCurrent makes w's rendering context current on the calling thread. It does nothing if w's native window
+// does not implement RenderContext.
+func (w *Window) MakeContextCurrent() {
+	if rc, ok := w.Native().(RenderContext); ok {
+		rc.MakeContextCurrent()
+	}
+}
+
+// SwapBuffers swaps w's front and back buffers. It does nothing if w's native window does not implement
+// RenderContext.
+func (w *Window) SwapBuffers() {
+	if rc, ok := w.Native().(RenderContext); ok {
+		rc.SwapBuffers()
+	}
+}
+
+// WindowState is a snapshot of a window's attributes. Sizes and positions are in screen coordinates except for the
+// framebuffer size, which is in pixels.
+type WindowState struct {
+	Width, Height                       int
+	FramebufferWidth, FramebufferHeight int
+	X, Y                                int
+	Focused                             bool
+	Iconified                           bool
+	Maximized                           bool
+	// ContentScale is the platform's UI scale for the window, such as 2 on high-DPI displays.
+	ContentScale float64
+	// Monitor is the name of the monitor the window is fullscreen on, or "" if it's windowed.
+	Monitor string
+}
+
+// StateWindow is implemented by native windows that can report their state.
+type StateWindow interface {
+	WindowState() WindowState
+}
+
+// State returns a snapshot of w's attributes. It must be called from the main goroutine. It returns the zero
+// WindowState if w's native window can't report its state.
+func (w *Window) State() WindowState {
+	if state, ok := nativeState(w); ok {
+		return state
+	}
+	if sw, ok := w.Native().(StateWindow); ok {
+		return sw.WindowState()
+	}
+	return WindowState{}
+}
@@ -0,0 +1,178 @@
+//go:build !android && !ios
+
+package gt3
+
+import (
+	"errors"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+	return glfw.False
+}
+
+// WindowConfig holds the configuration used by NewWindow. It is modified by WindowOptions.
+type WindowConfig struct {
+	Framebuffer FramebufferConfig
+
+	hints      []windowHint
+	fullscreen bool
+	monitor    *glfw.Monitor // nil for the primary monitor, if fullscreen
+	vsync      int           // Swap interval + 1, or 0 to leave it unset
+	events     EventMask     // Event types to route to the window's Dispatcher
+}
+
+type windowHint struct {
+	hint  glfw.Hint
+	value int
+}
+
+// Hint sets a GLFW window hint. Later hints for the same glfw.Hint replace earlier ones.
+func (c *WindowConfig) Hint(hint glfw.Hint, value int) {
+	for i := range c.hints {
+		if c.hints[i].hint == hint {
+			c.hints[i].value = value
+			return
+		}
+	}
+	c.hints = append(c.hints, windowHint{hint, value})
+}
+
+// ErrNoMonitor is returned by NewWindow when a fullscreen window is requested on the primary monitor and there is
+// none.
+var ErrNoMonitor = errors.New("gt3: no monitor for fullscreen window")
+
+// WindowOption configures a window created by NewWindow.
+type WindowOption func(*WindowConfig)
+
+// SRGB requests an sRGB-capable default framebuffer. Rendering is only gamma-corrected while GL_FRAMEBUFFER_SRGB is
+// enabled; see gfx.WithSRGB.
+func SRGB() WindowOption {
+	return func(c *WindowConfig) { c.Framebuffer.SRGB = true }
+}
+
+// Framebuffer sets the requested default framebuffer configuration, such as one of the Framebuffer presets. It
+// replaces the effect of any earlier SRGB or Samples options.
+func Framebuffer(fb FramebufferConfig) WindowOption {
+	return func(c *WindowConfig) { c.Framebuffer = fb }
+}
+
+// Samples sets the number of MSAA samples per pixel for the default framebuffer.
+func Samples(n int) WindowOption {
+	return func(c *WindowConfig) { c.Framebuffer.Samples = n }
+}
+
+// ContextVersion requests a GL context of at least the given version.
+func ContextVersion(major, minor int) WindowOption {
+	return func(c *WindowConfig) {
+		c.Hint(glfw.ContextVersionMajor, major)
+		c.Hint(glfw.ContextVersionMinor, minor)
+	}
+}
+
+// CoreProfile requests a forward-compatible core profile context, as required for GL 3.2 and later on macOS.
+func CoreProfile() WindowOption {
+	return func(c *WindowConfig) {
+		c.Hint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+		c.Hint(glfw.OpenGLForwardCompatible, glfw.True)
+	}
+}
+
+// Resizable sets whether the user can resize the window. Windows are resizable by default.
+func Resizable(resizable bool) WindowOption {
+	return func(c *WindowConfig) { c.Hint(glfw.Resizable, glfwBool(resizable)) }
+}
+
+// VSync sets whether buffer swaps wait for vertical sync. Setting it makes the new window's context current on the
+// calling thread; otherwise, the swap interval is left as the driver sets it.
+func VSync(enabled bool) WindowOption {
+	return func(c *WindowConfig) {
+		c.vsync = 1
+		if enabled {
+			c.vsync = 2
+		}
+	}
+}
+
+// Fullscreen creates the window fullscreen on monitor, or on the primary monitor if monitor is nil. If NewWindow's
+// width or height is <= 0, the monitor's current video mode is used.
+func Fullscreen(monitor *glfw.Monitor) WindowOption {
+	return func(c *WindowConfig) { c.fullscreen, c.monitor = true, monitor }
+}
+
+// HighDPI sets whether the window is DPI-aware: when enabled, its size is scaled by its monitor's content scale where
+// the platform doesn't do so itself, and its framebuffer uses the full resolution of Retina displays on macOS. Use
+// WindowState.ContentScale or ContentScaleEvent to scale UI accordingly.
+func HighDPI(enabled bool) WindowOption {
+	return func(c *WindowConfig) {
+		c.Hint(glfw.ScaleToMonitor, glfwBool(enabled))
+		c.Hint(glfw.CocoaRetinaFramebuffer, glfwBool(enabled))
+	}
+}
+
+// Events routes the window's events of the types in mask to its Dispatcher, as returned by Window.Events, by passing
+// it to SetEventMask.
+func Events(mask EventMask) WindowOption {
+	return func(c *WindowConfig) { c.events |= mask }
+}
+
+// NewWindow creates a GLFW window with the given title, size in screen coordinates, and options. Window hints not set
+// by options use GLFW's defaults. NewWindow must be called from the main thread after glfw.Init.
+//
+// The driver may grant a different framebuffer than requested. The requested configuration is available from the
+// Window's RequestedFramebuffer method, and gfx.ValidateFramebuffer compares it against the effective configuration.
+func NewWindow(title string, width, height int, opts ...WindowOption) (*Window, error) {
+	debugAssertMainThread()
+	conf := WindowConfig{Framebuffer: FramebufferDefault}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	glfw.DefaultWindowHints()
+	fb := conf.Framebuffer
+	glfw.WindowHint(glfw.Samples, fb.Samples)
+	glfw.WindowHint(glfw.DepthBits, fb.DepthBits)
+	glfw.WindowHint(glfw.StencilBits, fb.StencilBits)
+	glfw.WindowHint(glfw.DoubleBuffer, glfwBool(fb.DoubleBuffer))
+	glfw.WindowHint(glfw.SRGBCapable, glfwBool(fb.SRGB))
+	for _, h := range conf.hints {
+		glfw.WindowHint(h.hint, h.value)
+	}
+
+	var monitor *glfw.Monitor
+	if conf.fullscreen {
+		monitor = conf.monitor
+		if monitor == nil {
+			monitor = glfw.GetPrimaryMonitor()
+		}
+		if monitor == nil {
+			return nil, ErrNoMonitor
+		}
+		if mode := monitor.GetVideoMode(); mode != nil {
+			if width <= 0 || height <= 0 {
+				width, height = mode.Width, mode.Height
+			}
+			glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+		}
+	}
+
+	w, err := glfw.CreateWindow(width, height, title, monitor, nil)
+	if err != nil {
+		return nil, err
+	}
+	if conf.vsync > 0 {
+		w.MakeContextCurrent()
+		glfw.SwapInterval(conf.vsync - 1)
+	}
+
+	wnd := GLFWWindow(w)
+	wnd.framebuffer = fb
+	if conf.events != 0 {
+		SetEventMask(w, &wnd.events, conf.events)
+	}
+	return wnd, nil
+}
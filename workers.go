@@ -0,0 +1,119 @@
+package gt3
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// workerPool is a lazily started pool of goroutines running submitted functions. Submission never blocks: if no worker
+// is idle, trySubmit fails and the caller runs the work itself, so ops that use the pool from a worker can't deadlock.
+type workerPool struct {
+	n    int // Number of workers; GOMAXPROCS if <= 0
+	once sync.Once
+	jobs chan func()
+}
+
+// defaultPool is used by ops run outside of a Sim.
+var defaultPool workerPool
+
+func (p *workerPool) start() {
+	n := p.n
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	p.jobs = make(chan func())
+	for i := 0; i < n; i++ {
+		go func() {
+			for fn := range p.jobs {
+				fn()
+			}
+		}()
+	}
+}
+
+// trySubmit runs fn on an idle worker and reports whether one was available.
+func (p *workerPool) trySubmit(fn func()) bool {
+	p.once.Do(p.start)
+	select {
+	case p.jobs <- fn:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetWorkers sets the number of goroutines in the Sim's worker pool, used by ParallelFor and OpGraph. It defaults to
+// GOMAXPROCS and must be called before the Sim runs.
+func (s *Sim) SetWorkers(n int) {
+	s.pool.n = n
+}
+
+func (ctx OpContext) pool() *workerPool {
+	if ctx.sim != nil {
+		return &ctx.sim.pool
+	}
+	return &defaultPool
+}
+
+// ParallelFor calls fn for every i in [0, n), splitting the range into chunks run on the Sim's worker pool and the
+// calling goroutine. It returns once every call has returned. If fn panics, the first panic is re-raised on the calling
+// goroutine after the join as a *PanicError, so a panicking system in a Frame op still stops the Sim on the main
+// goroutine.
+//
+// fn must be safe to call concurrently for different i. ctx's Arena must not be used from fn.
+func (ctx OpContext) ParallelFor(n int, fn func(i int)) {
+	if n <= 0 {
+		return
+	}
+
+	pool := ctx.pool()
+	chunks := runtime.GOMAXPROCS(0) * 4
+	if chunks > n {
+		chunks = n
+	}
+	size := (n + chunks - 1) / chunks
+	chunks = (n + size - 1) / size
+
+	var (
+		next  int64
+		wg    sync.WaitGroup
+		once  sync.Once
+		fault *PanicError
+	)
+	wg.Add(chunks)
+	run := func() {
+		for {
+			c := int(atomic.AddInt64(&next, 1) - 1)
+			if c >= chunks {
+				return
+			}
+			func() {
+				defer wg.Done()
+				defer func() {
+					if v := recover(); v != nil {
+						stack := debug.Stack()
+						once.Do(func() { fault = &PanicError{Op: "ParallelFor", Value: v, Stack: stack} })
+					}
+				}()
+				end := (c + 1) * size
+				if end > n {
+					end = n
+				}
+				for i := c * size; i < end; i++ {
+					fn(i)
+				}
+			}()
+		}
+	}
+
+	for i := 1; i < chunks && pool.trySubmit(run); i++ {
+	}
+	run()
+	wg.Wait()
+
+	if fault != nil {
+		panic(fault)
+	}
+}
@@ -0,0 +1,51 @@
+package gt3
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelFor(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 1000} {
+		calls := make([]int32, n)
+		OpContext{}.ParallelFor(n, func(i int) { atomic.AddInt32(&calls[i], 1) })
+		for i, c := range calls {
+			if c != 1 {
+				t.Errorf("n=%d: fn(%d) called %d times; want 1", n, i, c)
+			}
+		}
+	}
+}
+
+func TestParallelForPanic(t *testing.T) {
+	errFn := errors.New("fn failed")
+	calls := make([]int32, 1000)
+	v := func() (v interface{}) {
+		defer func() { v = recover() }()
+		OpContext{}.ParallelFor(1000, func(i int) {
+			atomic.AddInt32(&calls[i], 1)
+			if i == 500 {
+				panic(errFn)
+			}
+		})
+		return nil
+	}()
+
+	err, ok := v.(*PanicError)
+	if !ok {
+		t.Fatalf("ParallelFor panicked with %#v; want a *PanicError", v)
+	}
+	if err.Op != "ParallelFor" {
+		t.Errorf("Op = %q; want %q", err.Op, "ParallelFor")
+	}
+	if !errors.Is(err, errFn) {
+		t.Errorf("errors.Is(%v, %v) = false; want true", err, errFn)
+	}
+	// Chunks before the panicking one still run to completion before the panic is re-raised.
+	for i, c := range calls[:500] {
+		if c != 1 {
+			t.Errorf("fn(%d) called %d times; want 1", i, c)
+		}
+	}
+}